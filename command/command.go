@@ -9,12 +9,16 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime/trace"
+	"strings"
 	"syscall"
 	"time"
 
+	"buddin.us/eolian/dashboard"
 	"buddin.us/eolian/dsp"
 	"buddin.us/eolian/engine"
-	"buddin.us/eolian/lua"           // Register standard modules
+	"buddin.us/eolian/lua" // Register standard modules
+	"buddin.us/eolian/lua/instrument"
+	"buddin.us/eolian/module"
 	_ "buddin.us/eolian/module/midi" // Register MIDI modules
 	_ "buddin.us/eolian/module/osc"  // Register OSC modules
 )
@@ -25,20 +29,66 @@ func Run(args []string) error {
 		device             int
 		seed               int64
 		writeTrace, norepl bool
+		profile, watch     bool
+		sandbox            bool
+		sandboxSteps       int64
+		sandboxMemory      uint64
+		describe           string
+		httpAddr           string
+		plugins            string
+		listen             string
+		listenToken        string
 		frameSize          int
+		maxBufferSize      int
+		controlRate        int
+		fade               float64
 	)
 
 	set := flag.NewFlagSet("eolian", flag.ContinueOnError)
 	set.IntVar(&device, "output", 1, "output device")
 	set.Int64Var(&seed, "seed", 0, "random seed")
 	set.IntVar(&frameSize, "framesize", 256, "frame size")
+	set.IntVar(&maxBufferSize, "max-buffer", 0, "let the audio buffer grow up to this many frames when xruns are frequent (0 disables)")
+	set.IntVar(&controlRate, "control-rate", 1, "compute modulation modules (envelopes, sequencers, random sources and the like; see -describe) once every N samples")
+	set.Float64Var(&fade, "fade", 0, "crossfade length (ms) applied when repatching inputs")
 	set.BoolVar(&writeTrace, "trace", false, "dump go trace tool information to trace.out")
 	set.BoolVar(&norepl, "no-repl", false, "run without the REPL")
+	set.BoolVar(&watch, "watch", false, "reload the rack when its files change")
+	set.BoolVar(&sandbox, "sandbox", false, "load an untrusted rack: confine its files to the rack's directory and limit the work it may do")
+	set.Int64Var(&sandboxSteps, "sandbox-steps", 50000000, "loop iterations and function calls a sandboxed rack may take to load, build or patch (0 disables)")
+	set.Uint64Var(&sandboxMemory, "sandbox-memory", 256, "megabytes a sandboxed rack may allocate to load, build or patch (0 disables)")
+	set.BoolVar(&profile, "profile", false, "measure the CPU time spent by each module")
+	set.StringVar(&describe, "describe", "", "print the description of a module type and exit")
+	set.StringVar(&plugins, "modules", "", "comma-separated Go plugins (or directories of .so files) that register extra modules")
+	set.StringVar(&listen, "listen", "", "evaluate Lua sent by editors on this address (e.g. unix:/tmp/eolian.sock or :7777)")
+	set.StringVar(&listenToken, "listen-token", "", "token editors must send with each request to -listen (TCP addresses get a random one if empty)")
+	set.StringVar(&httpAddr, "http", "", "serve a dashboard on this address (e.g. :8080); binds to localhost unless a host is given")
 	if err := set.Parse(args); err != nil {
 		return err
 	}
 
+	if plugins != "" {
+		if err := module.LoadPlugins(strings.Split(plugins, ",")); err != nil {
+			return err
+		}
+	}
+
+	if describe != "" {
+		help, err := module.Help(describe)
+		if err != nil {
+			return err
+		}
+		fmt.Print(help)
+		return nil
+	}
+
 	dsp.FrameSize = frameSize
+	if err := module.CheckControlRate(controlRate); err != nil {
+		return err
+	}
+	module.DefaultFade = int(dsp.Duration(fade).Value())
+	module.DefaultControlRate = controlRate
+	module.EnableProfiling(profile)
 
 	if writeTrace {
 		f, err := os.OpenFile("trace.out", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
@@ -59,11 +109,13 @@ func Run(args []string) error {
 	}
 	fmt.Println("Seed:", seed)
 	rand.Seed(seed)
+	module.SetSeed(seed)
 
 	e, err := engine.New(device)
 	if err != nil {
 		return err
 	}
+	e.SetMaxBufferSize(maxBufferSize)
 	go e.Run()
 	go func() {
 		for err := range e.Errors() {
@@ -71,10 +123,38 @@ func Run(args []string) error {
 		}
 	}()
 
-	vm, err := lua.NewVM(e, &e.Mutex)
+	if httpAddr != "" {
+		go func() {
+			if err := dashboard.New(e, &e.IO).ListenAndServe(httpAddr); err != nil {
+				fmt.Println("dashboard error:", err)
+			}
+		}()
+	}
+
+	vm, err := lua.NewVM(e, e)
 	if err != nil {
 		return err
 	}
+	go vm.Schedule(5*time.Millisecond, nil)
+
+	if listen != "" {
+		l, err := lua.Listen(listen)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		if listenToken == "" && l.Addr().Network() == "tcp" {
+			if listenToken, err = lua.NewToken(); err != nil {
+				return err
+			}
+		}
+		if listenToken != "" {
+			fmt.Printf("Listening: %s (token %s)\n", l.Addr(), listenToken)
+		} else {
+			fmt.Println("Listening:", l.Addr())
+		}
+		go vm.Serve(l, listenToken)
+	}
 
 	if len(set.Args()) > 0 {
 		path := set.Arg(0)
@@ -88,9 +168,22 @@ func Run(args []string) error {
 			path = filepath.Join(path, "init.lua")
 		}
 
+		if sandbox {
+			limits := instrument.Limits{Steps: sandboxSteps, Memory: sandboxMemory << 20}
+			if err := vm.Sandbox(filepath.Dir(path), limits); err != nil {
+				return err
+			}
+		}
 		if err := vm.DoString(fmt.Sprintf("Rack.load('%s')", path)); err != nil {
 			return err
 		}
+		if watch {
+			go vm.Watch(250*time.Millisecond, nil)
+		}
+	} else if watch {
+		return fmt.Errorf("-watch needs a rack file")
+	} else if sandbox {
+		return fmt.Errorf("-sandbox needs a rack file")
 	}
 
 	sig := make(chan os.Signal)
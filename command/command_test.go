@@ -17,4 +17,7 @@ func TestRun(t *testing.T) {
 
 	err = Run([]string{"notexistant.lua"})
 	assert.NotEqual(t, err, nil)
+
+	err = Run([]string{"-control-rate", "7"})
+	assert.NotEqual(t, err, nil)
 }
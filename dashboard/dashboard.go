@@ -0,0 +1,202 @@
+// Package dashboard provides a local web page for watching the synthesizer while it runs
+package dashboard
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+)
+
+// scopeFrames is the number of frames collected before they're sent to the browser
+const scopeFrames = 8
+
+// Engine is the audio engine being watched
+type Engine interface {
+	module.Executor
+	Load() float64
+	Latency() time.Duration
+	TotalElapsed() time.Duration
+}
+
+// Dashboard serves the page along with the module graph, engine metrics and output scopes it displays
+type Dashboard struct {
+	engine Engine
+	root   *module.IO
+	mux    *http.ServeMux
+}
+
+// New returns a Dashboard for the modules reachable from root
+func New(e Engine, root *module.IO) *Dashboard {
+	d := &Dashboard{engine: e, root: root, mux: http.NewServeMux()}
+	d.mux.HandleFunc("/", d.page)
+	d.mux.HandleFunc("/graph", d.graph)
+	d.mux.HandleFunc("/engine", d.metrics)
+	d.mux.HandleFunc("/scope", d.scope)
+	return d
+}
+
+// ListenAndServe serves the dashboard on an address. The host defaults to localhost so the dashboard isn't exposed to
+// the network unless asked for.
+func (d *Dashboard) ListenAndServe(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return http.ListenAndServe(net.JoinHostPort(host, port), d)
+}
+
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mux.ServeHTTP(w, r)
+}
+
+func (d *Dashboard) page(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, page)
+}
+
+type graphModule struct {
+	ID      string        `json:"id"`
+	Type    string        `json:"type"`
+	Inputs  []graphInput  `json:"inputs"`
+	Outputs []graphOutput `json:"outputs"`
+}
+
+type graphInput struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Module string `json:"module,omitempty"`
+	Output string `json:"output,omitempty"`
+}
+
+type graphOutput struct {
+	Name         string   `json:"name"`
+	Destinations []string `json:"destinations"`
+}
+
+func (d *Dashboard) graph(w http.ResponseWriter, r *http.Request) {
+	modules := []graphModule{}
+	d.engine.Exec(func() error {
+		for _, node := range module.Reachable(d.root) {
+			m := graphModule{ID: node.ID(), Type: node.Type(), Inputs: []graphInput{}, Outputs: []graphOutput{}}
+			for name, in := range node.Inputs() {
+				gi := graphInput{Name: name, Source: in.SourceName()}
+				if s, ok := in.State(); ok && s.IsConnection() {
+					gi.Module, gi.Output = s.Module, s.Output
+				}
+				m.Inputs = append(m.Inputs, gi)
+			}
+			for name, out := range node.Outputs() {
+				if out.IsActive() {
+					m.Outputs = append(m.Outputs, graphOutput{Name: name, Destinations: out.DestinationNames()})
+				}
+			}
+			sort.Slice(m.Inputs, func(i, j int) bool { return m.Inputs[i].Name < m.Inputs[j].Name })
+			sort.Slice(m.Outputs, func(i, j int) bool { return m.Outputs[i].Name < m.Outputs[j].Name })
+			modules = append(modules, m)
+		}
+		return nil
+	})
+	sort.Slice(modules, func(i, j int) bool { return modules[i].ID < modules[j].ID })
+	writeJSON(w, modules)
+}
+
+func (d *Dashboard) metrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"load":    d.engine.Load(),
+		"latency": d.engine.Latency().String(),
+		"elapsed": d.engine.TotalElapsed().String(),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// scope streams the frames computed by a module's output over a WebSocket. Each message holds a batch of samples as
+// little-endian float32s.
+func (d *Dashboard) scope(w http.ResponseWriter, r *http.Request) {
+	id, name := r.URL.Query().Get("module"), r.URL.Query().Get("output")
+
+	var (
+		out   *module.Out
+		probe = module.NewProbe(scopeFrames * 4)
+	)
+	err := d.engine.Exec(func() error {
+		for _, node := range module.Reachable(d.root) {
+			if node.ID() != id {
+				continue
+			}
+			o, ok := node.Outputs()[name]
+			if !ok {
+				return fmt.Errorf(`%s: output "%s" doesn't exist`, id, name)
+			}
+			out = o
+			out.AddProbe(probe)
+			return nil
+		}
+		return fmt.Errorf("module %s isn't patched into the engine", id)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer d.engine.Exec(func() error {
+		out.RemoveProbe(probe)
+		return nil
+	})
+
+	ws, err := upgrade(w, r)
+	if _, ok := err.(originError); ok {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer ws.Close()
+	closed := ws.discard()
+
+	var (
+		buf    = make([]byte, 0, scopeFrames*dsp.FrameSize*4)
+		sample [4]byte
+		frames int
+	)
+	for {
+		select {
+		case <-closed:
+			return
+		case f := <-probe.Frames():
+			for _, v := range f {
+				binary.LittleEndian.PutUint32(sample[:], math.Float32bits(float32(v)))
+				buf = append(buf, sample[:]...)
+			}
+			probe.Release(f)
+
+			if frames++; frames < scopeFrames {
+				continue
+			}
+			if err := ws.write(opBinary, buf); err != nil {
+				return
+			}
+			buf, frames = buf[:0], 0
+		}
+	}
+}
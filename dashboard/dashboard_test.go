@@ -0,0 +1,179 @@
+package dashboard
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+type mockEngine struct {
+	module.LockExecutor
+}
+
+func (mockEngine) Load() float64               { return 0.5 }
+func (mockEngine) Latency() time.Duration      { return time.Millisecond }
+func (mockEngine) TotalElapsed() time.Duration { return time.Second }
+
+func setup(t *testing.T) (*mockEngine, *module.IO, *module.Out) {
+	root := &module.IO{}
+	in := module.NewIn("input", dsp.Float64(0))
+	assert.Equal(t, root.Expose("Root", []*module.In{in}, []*module.Out{{Name: "output", Provider: dsp.Provide(in)}}), nil)
+
+	init, err := module.Lookup("Direct")
+	assert.Equal(t, err, nil)
+	direct, err := init(nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, direct.Patch("input", dsp.Float64(0.25)), nil)
+
+	source, err := direct.Output("output")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, root.Patch("input", source), nil)
+
+	out, err := root.Output("output")
+	assert.Equal(t, err, nil)
+
+	return &mockEngine{module.LockExecutor{Locker: &sync.Mutex{}}}, root, out
+}
+
+func TestGraph(t *testing.T) {
+	e, root, _ := setup(t)
+	server := httptest.NewServer(New(e, root))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/graph")
+	assert.Equal(t, err, nil)
+	defer resp.Body.Close()
+
+	var modules []graphModule
+	assert.Equal(t, json.NewDecoder(resp.Body).Decode(&modules), nil)
+	assert.Equal(t, len(modules), 2)
+	assert.Equal(t, modules[0].Type, "Direct")
+	assert.Equal(t, modules[0].Outputs[0].Name, "output")
+	assert.Equal(t, modules[1].Type, "Root")
+	assert.Equal(t, modules[1].Inputs[0].Module, modules[0].ID)
+}
+
+func TestEngineMetrics(t *testing.T) {
+	e, root, _ := setup(t)
+	server := httptest.NewServer(New(e, root))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/engine")
+	assert.Equal(t, err, nil)
+	defer resp.Body.Close()
+
+	var metrics map[string]interface{}
+	assert.Equal(t, json.NewDecoder(resp.Body).Decode(&metrics), nil)
+	assert.Equal(t, metrics["load"], 0.5)
+	assert.Equal(t, metrics["latency"], "1ms")
+}
+
+func TestScope(t *testing.T) {
+	e, root, out := setup(t)
+	server := httptest.NewServer(New(e, root))
+	defer server.Close()
+
+	var id string
+	e.Exec(func() error {
+		for _, node := range module.Reachable(root) {
+			if node.Type() == "Direct" {
+				id = node.ID()
+			}
+		}
+		return nil
+	})
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	assert.Equal(t, err, nil)
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "GET /scope?module=%s&output=output HTTP/1.1\r\n", id)
+	fmt.Fprintf(conn, "Host: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n")
+	fmt.Fprintf(conn, "Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, resp.StatusCode, http.StatusSwitchingProtocols)
+	assert.Equal(t, resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+
+	// Pull frames through the probed output like the audio thread would
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		frame := dsp.NewFrame()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				e.Exec(func() error {
+					out.Process(frame)
+					return nil
+				})
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+
+	header := make([]byte, 4)
+	_, err = io.ReadFull(r, header)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, header[0], byte(0x80|opBinary))
+	assert.Equal(t, header[1], byte(126))
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	assert.Equal(t, length, scopeFrames*dsp.FrameSize*4)
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, math.Float32frombits(binary.LittleEndian.Uint32(payload)), float32(0.25))
+}
+
+func TestScopeOrigin(t *testing.T) {
+	e, root, _ := setup(t)
+	server := httptest.NewServer(New(e, root))
+	defer server.Close()
+
+	var id string
+	e.Exec(func() error {
+		for _, node := range module.Reachable(root) {
+			if node.Type() == "Direct" {
+				id = node.ID()
+			}
+		}
+		return nil
+	})
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	handshake := func(origin string) int {
+		conn, err := net.Dial("tcp", host)
+		assert.Equal(t, err, nil)
+		defer conn.Close()
+
+		fmt.Fprintf(conn, "GET /scope?module=%s&output=output HTTP/1.1\r\n", id)
+		fmt.Fprintf(conn, "Host: %s\r\nOrigin: %s\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n", host, origin)
+		fmt.Fprintf(conn, "Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		assert.Equal(t, err, nil)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, handshake("http://evil.example"), http.StatusForbidden)
+	assert.Equal(t, handshake("http://"+host), http.StatusSwitchingProtocols)
+}
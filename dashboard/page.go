@@ -0,0 +1,162 @@
+package dashboard
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Eolian</title>
+<style>
+  body { font-family: monospace; background: #111; color: #ddd; margin: 0; display: flex; height: 100vh; }
+  #modules { width: 40%; overflow-y: auto; padding: 1em; border-right: 1px solid #333; }
+  #views { flex: 1; padding: 1em; }
+  .module { margin-bottom: 1em; }
+  .module h3 { margin: 0 0 0.3em 0; color: #8cf; }
+  .port { padding-left: 1em; }
+  .output { cursor: pointer; color: #fc8; }
+  .output:hover { text-decoration: underline; }
+  canvas { background: #000; width: 100%; height: 35vh; display: block; margin-bottom: 1em; }
+</style>
+</head>
+<body>
+<div id="modules"></div>
+<div id="views">
+  <div id="engine"></div>
+  <p id="probe">Click an output to watch it.</p>
+  <canvas id="scope" width="1024" height="300"></canvas>
+  <canvas id="spectrum" width="1024" height="300"></canvas>
+</div>
+<script>
+var fftSize = 2048;
+var samples = new Float32Array(fftSize);
+var socket = null;
+
+function el(tag, cls, text) {
+  var e = document.createElement(tag);
+  if (cls) e.className = cls;
+  if (text !== undefined) e.textContent = text;
+  return e;
+}
+
+function refreshGraph() {
+  fetch('/graph').then(function(r) { return r.json(); }).then(function(modules) {
+    var root = document.getElementById('modules');
+    root.innerHTML = '';
+    modules.forEach(function(m) {
+      var div = el('div', 'module');
+      div.appendChild(el('h3', '', m.id));
+      m.inputs.forEach(function(i) {
+        div.appendChild(el('div', 'port', i.name + ' <- ' + i.source));
+      });
+      m.outputs.forEach(function(o) {
+        var port = el('div', 'port output', o.name + ' -> ' + o.destinations.join(', '));
+        port.onclick = function() { watch(m.id, o.name); };
+        div.appendChild(port);
+      });
+      root.appendChild(div);
+    });
+  });
+}
+
+function refreshEngine() {
+  fetch('/engine').then(function(r) { return r.json(); }).then(function(e) {
+    document.getElementById('engine').textContent =
+      'load ' + (e.load * 100).toFixed(2) + '%  latency ' + e.latency + '  elapsed ' + e.elapsed;
+  });
+}
+
+function watch(module, output) {
+  if (socket) socket.close();
+  samples.fill(0);
+  document.getElementById('probe').textContent = module + '/' + output;
+  var url = (location.protocol === 'https:' ? 'wss://' : 'ws://') + location.host +
+    '/scope?module=' + encodeURIComponent(module) + '&output=' + encodeURIComponent(output);
+  socket = new WebSocket(url);
+  socket.binaryType = 'arraybuffer';
+  socket.onmessage = function(msg) {
+    var batch = new Float32Array(msg.data);
+    if (batch.length >= fftSize) {
+      samples.set(batch.subarray(batch.length - fftSize));
+    } else {
+      samples.copyWithin(0, batch.length);
+      samples.set(batch, fftSize - batch.length);
+    }
+  };
+}
+
+function drawScope() {
+  var c = document.getElementById('scope'), ctx = c.getContext('2d');
+  ctx.clearRect(0, 0, c.width, c.height);
+  ctx.strokeStyle = '#333';
+  ctx.beginPath(); ctx.moveTo(0, c.height / 2); ctx.lineTo(c.width, c.height / 2); ctx.stroke();
+  ctx.strokeStyle = '#8f8';
+  ctx.beginPath();
+  for (var i = 0; i < fftSize; i++) {
+    var x = i / fftSize * c.width, y = (1 - samples[i]) / 2 * c.height;
+    if (i === 0) ctx.moveTo(x, y); else ctx.lineTo(x, y);
+  }
+  ctx.stroke();
+}
+
+// In-place radix-2 FFT
+function fft(re, im) {
+  var n = re.length, i, j, k;
+  for (i = 1, j = 0; i < n; i++) {
+    var bit = n >> 1;
+    for (; j & bit; bit >>= 1) j ^= bit;
+    j ^= bit;
+    if (i < j) {
+      var t = re[i]; re[i] = re[j]; re[j] = t;
+      t = im[i]; im[i] = im[j]; im[j] = t;
+    }
+  }
+  for (var len = 2; len <= n; len <<= 1) {
+    var ang = -2 * Math.PI / len, wr = Math.cos(ang), wi = Math.sin(ang);
+    for (i = 0; i < n; i += len) {
+      var cr = 1, ci = 0;
+      for (k = 0; k < len / 2; k++) {
+        var ar = re[i + k + len / 2], ai = im[i + k + len / 2];
+        var br = ar * cr - ai * ci, bi = ar * ci + ai * cr;
+        re[i + k + len / 2] = re[i + k] - br; im[i + k + len / 2] = im[i + k] - bi;
+        re[i + k] += br; im[i + k] += bi;
+        var ncr = cr * wr - ci * wi; ci = cr * wi + ci * wr; cr = ncr;
+      }
+    }
+  }
+}
+
+function drawSpectrum() {
+  var c = document.getElementById('spectrum'), ctx = c.getContext('2d');
+  var re = new Float32Array(fftSize), im = new Float32Array(fftSize);
+  for (var i = 0; i < fftSize; i++) {
+    // Hann window
+    re[i] = samples[i] * (0.5 - 0.5 * Math.cos(2 * Math.PI * i / (fftSize - 1)));
+  }
+  fft(re, im);
+  ctx.clearRect(0, 0, c.width, c.height);
+  ctx.strokeStyle = '#f8c';
+  ctx.beginPath();
+  var bins = fftSize / 2;
+  for (var b = 1; b < bins; b++) {
+    var mag = Math.sqrt(re[b] * re[b] + im[b] * im[b]) / bins;
+    var db = Math.max(20 * Math.log10(mag + 1e-12), -120);
+    var x = Math.log(b) / Math.log(bins) * c.width, y = -db / 120 * c.height;
+    if (b === 1) ctx.moveTo(x, y); else ctx.lineTo(x, y);
+  }
+  ctx.stroke();
+}
+
+function draw() {
+  drawScope();
+  drawSpectrum();
+  requestAnimationFrame(draw);
+}
+
+refreshGraph();
+refreshEngine();
+setInterval(refreshGraph, 2000);
+setInterval(refreshEngine, 1000);
+requestAnimationFrame(draw);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,163 @@
+package dashboard
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The dashboard only ever pushes data to the browser, so this is a minimal server side of RFC 6455: it accepts the
+// handshake, writes unfragmented messages and reads incoming frames only to notice when the browser goes away.
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opBinary = 0x2
+	opClose  = 0x8
+)
+
+type websocket struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// originError is returned by upgrade when a page served from another host tries to open the socket. Browsers send
+// cookies and reach localhost on behalf of any page, so the Origin header is the only thing telling them apart.
+type originError string
+
+func (e originError) Error() string { return fmt.Sprintf("origin %s isn't allowed", string(e)) }
+
+func upgrade(w http.ResponseWriter, r *http.Request) (*websocket, error) {
+	if err := checkOrigin(r); err != nil {
+		return nil, err
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, fmt.Errorf("not a websocket handshake")
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("connection can't be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha1.New()
+	io.WriteString(h, key+websocketGUID)
+	accept := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+	fmt.Fprint(rw, "HTTP/1.1 101 Switching Protocols\r\n")
+	fmt.Fprint(rw, "Upgrade: websocket\r\n")
+	fmt.Fprint(rw, "Connection: Upgrade\r\n")
+	fmt.Fprintf(rw, "Sec-WebSocket-Accept: %s\r\n\r\n", accept)
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &websocket{conn: conn, rw: rw}, nil
+}
+
+// checkOrigin accepts handshakes from the dashboard's own page and from clients that aren't browsers, which don't send
+// an Origin header at all.
+func checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || !strings.EqualFold(u.Host, r.Host) {
+		return originError(origin)
+	}
+	return nil
+}
+
+func headerContains(h http.Header, name, value string) bool {
+	for _, v := range h[name] {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (ws *websocket) write(op byte, payload []byte) error {
+	header := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	if _, err := ws.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := ws.rw.Write(payload); err != nil {
+		return err
+	}
+	return ws.rw.Flush()
+}
+
+// discard reads frames from the browser until it closes the connection or the connection fails. The returned channel
+// is closed when that happens.
+func (ws *websocket) discard() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var header [2]byte
+			if _, err := io.ReadFull(ws.rw, header[:]); err != nil {
+				return
+			}
+			op := header[0] & 0x0F
+			length := uint64(header[1] & 0x7F)
+			switch length {
+			case 126:
+				var ext [2]byte
+				if _, err := io.ReadFull(ws.rw, ext[:]); err != nil {
+					return
+				}
+				length = uint64(binary.BigEndian.Uint16(ext[:]))
+			case 127:
+				var ext [8]byte
+				if _, err := io.ReadFull(ws.rw, ext[:]); err != nil {
+					return
+				}
+				length = binary.BigEndian.Uint64(ext[:])
+			}
+			if header[1]&0x80 != 0 {
+				length += 4 // Masking key
+			}
+			if _, err := io.CopyN(ioutil.Discard, ws.rw, int64(length)); err != nil {
+				return
+			}
+			if op == opClose {
+				return
+			}
+		}
+	}()
+	return done
+}
+
+func (ws *websocket) Close() error {
+	ws.write(opClose, nil)
+	return ws.conn.Close()
+}
@@ -0,0 +1,37 @@
+package dsp
+
+import "math"
+
+// Levels measures the peak and RMS level of a signal along with how many samples have clipped
+type Levels struct {
+	peak, squares Float64
+	count, clips  int
+}
+
+// Tick measures a sample
+func (l *Levels) Tick(in Float64) {
+	abs := Abs(in)
+	if abs > l.peak {
+		l.peak = abs
+	}
+	if abs >= 1 {
+		l.clips++
+	}
+	l.squares += in * in
+	l.count++
+}
+
+// Read returns the peak and RMS levels since the last Read, and the total number of clipped samples
+func (l *Levels) Read() (peak, rms Float64, clips int) {
+	peak = l.peak
+	if l.count > 0 {
+		rms = Float64(math.Sqrt(float64(l.squares) / float64(l.count)))
+	}
+	l.peak, l.squares, l.count = 0, 0, 0
+	return peak, rms, l.clips
+}
+
+// Decibels converts an amplitude to dBFS
+func Decibels(amp Float64) Float64 {
+	return Float64(20 * math.Log10(float64(amp)))
+}
@@ -0,0 +1,33 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	var l Levels
+	for _, v := range []Float64{0.5, -0.5, 1.2, -0.5} {
+		l.Tick(v)
+	}
+
+	peak, rms, clips := l.Read()
+	if peak != 1.2 {
+		t.Errorf("peak: expected 1.2; got %f", peak)
+	}
+	if expected := math.Sqrt((0.25*3 + 1.44) / 4); math.Abs(float64(rms)-expected) > tolerance {
+		t.Errorf("rms: expected %f; got %f", expected, rms)
+	}
+	if clips != 1 {
+		t.Errorf("clips: expected 1; got %d", clips)
+	}
+
+	l.Tick(0.1)
+	peak, _, clips = l.Read()
+	if peak != 0.1 {
+		t.Errorf("peak: expected 0.1 after read; got %f", peak)
+	}
+	if clips != 1 {
+		t.Errorf("clips: expected the count to accumulate; got %d", clips)
+	}
+}
@@ -3,6 +3,7 @@ package dsp
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 
 	"buddin.us/musictheory"
@@ -149,3 +150,106 @@ func (bpm BeatsPerMin) String() string {
 func (bpm BeatsPerMin) Process(out Frame) {
 	bpm.Value().Process(out)
 }
+
+// MiddleC is the frequency of C4, the reference of VOct
+var MiddleC = musictheory.MustParsePitch("C4").Freq()
+
+// VOct is a pitch expressed in octaves above or below C4, like a 1V/octave control voltage
+type VOct struct {
+	Valuer
+	Raw float64
+}
+
+// Volts returns the pitch that is v octaves away from C4
+func Volts(v float64) VOct {
+	return VOct{Raw: v, Valuer: Frequency(MiddleC * math.Exp2(v))}
+}
+
+func (v VOct) String() string {
+	return fmt.Sprintf("%.2fV", v.Raw)
+}
+
+// Process reads the VOct real value to a Frame
+func (v VOct) Process(out Frame) {
+	v.Value().Process(out)
+}
+
+// Semis is an interval in equal-tempered semitones. Its real value is the frequency ratio of the interval.
+type Semis struct {
+	Valuer
+	Raw float64
+}
+
+// Semitones returns an interval of v semitones
+func Semitones(v float64) Semis {
+	return Semis{Raw: v, Valuer: Float64(math.Exp2(v / 12))}
+}
+
+func (s Semis) String() string {
+	return fmt.Sprintf("%.2fst", s.Raw)
+}
+
+// Process reads the Semis real value to a Frame
+func (s Semis) Process(out Frame) {
+	s.Value().Process(out)
+}
+
+// Cent is an interval in hundredths of a semitone. Its real value is the frequency ratio of the interval.
+type Cent struct {
+	Valuer
+	Raw float64
+}
+
+// Cents returns an interval of v cents
+func Cents(v float64) Cent {
+	return Cent{Raw: v, Valuer: Float64(math.Exp2(v / 1200))}
+}
+
+func (c Cent) String() string {
+	return fmt.Sprintf("%.2fcents", c.Raw)
+}
+
+// Process reads the Cent real value to a Frame
+func (c Cent) Process(out Frame) {
+	c.Value().Process(out)
+}
+
+// DB is a gain in decibels. Its real value is the amplitude it scales a signal by.
+type DB struct {
+	Valuer
+	Raw float64
+}
+
+// Gain returns a gain of v decibels
+func Gain(v float64) DB {
+	return DB{Raw: v, Valuer: Float64(math.Pow(10, v/20))}
+}
+
+func (db DB) String() string {
+	return fmt.Sprintf("%.2fdB", db.Raw)
+}
+
+// Process reads the DB real value to a Frame
+func (db DB) Process(out Frame) {
+	db.Value().Process(out)
+}
+
+// Ratio is a plain multiplier, such as a frequency ratio or a linear gain
+type Ratio struct {
+	Valuer
+	Raw float64
+}
+
+// Times returns a ratio of v
+func Times(v float64) Ratio {
+	return Ratio{Raw: v, Valuer: Float64(v)}
+}
+
+func (r Ratio) String() string {
+	return fmt.Sprintf("%.2fx", r.Raw)
+}
+
+// Process reads the Ratio real value to a Frame
+func (r Ratio) Process(out Frame) {
+	r.Value().Process(out)
+}
@@ -3,7 +3,9 @@ package engine
 
 import (
 	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"buddin.us/eolian/dsp"
@@ -13,7 +15,6 @@ import (
 
 // Engine is the connection of the synthesizer to PortAudio
 type Engine struct {
-	sync.Mutex
 	module.IO
 	left, right *module.In
 
@@ -21,8 +22,25 @@ type Engine struct {
 	errors     chan error
 	stop       chan error
 	metrics    *metrics
+	levels     [2]dsp.Levels
 	stream     *portaudio.Stream
 	originTime time.Duration
+
+	// The PortAudio buffer is a multiple of dsp.FrameSize. When maxBufferSize allows it, frequent xruns cause the
+	// stream to be reopened with a larger buffer.
+	xruns                     *xruns
+	bufferSize, maxBufferSize int64
+	resize, done, reported    chan struct{}
+
+	// Changes to the module graph are queued and applied by the audio thread at the start of each frame. The mutex
+	// only serializes callers against the stream starting and stopping; the audio thread never takes it.
+	commands *queue
+	mtx      sync.Mutex
+	running  bool
+
+	// Sample time of the frame being rendered, and the commands waiting for a later frame
+	samples  int64
+	timeline *timeline
 }
 
 // New returns a new Enngine
@@ -46,16 +64,26 @@ func New(deviceIndex int) (*Engine, error) {
 	fmt.Println("Frame Size:", dsp.FrameSize)
 
 	m := &Engine{
-		left:    &module.In{Name: "left", Source: dsp.NewBuffer(dsp.Float64(0)), ForceSinking: true},
-		right:   &module.In{Name: "right", Source: dsp.NewBuffer(dsp.Float64(0)), ForceSinking: true},
-		errors:  make(chan error),
-		stop:    make(chan error),
-		device:  devices[deviceIndex],
-		metrics: &metrics{},
+		left:       &module.In{Name: "left", Source: dsp.NewBuffer(dsp.Float64(0)), ForceSinking: true},
+		right:      &module.In{Name: "right", Source: dsp.NewBuffer(dsp.Float64(0)), ForceSinking: true},
+		errors:     make(chan error),
+		stop:       make(chan error),
+		device:     devices[deviceIndex],
+		metrics:    &metrics{},
+		commands:   newQueue(),
+		timeline:   newTimeline(),
+		xruns:      newXruns(),
+		bufferSize: int64(dsp.FrameSize),
+		resize:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		reported:   make(chan struct{}),
 	}
 
-	err = m.Expose("Engine", []*module.In{m.left, m.right}, nil)
-	return m, err
+	if err := m.Expose("Engine", []*module.In{m.left, m.right}, nil); err != nil {
+		return nil, err
+	}
+	go m.report()
+	return m, nil
 }
 
 // LuaMethods exposes methods on the module at the Lua layer
@@ -70,35 +98,94 @@ func (e *Engine) LuaMethods() map[string]module.LuaMethod {
 		"load": module.LuaMethod{Func: func() (string, error) {
 			return fmt.Sprintf("%.2f%%", e.Load()*100), nil
 		}},
+		"levels": module.LuaMethod{Lock: true, Func: func() (map[string]interface{}, error) {
+			return map[string]interface{}{
+				"left":  module.ReadLevels(&e.levels[0]),
+				"right": module.ReadLevels(&e.levels[1]),
+			}, nil
+		}},
+		"xruns": module.LuaMethod{Func: func() (map[string]interface{}, error) {
+			underflows, overBudget := e.xruns.counts()
+			return map[string]interface{}{
+				"underflows": underflows,
+				"overBudget": overBudget,
+				"bufferSize": e.BufferSize(),
+			}, nil
+		}},
 	}
 }
 
+// SetMaxBufferSize allows the PortAudio buffer to grow up to a number of frames when xruns are frequent. The buffer
+// starts at dsp.FrameSize and doubles each time it grows, trading latency for stability. It must be called before Run.
+func (e *Engine) SetMaxBufferSize(frames int) {
+	atomic.StoreInt64(&e.maxBufferSize, int64(frames))
+}
+
+// BufferSize returns the number of frames PortAudio requests in each callback
+func (e *Engine) BufferSize() int {
+	return int(atomic.LoadInt64(&e.bufferSize))
+}
+
+// Xruns returns the number of output underflows and callbacks that exceeded their time budget
+func (e *Engine) Xruns() (underflows, overBudget int) {
+	return e.xruns.counts()
+}
+
 // TotalElapsed returns the current wallclock duration of the session
 func (e *Engine) TotalElapsed() time.Duration {
-	e.Lock()
-	r := e.metrics.TotalElapsed
-	e.Unlock()
-	return r - e.originTime
+	return time.Duration(atomic.LoadInt64(&e.metrics.totalElapsed)) - e.originTime
 }
 
 // Latency returns the current latency within the PortAudio callback. It's an indicator of how computationally expensive
 // your Rack is, and does not include any latency between PortAudio and your speakers.
 func (e *Engine) Latency() time.Duration {
-	e.Lock()
-	r := e.metrics.Callback
-	e.Unlock()
-	return r
+	return time.Duration(atomic.LoadInt64(&e.metrics.callback))
 }
 
 // Load returns the current CPU load of the underlying audio engine
 func (e *Engine) Load() float64 {
-	e.Lock()
-	r := e.metrics.Load
-	e.Unlock()
-	return r
+	return math.Float64frombits(atomic.LoadUint64(&e.metrics.load))
+}
+
+// Exec runs a function that reads or mutates the module graph. While the audio stream is running, the function is
+// queued and run by the audio thread at the start of the next frame; Exec waits for it to finish. Otherwise the function
+// is run immediately.
+func (e *Engine) Exec(fn func() error) error {
+	e.mtx.Lock()
+	if !e.running {
+		defer e.mtx.Unlock()
+		return fn()
+	}
+	c := &command{fn: fn, done: make(chan error, 1)}
+	e.commands.push(c)
+	e.mtx.Unlock()
+	return <-c.done
+}
+
+// Now returns the sample time of the frame being rendered, or of the next frame between callbacks
+func (e *Engine) Now() int64 {
+	return atomic.LoadInt64(&e.samples)
+}
+
+// ExecAt queues a function to be run by the audio thread at a sample time without waiting for it. The function runs at
+// the start of the frame containing its time, and patches it makes take effect at that exact sample. Errors are sent
+// through Errors().
+func (e *Engine) ExecAt(at int64, fn func() error) {
+	e.timeline.queue.push(&command{fn: fn, at: at})
+}
+
+func (e *Engine) setRunning(running bool) {
+	e.mtx.Lock()
+	e.running = running
+	if !running {
+		// Nothing will drain the queue after this point, so run whatever is left
+		e.commands.drain()
+	}
+	e.mtx.Unlock()
 }
 
-// Errors returns a channel that expresses any errors during operation of the Engine
+// Errors returns a channel that expresses any errors during operation of the Engine, including those of functions
+// queued with ExecAt. Xruns are sent as Xrun values.
 func (e *Engine) Errors() chan error {
 	return e.errors
 }
@@ -107,31 +194,56 @@ func (e *Engine) params() portaudio.StreamParameters {
 	params := portaudio.LowLatencyParameters(nil, e.device)
 	params.Output.Channels = 2
 	params.SampleRate = dsp.SampleRate
-	params.FramesPerBuffer = dsp.FrameSize
+	params.FramesPerBuffer = e.BufferSize()
 	return params
 }
 
 // Run starts the Engine; running the audio stream
 func (e *Engine) Run() {
-	var err error
-	e.stream, err = portaudio.OpenStream(e.params(), e.portAudioCallback)
-	if err != nil {
-		e.errors <- err
-		return
-	}
-	e.originTime = e.stream.Time()
+	for {
+		stream, err := portaudio.OpenStream(e.params(), e.portAudioCallback)
+		if err != nil {
+			e.errors <- err
+			return
+		}
+		if e.stream == nil {
+			e.originTime = stream.Time()
+		}
+		e.stream = stream
 
-	if err = e.stream.Start(); err != nil {
-		e.errors <- err
-		return
+		e.setRunning(true)
+		if err = e.stream.Start(); err != nil {
+			e.setRunning(false)
+			e.errors <- err
+			return
+		}
+
+		select {
+		case <-e.stop:
+			e.stop <- e.closeStream()
+			return
+		case <-e.resize:
+			if err := e.closeStream(); err != nil {
+				e.errors <- err
+				return
+			}
+			size := 2 * e.BufferSize()
+			if max := int(atomic.LoadInt64(&e.maxBufferSize)); size > max {
+				size = max - max%dsp.FrameSize
+			}
+			atomic.StoreInt64(&e.bufferSize, int64(size))
+			e.errors <- fmt.Errorf("xruns are frequent; buffer size increased to %d frames", size)
+		}
 	}
-	<-e.stop
+}
 
-	err = e.stream.Stop()
+func (e *Engine) closeStream() error {
+	err := e.stream.Stop()
+	e.setRunning(false)
 	if err == nil {
 		err = e.stream.Close()
 	}
-	e.stop <- err
+	return err
 }
 
 // Stop shuts down the Engine
@@ -139,31 +251,75 @@ func (e *Engine) Stop() error {
 	defer portaudio.Terminate()
 	e.stop <- nil
 	err := <-e.stop
+	close(e.done)
+	<-e.reported
 	close(e.errors)
 	close(e.stop)
 	return err
 }
 
-func (e *Engine) portAudioCallback(_, out [][]float32) {
-	e.Lock()
+// report forwards xruns and errors from the audio thread to Errors() and decides when the buffer should grow
+func (e *Engine) report() {
+	defer close(e.reported)
+	var g growth
+	for {
+		select {
+		case <-e.done:
+			return
+		case xrun := <-e.xruns.pending:
+			max := int(atomic.LoadInt64(&e.maxBufferSize))
+			if max >= e.BufferSize()+dsp.FrameSize && g.observe(xrun.Time) {
+				select {
+				case e.resize <- struct{}{}:
+				default:
+				}
+			}
+			select {
+			case e.errors <- xrun:
+			case <-e.done:
+				return
+			}
+		case err := <-e.timeline.failed:
+			select {
+			case e.errors <- err:
+			case <-e.done:
+				return
+			}
+		}
+	}
+}
+
+func (e *Engine) portAudioCallback(_, out [][]float32, _ portaudio.StreamCallbackTimeInfo, flags portaudio.StreamCallbackFlags) {
 	now := time.Now()
-	left, right := e.left.ProcessFrame(), e.right.ProcessFrame()
-	for i := range out {
-		for j := 0; j < len(out[i]); j++ {
-			if i%2 == 0 {
-				out[i][j] = float32(left[j])
-			} else {
-				out[i][j] = float32(right[j])
+	for offset := 0; offset < len(out[0]); offset += dsp.FrameSize {
+		e.commands.drain()
+		e.timeline.run(atomic.LoadInt64(&e.samples))
+		module.ProfileFrame()
+		left, right := e.left.ProcessFrame(), e.right.ProcessFrame()
+		for i := range left {
+			e.levels[0].Tick(left[i])
+			e.levels[1].Tick(right[i])
+		}
+		for i := range out {
+			for j := 0; j < dsp.FrameSize && offset+j < len(out[i]); j++ {
+				if i%2 == 0 {
+					out[i][offset+j] = float32(left[j])
+				} else {
+					out[i][offset+j] = float32(right[j])
+				}
 			}
 		}
+		atomic.AddInt64(&e.samples, int64(dsp.FrameSize))
 	}
-	e.metrics.Callback = time.Since(now)
-	e.metrics.TotalElapsed = e.stream.Time()
-	e.metrics.Load = e.stream.CpuLoad()
-	e.Unlock()
+	elapsed := time.Since(now)
+	e.xruns.check(flags, now, elapsed, time.Duration(len(out[0]))*time.Second/dsp.SampleRate)
+	atomic.StoreInt64(&e.metrics.callback, int64(elapsed))
+	atomic.StoreInt64(&e.metrics.totalElapsed, int64(e.stream.Time()))
+	atomic.StoreUint64(&e.metrics.load, math.Float64bits(e.stream.CpuLoad()))
 }
 
+// metrics are written by the audio thread and read atomically from elsewhere
 type metrics struct {
-	TotalElapsed, Callback time.Duration
-	Load                   float64
+	totalElapsed, callback int64
+	load                   uint64
 }
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// command is a function waiting to be run on the audio thread
+type command struct {
+	fn   func() error
+	done chan error
+	at   int64 // Sample time of commands queued with ExecAt
+	next unsafe.Pointer
+}
+
+// queue is an intrusive multi-producer/single-consumer queue. Pushing is wait-free and popping never blocks, so the
+// audio thread can drain it without waiting on the goroutines that fill it.
+type queue struct {
+	head unsafe.Pointer
+	tail *command
+	stub command
+}
+
+func newQueue() *queue {
+	q := &queue{}
+	q.head = unsafe.Pointer(&q.stub)
+	q.tail = &q.stub
+	return q
+}
+
+func (q *queue) push(c *command) {
+	atomic.StorePointer(&c.next, nil)
+	prev := (*command)(atomic.SwapPointer(&q.head, unsafe.Pointer(c)))
+	atomic.StorePointer(&prev.next, unsafe.Pointer(c))
+}
+
+// pop removes the oldest command from the queue. It returns nil if the queue is empty or a producer is in the middle
+// of pushing; in the latter case the command is picked up by a later pop.
+func (q *queue) pop() *command {
+	tail := q.tail
+	next := (*command)(atomic.LoadPointer(&tail.next))
+	if tail == &q.stub {
+		if next == nil {
+			return nil
+		}
+		q.tail = next
+		tail = next
+		next = (*command)(atomic.LoadPointer(&next.next))
+	}
+	if next != nil {
+		q.tail = next
+		return tail
+	}
+	if tail != (*command)(atomic.LoadPointer(&q.head)) {
+		return nil
+	}
+	q.push(&q.stub)
+	next = (*command)(atomic.LoadPointer(&tail.next))
+	if next != nil {
+		q.tail = next
+		return tail
+	}
+	return nil
+}
+
+// drain runs every queued command and reports the results back to the waiting callers
+func (q *queue) drain() {
+	for c := q.pop(); c != nil; c = q.pop() {
+		c.done <- c.fn()
+	}
+}
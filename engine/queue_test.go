@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"sync"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestQueue(t *testing.T) {
+	q := newQueue()
+	assert.Equal(t, q.pop() == nil, true)
+
+	var (
+		wg      sync.WaitGroup
+		applied int
+	)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &command{
+				fn: func() error {
+					applied++
+					return nil
+				},
+				done: make(chan error, 1),
+			}
+			q.push(c)
+			<-c.done
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-done:
+			assert.Equal(t, applied, 100)
+			assert.Equal(t, q.pop() == nil, true)
+			return
+		default:
+			q.drain()
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"sort"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+)
+
+const timelineBacklog = 64
+
+// timeline holds the commands queued with ExecAt until the frame containing their time. Commands arrive through a
+// queue; the timeline itself is only used by the audio thread.
+type timeline struct {
+	queue   *queue
+	pending []*command
+	failed  chan error
+}
+
+func newTimeline() *timeline {
+	return &timeline{
+		queue:  newQueue(),
+		failed: make(chan error, timelineBacklog),
+	}
+}
+
+// run runs the commands due in the frame starting at a time. Patches they make take effect at their offset in the
+// frame. Errors can't be returned to anyone, so they're queued for reporting and dropped when the backlog is full.
+func (t *timeline) run(start int64) {
+	for c := t.queue.pop(); c != nil; c = t.queue.pop() {
+		i := sort.Search(len(t.pending), func(i int) bool { return t.pending[i].at > c.at })
+		t.pending = append(t.pending, nil)
+		copy(t.pending[i+1:], t.pending[i:])
+		t.pending[i] = c
+	}
+
+	end := start + int64(dsp.FrameSize)
+	var n int
+	for ; n < len(t.pending) && t.pending[n].at < end; n++ {
+		c := t.pending[n]
+		offset := c.at - start
+		if offset < 0 {
+			offset = 0
+		}
+		if err := module.PatchAt(int(offset), c.fn); err != nil {
+			select {
+			case t.failed <- err:
+			default:
+			}
+		}
+		t.pending[n] = nil
+	}
+	t.pending = t.pending[:copy(t.pending, t.pending[n:])]
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestTimeline(t *testing.T) {
+	var (
+		tl   = newTimeline()
+		size = int64(dsp.FrameSize)
+		ran  []int64
+	)
+	record := func(at int64) {
+		tl.queue.push(&command{at: at, fn: func() error {
+			ran = append(ran, at)
+			return nil
+		}})
+	}
+	record(2*size + 1)
+	record(size + 3)
+	record(size)
+	record(-5)
+
+	tl.run(0)
+	assert.Equal(t, ran, []int64{-5})
+	tl.run(size)
+	assert.Equal(t, ran, []int64{-5, size, size + 3})
+	tl.run(2 * size)
+	assert.Equal(t, ran, []int64{-5, size, size + 3, 2*size + 1})
+	assert.Equal(t, len(tl.pending), 0)
+
+	tl.queue.push(&command{at: 0, fn: func() error { return errors.New("failed") }})
+	tl.run(3 * size)
+	assert.Equal(t, (<-tl.failed).Error(), "failed")
+}
+
+func TestTimelinePatchOffset(t *testing.T) {
+	init, err := module.Lookup("Direct")
+	assert.Equal(t, err, nil)
+	direct, err := init(nil)
+	assert.Equal(t, err, nil)
+	out, err := direct.Output("output")
+	assert.Equal(t, err, nil)
+
+	tl := newTimeline()
+	tl.queue.push(&command{at: 7, fn: func() error {
+		return direct.Patch("input", 1)
+	}})
+	tl.run(0)
+
+	frame := dsp.NewFrame()
+	out.Process(frame)
+	assert.Equal(t, frame[6], dsp.Float64(0))
+	assert.Equal(t, frame[7], dsp.Float64(1))
+}
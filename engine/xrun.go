@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+const (
+	// Buffer growth is triggered when this many xruns happen within the window
+	xrunGrowCount  = 3
+	xrunGrowWindow = 10 * time.Second
+
+	xrunBacklog = 64
+)
+
+// XrunKind is the reason audio wasn't delivered in time
+type XrunKind int
+
+// Kinds of xruns
+const (
+	// Underflow means PortAudio ran out of output samples before the callback provided them
+	Underflow XrunKind = iota
+	// OverBudget means the callback took longer to compute a buffer than the buffer takes to play
+	OverBudget
+)
+
+// Xrun is sent through Errors() when the engine fails to deliver audio in time
+type Xrun struct {
+	Kind    XrunKind
+	Time    time.Time
+	Elapsed time.Duration // Time spent in the callback
+	Budget  time.Duration // Duration of the audio computed by the callback
+}
+
+func (x Xrun) Error() string {
+	timestamp := x.Time.Format("15:04:05.000")
+	if x.Kind == Underflow {
+		return fmt.Sprintf("%s xrun: output underflow", timestamp)
+	}
+	return fmt.Sprintf("%s xrun: callback took %s; budget is %s", timestamp, x.Elapsed, x.Budget)
+}
+
+// xruns counts xruns detected by the audio thread and queues them for reporting. Recording never blocks; when the
+// backlog is full the xrun is counted but not reported.
+type xruns struct {
+	underflows, overBudget uint64
+	pending                chan Xrun
+}
+
+func newXruns() *xruns {
+	return &xruns{pending: make(chan Xrun, xrunBacklog)}
+}
+
+// check records xruns for a callback given PortAudio's status flags and the time the callback took
+func (x *xruns) check(flags portaudio.StreamCallbackFlags, start time.Time, elapsed, budget time.Duration) {
+	if flags&portaudio.OutputUnderflow != 0 {
+		atomic.AddUint64(&x.underflows, 1)
+		x.report(Xrun{Kind: Underflow, Time: start, Elapsed: elapsed, Budget: budget})
+	}
+	if elapsed > budget {
+		atomic.AddUint64(&x.overBudget, 1)
+		x.report(Xrun{Kind: OverBudget, Time: start, Elapsed: elapsed, Budget: budget})
+	}
+}
+
+func (x *xruns) report(xrun Xrun) {
+	select {
+	case x.pending <- xrun:
+	default:
+	}
+}
+
+func (x *xruns) counts() (underflows, overBudget int) {
+	return int(atomic.LoadUint64(&x.underflows)), int(atomic.LoadUint64(&x.overBudget))
+}
+
+// growth decides when xruns are frequent enough to warrant a larger buffer
+type growth struct {
+	recent []time.Time
+}
+
+func (g *growth) observe(t time.Time) bool {
+	g.recent = append(g.recent, t)
+	for len(g.recent) > 0 && t.Sub(g.recent[0]) > xrunGrowWindow {
+		g.recent = g.recent[1:]
+	}
+	if len(g.recent) < xrunGrowCount {
+		return false
+	}
+	g.recent = g.recent[:0]
+	return true
+}
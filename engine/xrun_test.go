@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestXrunDetection(t *testing.T) {
+	var (
+		x     = newXruns()
+		start = time.Now()
+	)
+
+	x.check(0, start, time.Millisecond, 5*time.Millisecond)
+	underflows, overBudget := x.counts()
+	assert.Equal(t, underflows, 0)
+	assert.Equal(t, overBudget, 0)
+
+	x.check(portaudio.OutputUnderflow, start, 6*time.Millisecond, 5*time.Millisecond)
+	underflows, overBudget = x.counts()
+	assert.Equal(t, underflows, 1)
+	assert.Equal(t, overBudget, 1)
+
+	xrun := <-x.pending
+	assert.Equal(t, xrun.Kind, Underflow)
+	xrun = <-x.pending
+	assert.Equal(t, xrun.Kind, OverBudget)
+	assert.Equal(t, xrun.Elapsed, 6*time.Millisecond)
+}
+
+func TestXrunBacklogDoesNotBlock(t *testing.T) {
+	x := newXruns()
+	for i := 0; i < xrunBacklog*2; i++ {
+		x.check(portaudio.OutputUnderflow, time.Now(), 0, time.Millisecond)
+	}
+	underflows, _ := x.counts()
+	assert.Equal(t, underflows, xrunBacklog*2)
+	assert.Equal(t, len(x.pending), xrunBacklog)
+}
+
+func TestXrunGrowth(t *testing.T) {
+	var (
+		g     growth
+		start = time.Now()
+	)
+	assert.Equal(t, g.observe(start), false)
+	assert.Equal(t, g.observe(start.Add(time.Second)), false)
+	assert.Equal(t, g.observe(start.Add(20*time.Second)), false)
+	assert.Equal(t, g.observe(start.Add(21*time.Second)), false)
+	assert.Equal(t, g.observe(start.Add(22*time.Second)), true)
+	assert.Equal(t, g.observe(start.Add(23*time.Second)), false)
+}
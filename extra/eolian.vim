@@ -1,3 +1,8 @@
+" Address eolian was started with -listen on. Unix sockets (unix:/path) need a Vim with Unix socket channels.
+let g:eolian_listen = get(g:, 'eolian_listen', 'localhost:7777')
+" Token eolian printed at startup (or was given with -listen-token). Not needed for Unix sockets.
+let g:eolian_token = get(g:, 'eolian_token', '')
+
 function! EolianPatch()
     call s:signal("USR1")
 endfunction
@@ -6,6 +11,20 @@ function! EolianBuild()
     call s:signal("USR2")
 endfunction
 
+function! EolianEval(code)
+    let ch = ch_open(g:eolian_listen, {'mode': 'nl', 'waittime': 1000})
+    if ch_status(ch) != "open"
+        echom "Eolian isn't listening on " . g:eolian_listen
+        return
+    endif
+    let resp = json_decode(ch_evalraw(ch, json_encode({'token': g:eolian_token, 'code': a:code}) . "\n", {'timeout': 10000}))
+    call ch_close(ch)
+    if has_key(resp, 'error')
+        echoerr resp.error
+    endif
+    echo substitute(resp.output, '\n$', '', '')
+endfunction
+
 function! s:signal(which)
     let pid = system("pgrep eolian")
     if pid == ""
@@ -17,3 +36,4 @@ endfunction
 
 command! EolianPatch call EolianPatch()
 command! EolianBuild call EolianBuild()
+command! -range EolianEval call EolianEval(join(getline(<line1>, <line2>), "\n"))
@@ -0,0 +1,177 @@
+package lua
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+)
+
+var (
+	// Inside the table given to a module's set method: Rack.modules.filter:set { cut
+	setPattern = regexp.MustCompile(`([A-Za-z_][\w.\[\]'"]*):set\s*\(?\s*\{([^{}]*)$`)
+	// Inside the string given to a module's out method: Rack.modules.filter:out('low
+	outPattern = regexp.MustCompile(`([A-Za-z_][\w.\[\]'"]*):out\(\s*['"]([^'"]*)$`)
+	// Inside the table given to a constructor: synth.Filter { cut
+	configPattern = regexp.MustCompile(`\.([A-Z]\w*)\s*\(?\s*\{([^{}]*)$`)
+
+	pathPattern = regexp.MustCompile(`^(?:([A-Za-z_]\w*)|\[(\d+)\]|\['([^']*)'\]|\["([^"]*)"\])\.?`)
+	wordPattern = regexp.MustCompile(`\w*$`)
+)
+
+// completion offers the rest of whatever is being typed at the end of the line: the names of a module's inputs within
+// :set { ... }, its outputs within :out('...'), a constructor's config keys or else the keys of Lua tables by dot-path
+func (vm *VM) completion(line [][]rune, pos int) [][]rune {
+	vm.mtx.Lock()
+	defer vm.mtx.Unlock()
+
+	segments := make([]string, len(line))
+	for i, s := range line {
+		segments[i] = string(s)
+	}
+	input := strings.Join(segments, " ")
+	last := segments[len(segments)-1]
+
+	if m := outPattern.FindStringSubmatch(input); m != nil {
+		return vm.portCompletion(last, m[2], m[1], false)
+	}
+	if m := setPattern.FindStringSubmatch(input); m != nil && isKeyPosition(m[2]) {
+		return vm.portCompletion(last, wordPattern.FindString(m[2]), m[1], true)
+	}
+	if m := configPattern.FindStringSubmatch(input); m != nil && isKeyPosition(m[2]) {
+		names := []string{}
+		if meta, err := module.LookupMetadata(m[1]); err == nil {
+			for _, c := range meta.Config {
+				names = append(names, c.Name)
+			}
+			if meta.ControlRate {
+				names = append(names, "controlRate")
+			}
+		}
+		return candidates(last, wordPattern.FindString(m[2]), names)
+	}
+	return vm.tableCompletion(last)
+}
+
+// isKeyPosition returns whether the contents of a table constructor end where a key goes rather than a value
+func isKeyPosition(fields string) bool {
+	if i := strings.LastIndexAny(fields, ",;"); i > -1 {
+		fields = fields[i+1:]
+	}
+	return !strings.Contains(fields, "=")
+}
+
+// portCompletion offers the names of the inputs or outputs of the module found at a path of globals
+func (vm *VM) portCompletion(last, word, path string, inputs bool) [][]rune {
+	p, ok := patcherOf(lookupPath(vm.G.Global, path))
+	if !ok {
+		return [][]rune{}
+	}
+	names := []string{}
+	vm.sched.Exec(func() error {
+		if inputs {
+			for name := range p.Inputs() {
+				names = append(names, name)
+			}
+		} else {
+			for name := range p.Outputs() {
+				names = append(names, name)
+			}
+		}
+		return nil
+	})
+	return candidates(last, word, names)
+}
+
+// candidates replaces the word at the end of the last segment with each name it's a prefix of
+func candidates(last, word string, names []string) [][]rune {
+	if !strings.HasSuffix(last, word) {
+		return [][]rune{}
+	}
+	prefix := last[:len(last)-len(word)]
+	sort.Strings(names)
+	result := [][]rune{}
+	for _, name := range names {
+		if strings.HasPrefix(name, word) {
+			result = append(result, []rune(prefix+name))
+		}
+	}
+	return result
+}
+
+// lookupPath follows a path like Rack.modules.voices[1] from a table, returning nil if it leads nowhere
+func lookupPath(table *lua.LTable, path string) lua.LValue {
+	var v lua.LValue = table
+	for path != "" {
+		m := pathPattern.FindStringSubmatch(path)
+		t, ok := v.(*lua.LTable)
+		if m == nil || !ok {
+			return lua.LNil
+		}
+		switch {
+		case m[1] != "":
+			v = t.RawGetString(m[1])
+		case m[2] != "":
+			i, _ := strconv.Atoi(m[2])
+			v = t.RawGetInt(i)
+		case m[3] != "":
+			v = t.RawGetString(m[3])
+		default:
+			v = t.RawGetString(m[4])
+		}
+		path = path[len(m[0]):]
+	}
+	return v
+}
+
+// patcherOf returns the module behind a module's table
+func patcherOf(v lua.LValue) (module.Patcher, bool) {
+	t, ok := v.(*lua.LTable)
+	if !ok {
+		return nil, false
+	}
+	data, ok := t.RawGetString("__patcher").(*lua.LUserData)
+	if !ok {
+		return nil, false
+	}
+	p, ok := data.Value.(module.Patcher)
+	return p, ok
+}
+
+// tableCompletion offers the keys of Lua tables reached by dot-path from the globals
+func (vm *VM) tableCompletion(last string) [][]rune {
+	var prefix string
+	input := last
+	if start := strings.LastIndexAny(input, "({[,="); start > -1 {
+		prefix, input = input[:start+1], input[start+1:]
+	}
+	parts := strings.Split(input, ".")
+
+	table := vm.G.Global
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := table.RawGetString(part).(*lua.LTable)
+		if !ok {
+			return [][]rune{}
+		}
+		table = next
+	}
+
+	word := parts[len(parts)-1]
+	path := prefix + strings.Join(parts[:len(parts)-1], ".")
+	if len(parts) > 1 {
+		path += "."
+	}
+	names := []string{}
+	table.ForEach(func(k, v lua.LValue) {
+		name, ok := k.(lua.LString)
+		if !ok || (len(parts) > 1 && strings.HasPrefix(string(name), "__")) {
+			return
+		}
+		names = append(names, string(name))
+	})
+	return candidates(path+word, word, names)
+}
@@ -0,0 +1,79 @@
+package lua
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chzyer/readline"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestCompletion(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(`
+		synth = require('eolian.synth')
+		Rack.modules = { filter = synth.Filter(), voices = { synth.Oscillator() } }
+	`), nil)
+
+	complete := func(line string) []string {
+		segments, pos := readline.SplitSegment([]rune(line), len(line))
+		result := []string{}
+		for _, c := range vm.completion(segments, pos) {
+			result = append(result, string(c))
+		}
+		return result
+	}
+
+	var tests = []struct {
+		line     string
+		expected []string
+	}{
+		{"Rack.mod", []string{"Rack.modules"}},
+		{"print(Rack.modules.fi", []string{"print(Rack.modules.filter"}},
+		{"Rack.modules.filter:set { cu", []string{"cutoff"}},
+		{"Rack.modules.filter:set { cutoff = 1, res", []string{"resonance"}},
+		{"Rack.modules.filter:set { cutoff = Rack.modules.fi", []string{"Rack.modules.filter"}},
+		{"Rack.modules.voices[1]:set { pitchMod", []string{"pitchMod", "pitchModAmount"}},
+		{"Rack.modules.filter:out('low", []string{"Rack.modules.filter:out('lowpass"}},
+		{"x = Rack.modules.voices[1]:out(\"s", []string{"Rack.modules.voices[1]:out(\"saw", "Rack.modules.voices[1]:out(\"sine",
+			"Rack.modules.voices[1]:out(\"sub"}},
+		{"synth.Oscillator { mul", []string{"multiplier"}},
+		{"synth.Oscillator { multiplier = 2, alg", []string{"algorithm"}},
+		{"nothing.here:set { cu", []string{}},
+	}
+
+	for _, test := range tests {
+		result := complete(test.line)
+		if len(test.expected) == 0 {
+			assert.Equal(t, len(result), 0)
+			continue
+		}
+		assert.Equal(t, result, test.expected)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(`
+		synth = require('eolian.synth')
+		Rack.modules = { filter = synth.Filter(), osc = synth.Oscillator() }
+		Rack.modules.filter:set { input = Rack.modules.osc:out('saw'), cutoff = hz(1000) }
+	`), nil)
+
+	output, err := vm.Eval("Rack.modules.filter?")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasPrefix(output, "Filter (filter)\n"), true)
+	assert.Equal(t, strings.Contains(output, "osc/saw"), true)
+	assert.Equal(t, strings.Contains(output, "1000.00Hz"), true)
+
+	output, err = vm.Eval("? synth.Filter")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasPrefix(output, "Filter: "), true)
+	assert.Equal(t, strings.Contains(output, "Inputs:"), true)
+
+	output, err = vm.Eval("?hz(440)")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "userdata\t440.00Hz\n")
+}
@@ -0,0 +1,489 @@
+package lua
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+)
+
+// exportable is what's needed of a module to write it to a rack file
+type exportable interface {
+	ID() string
+	Type() string
+	Inputs() map[string]*module.In
+	Config() module.Config
+}
+
+// exported is a module written to a rack file, found at a path of keys within the table returned by build
+type exported struct {
+	path   []lua.LValue
+	module exportable
+	inputs []exportedInput
+}
+
+type exportedInput struct {
+	name  string
+	state module.InputState
+	// source is the module an input is patched to
+	source *exported
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var luaKeywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true, "end": true, "false": true, "for": true,
+	"function": true, "goto": true, "if": true, "in": true, "local": true, "nil": true, "not": true, "or": true,
+	"repeat": true, "return": true, "then": true, "true": true, "until": true, "while": true,
+}
+
+// rackExport gathers the modules of a session and writes them as a rack file. Modules are found in Rack.modules, in
+// global variables set from the REPL and upstream of the Engine; the ones found only by following connections are
+// named after their type.
+type rackExport struct {
+	modules  []*exported
+	byID     map[string]*exported
+	topLevel map[string]bool
+	counts   map[string]int
+	engine   []exportedInput
+	warnings []string
+}
+
+func newRackExport() *rackExport {
+	return &rackExport{
+		byID:     map[string]*exported{},
+		topLevel: map[string]bool{},
+		counts:   map[string]int{},
+	}
+}
+
+// exportSession writes the modules of the session, with their configs, connections and constant values, to a rack file
+// in the shape Rack.load expects. It returns warnings about anything that couldn't be written.
+func exportSession(state *lua.LState, exec module.Executor) (string, []string) {
+	e := newRackExport()
+
+	if rack, ok := state.GetGlobal("Rack").(*lua.LTable); ok {
+		if modules, ok := rack.RawGetString("modules").(*lua.LTable); ok {
+			e.addTable(state, modules, nil, map[*lua.LTable]bool{})
+		}
+	}
+
+	globals := state.G.Global
+	names := []string{}
+	globals.ForEach(func(k, v lua.LValue) {
+		if name, ok := k.(lua.LString); ok && name != "Engine" {
+			names = append(names, string(name))
+		}
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		if p, ok := modulePatcher(globals.RawGetString(name)); ok && e.byID[p.ID()] == nil {
+			e.add(p, []lua.LValue{lua.LString(e.unique(name))})
+		}
+	}
+
+	engine, _ := modulePatcher(state.GetField(state.GetGlobal("Engine"), "__patcher"))
+	exec.Exec(func() error {
+		e.follow(engine)
+		return nil
+	})
+	return e.source(), e.warnings
+}
+
+// modulePatcher returns the module held by a Lua value: either a module's table or its __patcher userdata
+func modulePatcher(v lua.LValue) (exportable, bool) {
+	if t, ok := v.(*lua.LTable); ok {
+		v = t.RawGetString("__patcher")
+	}
+	data, ok := v.(*lua.LUserData)
+	if !ok {
+		return nil, false
+	}
+	p, ok := data.Value.(exportable)
+	return p, ok
+}
+
+func (e *rackExport) addTable(state *lua.LState, t *lua.LTable, path []lua.LValue, seen map[*lua.LTable]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	keys := []lua.LValue{}
+	t.ForEach(func(k, v lua.LValue) {
+		switch key := k.(type) {
+		case lua.LString:
+			if !strings.HasPrefix(string(key), "__") {
+				keys = append(keys, k)
+			}
+		case lua.LNumber:
+			keys = append(keys, k)
+		}
+	})
+	sortKeys(keys)
+
+	for _, k := range keys {
+		v := t.RawGet(k)
+		keyPath := append(append([]lua.LValue{}, path...), k)
+		if p, ok := modulePatcher(v); ok {
+			if len(path) == 0 {
+				e.topLevel[k.String()] = true
+			}
+			e.add(p, keyPath)
+		} else if sub, ok := v.(*lua.LTable); ok {
+			if len(path) == 0 {
+				e.topLevel[k.String()] = true
+			}
+			e.addTable(state, sub, keyPath, seen)
+		}
+	}
+}
+
+func (e *rackExport) add(p exportable, path []lua.LValue) *exported {
+	if m, ok := e.byID[p.ID()]; ok {
+		return m
+	}
+	m := &exported{path: path, module: p}
+	e.modules = append(e.modules, m)
+	e.byID[p.ID()] = m
+	return m
+}
+
+// unique returns a top-level key of the build table that isn't taken yet
+func (e *rackExport) unique(name string) string {
+	result := name
+	for i := 2; e.topLevel[result]; i++ {
+		result = fmt.Sprintf("%s%d", name, i)
+	}
+	e.topLevel[result] = true
+	return result
+}
+
+// follow reads the inputs of the engine and every module found so far, adding the modules they're patched to
+func (e *rackExport) follow(engine exportable) {
+	if engine != nil {
+		e.engine = e.readInputs(engine, "engine")
+	}
+	for i := 0; i < len(e.modules); i++ {
+		m := e.modules[i]
+		m.inputs = e.readInputs(m.module, pathExpr("rack", m.path))
+	}
+}
+
+func (e *rackExport) readInputs(p exportable, name string) []exportedInput {
+	inputs := p.Inputs()
+	names := []string{}
+	for n := range inputs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	result := []exportedInput{}
+	for _, n := range names {
+		in := inputs[n]
+		if in.IsDefault() {
+			continue
+		}
+		is, ok := in.State()
+		if !ok {
+			e.warnings = append(e.warnings, fmt.Sprintf("%s.%s: source %s can't be exported", name, n, in.SourceName()))
+			continue
+		}
+		input := exportedInput{name: n, state: is}
+		if source, _, ok := in.Upstream(); ok {
+			m, ok := e.byID[source.ID()]
+			if !ok {
+				typ := source.Type()
+				e.counts[typ]++
+				key := e.unique(fmt.Sprintf("%s%d", strings.ToLower(typ[:1])+typ[1:], e.counts[typ]))
+				m = e.add(source, []lua.LValue{lua.LString(key)})
+			}
+			input.source = m
+		}
+		result = append(result, input)
+	}
+	return result
+}
+
+// source returns the Lua source of the rack file
+func (e *rackExport) source() string {
+	var b bytes.Buffer
+	b.WriteString("-- Exported from an eolian session\n")
+	b.WriteString("return function(env)\n")
+	b.WriteString("    local synth = require('eolian.synth')\n")
+	b.WriteString("    local value = require('eolian.value')\n\n")
+
+	b.WriteString("    local function build()\n")
+	b.WriteString("        return ")
+	e.writeTree(&b, e.tree(), 2)
+	b.WriteString("\n    end\n\n")
+
+	b.WriteString("    local function patch(rack)\n")
+	for _, m := range e.modules {
+		if len(m.inputs) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s:set { %s }\n", pathExpr("rack", m.path), inputList(m.inputs))
+	}
+	if sinks := e.sinks(); sinks != "" {
+		fmt.Fprintf(&b, "        return %s\n", sinks)
+	}
+	b.WriteString("    end\n\n")
+
+	b.WriteString("    return build, patch\n")
+	b.WriteString("end\n")
+	return b.String()
+}
+
+// sinks returns what patch returns for the engine to mount: one output for both channels or one for each
+func (e *rackExport) sinks() string {
+	var left, right string
+	for _, in := range e.engine {
+		switch in.name {
+		case "left":
+			left = inputExpr(in)
+		case "right":
+			right = inputExpr(in)
+		}
+	}
+	switch {
+	case left == "" && right == "":
+		return ""
+	case left == right:
+		return left
+	}
+	if left == "" {
+		left = "0"
+	}
+	if right == "" {
+		right = "0"
+	}
+	return left + ", " + right
+}
+
+// node is a table within the table returned by build; it holds either a module or more tables
+type node struct {
+	module   *exported
+	keys     []lua.LValue
+	children map[string]*node
+}
+
+func (e *rackExport) tree() *node {
+	root := &node{children: map[string]*node{}}
+	for _, m := range e.modules {
+		n := root
+		for _, k := range m.path {
+			child, ok := n.children[keyID(k)]
+			if !ok {
+				child = &node{children: map[string]*node{}}
+				n.children[keyID(k)] = child
+				n.keys = append(n.keys, k)
+			}
+			n = child
+		}
+		n.module = m
+	}
+	return root
+}
+
+func keyID(k lua.LValue) string {
+	return k.Type().String() + ":" + k.String()
+}
+
+func (e *rackExport) writeTree(b *bytes.Buffer, n *node, depth int) {
+	if n.module != nil {
+		e.writeConstructor(b, n.module)
+		return
+	}
+	if len(n.keys) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	keys := append([]lua.LValue{}, n.keys...)
+	sortKeys(keys)
+
+	indent := strings.Repeat("    ", depth+1)
+	b.WriteString("{\n")
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s%s = ", indent, keyExpr(k))
+		e.writeTree(b, n.children[keyID(k)], depth+1)
+		b.WriteString(",\n")
+	}
+	b.WriteString(strings.Repeat("    ", depth) + "}")
+}
+
+// writeConstructor writes the call creating a module. Config values that can't be written as Lua are left out with a
+// warning.
+func (e *rackExport) writeConstructor(b *bytes.Buffer, m *exported) {
+	fmt.Fprintf(b, "synth.%s", m.module.Type())
+	config := map[interface{}]interface{}{}
+	for k, v := range m.module.Config() {
+		if _, err := literal(v); err != nil {
+			e.warnings = append(e.warnings, fmt.Sprintf("%s: config %s can't be exported: %s", pathExpr("rack", m.path), k, err))
+			continue
+		}
+		config[k] = v
+	}
+	if len(config) == 0 {
+		b.WriteString("()")
+		return
+	}
+	src, _ := literal(config)
+	b.WriteString(" ")
+	b.WriteString(src)
+}
+
+// literal returns the Lua source of a config value
+func literal(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "nil", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return strconv.Quote(v), nil
+	case float64:
+		return formatNumber(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case []interface{}:
+		return listLiteral(len(v), func(i int) interface{} { return v[i] })
+	case []string:
+		return listLiteral(len(v), func(i int) interface{} { return v[i] })
+	case map[string]interface{}:
+		generic := map[interface{}]interface{}{}
+		for k, e := range v {
+			generic[k] = e
+		}
+		return literal(generic)
+	case map[interface{}]interface{}:
+		keys := []lua.LValue{}
+		values := map[string]interface{}{}
+		for k, e := range v {
+			var key lua.LValue
+			switch k := k.(type) {
+			case float64:
+				key = lua.LNumber(k)
+			case int:
+				key = lua.LNumber(k)
+			default:
+				key = lua.LString(fmt.Sprint(k))
+			}
+			keys = append(keys, key)
+			values[keyID(key)] = e
+		}
+		sortKeys(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			src, err := literal(values[keyID(k)])
+			if err != nil {
+				return "", err
+			}
+			parts[i] = keyExpr(k) + " = " + src
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	default:
+		return "", fmt.Errorf("%T has no Lua equivalent", v)
+	}
+}
+
+func listLiteral(n int, at func(int) interface{}) (string, error) {
+	parts := make([]string, n)
+	for i := range parts {
+		src, err := literal(at(i))
+		if err != nil {
+			return "", err
+		}
+		parts[i] = src
+	}
+	return "{ " + strings.Join(parts, ", ") + " }", nil
+}
+
+func inputList(inputs []exportedInput) string {
+	parts := make([]string, len(inputs))
+	for i, in := range inputs {
+		parts[i] = keyExpr(lua.LString(in.name)) + " = " + inputExpr(in)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// inputExpr returns the Lua source of whatever an input reads from
+func inputExpr(in exportedInput) string {
+	s := in.state
+	if in.source != nil {
+		return fmt.Sprintf("%s:out(%s)", pathExpr("rack", in.source.path), strconv.Quote(s.Output))
+	}
+	if s.Unit == module.UnitPitch {
+		return fmt.Sprintf("value.pitch(%s)", strconv.Quote(s.Pitch))
+	}
+	if s.Value == nil {
+		return "0"
+	}
+	v := formatNumber(*s.Value)
+	switch s.Unit {
+	case module.UnitHz:
+		return fmt.Sprintf("value.hz(%s)", v)
+	case module.UnitMS:
+		return fmt.Sprintf("value.ms(%s)", v)
+	case module.UnitBPM:
+		return fmt.Sprintf("value.bpm(%s)", v)
+	}
+	return v
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// pathExpr returns the Lua expression reaching a path of keys from a variable
+func pathExpr(root string, path []lua.LValue) string {
+	expr := root
+	for _, k := range path {
+		if s, ok := k.(lua.LString); ok && isIdentifier(string(s)) {
+			expr += "." + string(s)
+		} else {
+			expr += "[" + keyLiteral(k) + "]"
+		}
+	}
+	return expr
+}
+
+// keyExpr returns the Lua source of a key within a table constructor
+func keyExpr(k lua.LValue) string {
+	if s, ok := k.(lua.LString); ok && isIdentifier(string(s)) {
+		return string(s)
+	}
+	return "[" + keyLiteral(k) + "]"
+}
+
+func keyLiteral(k lua.LValue) string {
+	if n, ok := k.(lua.LNumber); ok {
+		return formatNumber(float64(n))
+	}
+	return strconv.Quote(k.String())
+}
+
+func isIdentifier(s string) bool {
+	return identifier.MatchString(s) && !luaKeywords[s]
+}
+
+// sortKeys orders numeric keys before string keys
+func sortKeys(keys []lua.LValue) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, aNum := keys[i].(lua.LNumber)
+		b, bNum := keys[j].(lua.LNumber)
+		switch {
+		case aNum && bNum:
+			return a < b
+		case aNum != bNum:
+			return aNum
+		}
+		return keys[i].String() < keys[j].String()
+	})
+}
@@ -0,0 +1,115 @@
+package lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestSessionExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-export")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(`
+		local synth = require('eolian.synth')
+		osc = synth.Oscillator { multiplier = 2 }
+		proc = synth.LuaProcessor {
+			script  = "function process(inputs, outputs, size)\nend",
+			outputs = { "a", "b" },
+		}
+		osc:set { pitch = hz(440), pitchMod = synth.Oscillator():out('sine'), pitchModAmount = 0.5 }
+		proc:set { input = osc:out('saw') }
+		Engine:set { input = proc:out('a') }
+	`), nil)
+
+	first := filepath.Join(dir, "first.lua")
+	assert.Equal(t, vm.DoString(fmt.Sprintf("session.export('%s')", first)), nil)
+	raw, err := ioutil.ReadFile(first)
+	assert.Equal(t, err, nil)
+	src := string(raw)
+
+	for _, line := range []string{
+		`osc = synth.Oscillator { multiplier = 2 },`,
+		`oscillator1 = synth.Oscillator(),`,
+		`proc = synth.LuaProcessor { outputs = { "a", "b" }, script = "function process(inputs, outputs, size)\nend" },`,
+		`rack.osc:set { pitch = value.hz(440), pitchMod = rack.oscillator1:out("sine"), pitchModAmount = 0.5 }`,
+		`rack.proc:set { input = rack.osc:out("saw") }`,
+	} {
+		assert.Equal(t, strings.Contains(src, line), true)
+	}
+
+	// Loading the exported rack and exporting it again gives the same rack
+	loaded := newVM(t)
+	defer loaded.Close()
+	assert.Equal(t, loaded.DoString(fmt.Sprintf("Rack.load('%s')", first)), nil)
+
+	second := filepath.Join(dir, "second.lua")
+	assert.Equal(t, loaded.DoString(fmt.Sprintf("session.export('%s')", second)), nil)
+	again, err := ioutil.ReadFile(second)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(again), src)
+}
+
+func TestSessionExportNestedRack(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-export")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	rack := filepath.Join(dir, "rack.lua")
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(fmt.Sprintf(rebuiltRack, 3, "")), 0644), nil)
+
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s')", rack)), nil)
+
+	exported := filepath.Join(dir, "exported.lua")
+	assert.Equal(t, vm.DoString(fmt.Sprintf("session.export('%s')", exported)), nil)
+	raw, err := ioutil.ReadFile(exported)
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, string(raw), `-- Exported from an eolian session
+return function(env)
+    local synth = require('eolian.synth')
+    local value = require('eolian.value')
+
+    local function build()
+        return {
+            lfo = synth.Oscillator { multiplier = 3 },
+            osc = synth.Oscillator(),
+            voices = {
+                [1] = synth.Direct(),
+                [2] = synth.Direct(),
+            },
+        }
+    end
+
+    local function patch(rack)
+        rack.voices[1]:set { input = rack.osc:out("sine") }
+    end
+
+    return build, patch
+end
+`)
+}
+
+func TestSessionExportUnexportableConfig(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(`
+		local synth = require('eolian.synth')
+		osc = synth.Oscillator { multiplier = 2, callback = function() end }
+	`), nil)
+
+	src, warnings := exportSession(vm.LState, vm.sched)
+	assert.Equal(t, strings.Contains(src, `osc = synth.Oscillator { multiplier = 2 },`), true)
+	assert.Equal(t, len(warnings), 1)
+	assert.Equal(t, strings.HasPrefix(warnings[0], "rack.osc: config callback can't be exported"), true)
+}
@@ -10,6 +10,7 @@ func preloadFilepath(state *lua.LState) int {
 	mod := state.NewTable()
 	state.SetFuncs(mod, map[string]lua.LGFunction{
 		"dir": dir,
+		"ext": ext,
 	})
 	state.Push(mod)
 	return 1
@@ -19,3 +20,8 @@ func dir(state *lua.LState) int {
 	state.Push(lua.LString(filepath.Dir(state.CheckString(1))))
 	return 1
 }
+
+func ext(state *lua.LState) int {
+	state.Push(lua.LString(filepath.Ext(state.CheckString(1))))
+	return 1
+}
@@ -0,0 +1,227 @@
+package lua
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+)
+
+type graph struct {
+	Modules     []graphModule     `json:"modules"`
+	Connections []graphConnection `json:"connections"`
+}
+
+type graphModule struct {
+	Path    string       `json:"path"`
+	ID      string       `json:"id"`
+	Type    string       `json:"type"`
+	Inputs  []graphInput `json:"inputs"`
+	Outputs []string     `json:"outputs"`
+	Orphan  bool         `json:"orphan"`
+}
+
+type graphInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+type graphConnection struct {
+	From   string `json:"from"`
+	Output string `json:"output"`
+	To     string `json:"to"`
+	Input  string `json:"input"`
+}
+
+func preloadGraph(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			"dot":  graphWriter(exec, encodeDOT),
+			"json": graphWriter(exec, encodeJSON),
+		})
+		state.Push(mod)
+		return 1
+	}
+}
+
+// graphWriter writes the topology of a set of modules (keyed by their path in the rack) to a file
+func graphWriter(exec module.Executor, encode func(graph) ([]byte, error)) lua.LGFunction {
+	return func(state *lua.LState) int {
+		name := state.CheckString(1)
+		modules := rackPatchers(state, state.CheckTable(2))
+
+		var g graph
+		exec.Exec(func() error {
+			g = buildGraph(modules)
+			return nil
+		})
+
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			state.RaiseError("absolute path %s: %s", name, err)
+		}
+		raw, err := encode(g)
+		if err != nil {
+			state.RaiseError("encode graph: %s", err)
+		}
+		if err := ioutil.WriteFile(abs, raw, 0660); err != nil {
+			state.RaiseError("write %s: %s", abs, err)
+		}
+		fmt.Printf("Graph written to %s\n", abs)
+		return 0
+	}
+}
+
+func buildGraph(modules map[string]module.Patcher) graph {
+	g := graph{Modules: []graphModule{}, Connections: []graphConnection{}}
+
+	paths := map[string]string{}
+	for path, p := range modules {
+		paths[p.ID()] = path
+	}
+
+	// Modules feeding the rack that aren't part of it are added to the graph by their ID
+	external := map[string]bool{}
+	connected := map[string]bool{}
+
+	for path, p := range modules {
+		gm := graphModule{Path: path, ID: p.ID(), Type: p.Type(), Inputs: []graphInput{}, Outputs: []string{}}
+		for name, in := range p.Inputs() {
+			is, ok := in.State()
+			if !ok || !is.IsConnection() {
+				gm.Inputs = append(gm.Inputs, graphInput{Name: name, Value: in.SourceName()})
+				continue
+			}
+			gm.Inputs = append(gm.Inputs, graphInput{Name: name})
+
+			from, ok := paths[is.Module]
+			if !ok {
+				from = is.Module
+				external[from] = true
+			}
+			g.Connections = append(g.Connections, graphConnection{
+				From:   from,
+				Output: is.Output,
+				To:     path,
+				Input:  name,
+			})
+			connected[from], connected[path] = true, true
+		}
+		for name := range p.Outputs() {
+			gm.Outputs = append(gm.Outputs, name)
+		}
+		sort.Slice(gm.Inputs, func(i, j int) bool { return gm.Inputs[i].Name < gm.Inputs[j].Name })
+		sort.Strings(gm.Outputs)
+		g.Modules = append(g.Modules, gm)
+	}
+
+	for id := range external {
+		outputs := []string{}
+		for _, c := range g.Connections {
+			if c.From == id {
+				outputs = append(outputs, c.Output)
+			}
+		}
+		sort.Strings(outputs)
+		g.Modules = append(g.Modules, graphModule{Path: id, ID: id, Inputs: []graphInput{}, Outputs: uniq(outputs)})
+	}
+
+	for i, m := range g.Modules {
+		g.Modules[i].Orphan = !connected[m.Path]
+	}
+
+	sort.Slice(g.Modules, func(i, j int) bool { return g.Modules[i].Path < g.Modules[j].Path })
+	sort.Slice(g.Connections, func(i, j int) bool {
+		a, b := g.Connections[i], g.Connections[j]
+		if a.To == b.To {
+			return a.Input < b.Input
+		}
+		return a.To < b.To
+	})
+	return g
+}
+
+func encodeJSON(g graph) ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+func encodeDOT(g graph) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("digraph rack {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=record];\n")
+
+	ports := map[string]string{}
+	for _, m := range g.Modules {
+		inputs := []string{}
+		for i, in := range m.Inputs {
+			port := fmt.Sprintf("in%d", i)
+			ports[m.Path+"\x00in\x00"+in.Name] = port
+			label := in.Name
+			if in.Value != "" {
+				label += ": " + in.Value
+			}
+			inputs = append(inputs, fmt.Sprintf("<%s> %s", port, escapeRecord(label)))
+		}
+		outputs := []string{}
+		for i, name := range m.Outputs {
+			port := fmt.Sprintf("out%d", i)
+			ports[m.Path+"\x00out\x00"+name] = port
+			outputs = append(outputs, fmt.Sprintf("<%s> %s", port, escapeRecord(name)))
+		}
+
+		title := escapeRecord(m.Path)
+		if m.Type != "" {
+			title += `\n` + escapeRecord(m.Type)
+		}
+		label := fmt.Sprintf("{%s}|{%s}|{%s}", strings.Join(inputs, "|"), title, strings.Join(outputs, "|"))
+
+		style := ""
+		if m.Orphan {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q [label=\"%s\"%s];\n", m.Path, label, style)
+	}
+
+	for _, c := range g.Connections {
+		fmt.Fprintf(&b, "  %q:%s -> %q:%s;\n",
+			c.From, ports[c.From+"\x00out\x00"+c.Output],
+			c.To, ports[c.To+"\x00in\x00"+c.Input])
+	}
+
+	b.WriteString("}\n")
+	return b.Bytes(), nil
+}
+
+var recordEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+)
+
+func escapeRecord(s string) string {
+	return recordEscaper.Replace(s)
+}
+
+func uniq(strs []string) []string {
+	result := []string{}
+	for i, s := range strs {
+		if i > 0 && strs[i-1] == s {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package lua
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+	assert "gopkg.in/go-playground/assert.v1"
+)
+
+func TestGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-graph")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	vm := newVM(t)
+	defer vm.Close()
+
+	vm.SetGlobal("dir", lua.LString(dir))
+
+	err = vm.DoString(`
+		local synth = require('eolian.synth')
+
+		local osc    = synth.Oscillator()
+		local filter = synth.Filter()
+		local unused = synth.Noise()
+		Rack.modules = { osc = osc, filter = filter, unused = { noise = unused } }
+
+		osc:set { pitch = hz(440) }
+		filter:set { input = osc:out('saw') }
+		Engine:set { input = filter:out('lowpass') }
+
+		Rack.graph(dir .. '/rack.dot')
+		Rack.graph(dir .. '/rack.json')
+	`)
+	assert.Equal(t, err, nil)
+
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "rack.json"))
+	assert.Equal(t, err, nil)
+
+	var g graph
+	assert.Equal(t, json.Unmarshal(raw, &g), nil)
+	assert.Equal(t, len(g.Modules), 4)
+	assert.Equal(t, g.Connections, []graphConnection{
+		{From: "filter", Output: "lowpass", To: "engine", Input: "input"},
+		{From: "osc", Output: "saw", To: "filter", Input: "input"},
+	})
+
+	orphans := []string{}
+	for _, m := range g.Modules {
+		if m.Orphan {
+			orphans = append(orphans, m.Path)
+		}
+	}
+	assert.Equal(t, orphans, []string{"unused.noise"})
+
+	raw, err = ioutil.ReadFile(filepath.Join(dir, "rack.dot"))
+	assert.Equal(t, err, nil)
+	dot := string(raw)
+	assert.Equal(t, strings.HasPrefix(dot, "digraph rack {"), true)
+	assert.Equal(t, strings.Contains(dot, `"osc":out`), true)
+	assert.Equal(t, strings.Contains(dot, `"unused.noise" [label=`), true)
+}
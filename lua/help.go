@@ -0,0 +1,36 @@
+package lua
+
+import (
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+)
+
+func preloadHelp(state *lua.LState) int {
+	mod := state.NewTable()
+	state.SetFuncs(mod, map[string]lua.LGFunction{
+		"describe": describe,
+		"summary":  summary,
+	})
+	state.Push(mod)
+	return 1
+}
+
+func describe(state *lua.LState) int {
+	help, err := module.Help(state.CheckString(1))
+	if err != nil {
+		state.RaiseError("%s", err)
+	}
+	state.Push(lua.LString(help))
+	return 1
+}
+
+// summary returns the one-line description of a module type, or nothing if it isn't described
+func summary(state *lua.LState) int {
+	m, err := module.LookupMetadata(state.CheckString(1))
+	if err != nil {
+		return 0
+	}
+	state.Push(lua.LString(m.Description))
+	return 1
+}
@@ -0,0 +1,167 @@
+// Package instrument makes Lua chunks count the steps they take. The embedded interpreter has no hooks to interrupt a
+// script, so chunks are rewritten instead: every loop iteration and function call calls a step function that can
+// raise an error to stop the script.
+package instrument
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// StepName is the global that instrumented chunks call on each step. It can't be written in Lua source, so scripts
+// can't reach it.
+const StepName = "(step)"
+
+// heapMetric is the size of the live and not yet swept objects on the heap. Reading it doesn't stop the world, but it
+// still costs far more than a step, so it's only read every heapInterval steps.
+const (
+	heapMetric   = "/memory/classes/heap/objects:bytes"
+	heapInterval = 100
+)
+
+// Limits bounds the work of an instrumented script
+type Limits struct {
+	// Steps counts loop iterations and function calls
+	Steps int64
+	// Memory is how much the heap may grow, in bytes
+	Memory uint64
+}
+
+// Counter counts the steps of instrumented chunks and checks the heap every so many of them
+type Counter struct {
+	Limits Limits
+
+	steps    int64
+	baseline uint64
+	heap     []metrics.Sample
+}
+
+// Reset starts counting from zero, measuring the heap's growth from its current size
+func (c *Counter) Reset() {
+	c.steps, c.baseline = 0, c.HeapAlloc()
+}
+
+// Step counts a step. It returns an error once the limits are exceeded.
+func (c *Counter) Step() error {
+	c.steps++
+	if c.Limits.Steps > 0 && c.steps > c.Limits.Steps {
+		return fmt.Errorf("went past the sandbox's limit of %d steps", c.Limits.Steps)
+	}
+	if c.steps%heapInterval == 0 {
+		return c.CheckMemory()
+	}
+	return nil
+}
+
+// CheckMemory returns an error if the heap grew past the memory limit since Reset. Steps only check it now and then,
+// so it's worth calling once the script returns too.
+func (c *Counter) CheckMemory() error {
+	if c.Limits.Memory > 0 {
+		if used := c.HeapAlloc(); used > c.baseline && used-c.baseline > c.Limits.Memory {
+			return fmt.Errorf("went past the sandbox's memory limit of %dMB", c.Limits.Memory>>20)
+		}
+	}
+	return nil
+}
+
+// HeapAlloc returns the size of the heap
+func (c *Counter) HeapAlloc() uint64 {
+	if c.heap == nil {
+		c.heap = []metrics.Sample{{Name: heapMetric}}
+	}
+	metrics.Read(c.heap)
+	return c.heap[0].Value.Uint64()
+}
+
+// Compile parses and compiles a chunk that calls StepName on each step
+func Compile(src, name string) (*lua.FunctionProto, error) {
+	chunk, err := parse.Parse(strings.NewReader(src), name)
+	if err != nil {
+		return nil, err
+	}
+	return lua.Compile(instrumentBlock(chunk, 0), name)
+}
+
+// instrumentBlock adds a step at the start of a block and instruments the functions and loops within it
+func instrumentBlock(stmts []ast.Stmt, line int) []ast.Stmt {
+	instrumentStmts(stmts)
+	call := &ast.FuncCallExpr{Func: &ast.IdentExpr{Value: StepName}}
+	call.SetLine(line)
+	call.Func.SetLine(line)
+	step := &ast.FuncCallStmt{Expr: call}
+	step.SetLine(line)
+	return append([]ast.Stmt{step}, stmts...)
+}
+
+func instrumentStmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			instrumentExprs(s.Lhs...)
+			instrumentExprs(s.Rhs...)
+		case *ast.LocalAssignStmt:
+			instrumentExprs(s.Exprs...)
+		case *ast.FuncCallStmt:
+			instrumentExprs(s.Expr)
+		case *ast.DoBlockStmt:
+			instrumentStmts(s.Stmts)
+		case *ast.WhileStmt:
+			instrumentExprs(s.Condition)
+			s.Stmts = instrumentBlock(s.Stmts, s.Line())
+		case *ast.RepeatStmt:
+			instrumentExprs(s.Condition)
+			s.Stmts = instrumentBlock(s.Stmts, s.Line())
+		case *ast.IfStmt:
+			instrumentExprs(s.Condition)
+			instrumentStmts(s.Then)
+			instrumentStmts(s.Else)
+		case *ast.NumberForStmt:
+			instrumentExprs(s.Init, s.Limit, s.Step)
+			s.Stmts = instrumentBlock(s.Stmts, s.Line())
+		case *ast.GenericForStmt:
+			instrumentExprs(s.Exprs...)
+			s.Stmts = instrumentBlock(s.Stmts, s.Line())
+		case *ast.FuncDefStmt:
+			instrumentExprs(s.Func)
+		case *ast.ReturnStmt:
+			instrumentExprs(s.Exprs...)
+		}
+	}
+}
+
+func instrumentExprs(exprs ...ast.Expr) {
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case *ast.FunctionExpr:
+			e.Stmts = instrumentBlock(e.Stmts, e.Line())
+		case *ast.AttrGetExpr:
+			instrumentExprs(e.Object, e.Key)
+		case *ast.TableExpr:
+			for _, f := range e.Fields {
+				instrumentExprs(f.Key, f.Value)
+			}
+		case *ast.FuncCallExpr:
+			instrumentExprs(e.Func, e.Receiver)
+			instrumentExprs(e.Args...)
+		case *ast.LogicalOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.RelationalOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.StringConcatOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.ArithmeticOpExpr:
+			instrumentExprs(e.Lhs, e.Rhs)
+		case *ast.UnaryMinusOpExpr:
+			instrumentExprs(e.Expr)
+		case *ast.UnaryNotOpExpr:
+			instrumentExprs(e.Expr)
+		case *ast.UnaryLenOpExpr:
+			instrumentExprs(e.Expr)
+		}
+	}
+}
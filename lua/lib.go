@@ -113,7 +113,7 @@ func luaLibRackMountLua() (*asset, error) {
 	return a, nil
 }
 
-var _luaLibRackRackLua = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x57\xcd\x8e\xdb\x36\x10\xbe\xfb\x29\x06\xe8\x41\x32\xa0\xa8\x49\x8e\x29\xd4\x9e\x7a\x0f\x7a\xe9\xa1\x28\x02\x5a\x1a\xdb\xc4\xd2\xa4\x43\x52\x6e\x82\x85\xf3\xec\x05\x39\xfc\x19\xad\xdc\x5d\xc0\xe8\x06\x31\x10\x44\x9f\xbe\xe1\x70\xfe\x34\x33\xab\xcc\x28\x14\xec\xa5\xc2\xb3\xf0\x47\x18\xc0\xe2\xe7\x59\x5a\x6c\x1b\x34\x4a\x0a\xdd\x67\xaa\xd9\x6e\x36\x24\x8c\xfa\x22\xad\xd1\x27\xd4\x1e\x06\x78\xdc\x00\x00\x08\xe7\xd0\x7a\xa0\xdf\x90\x60\x17\xa9\xf1\x28\xb4\x46\x95\xa9\x04\x13\x67\xac\x99\xbd\xd4\x98\xb8\x0c\x89\x9d\x70\x37\x1f\xa0\x28\x8d\x30\x31\x26\x98\x55\x99\x08\x89\x42\x6b\x8d\xad\x87\x22\x24\xe6\x80\xfe\x84\x5e\x78\xb1\x53\x08\xc3\x02\x92\x80\x34\x50\x7f\x03\x48\x93\x5e\x9f\x85\xb4\xae\xbe\x8e\x90\x28\x65\xc4\xc4\x4e\x04\x58\x89\x62\xe2\x50\x60\x25\x9d\xb7\x52\x1f\x0a\x49\x90\xe8\x53\x48\x44\x55\x1a\x20\x11\x1a\xbf\x78\x46\x04\x48\x84\x71\x0b\xc3\x4d\xb2\x8e\xdb\x0d\x03\x30\xbb\xcf\xa3\x50\x8a\x31\x01\x26\xc6\x4a\xed\x19\x13\x20\x31\x56\xfc\x83\x9f\x67\xa1\x12\x93\x61\x21\x0f\x58\xf3\x4f\xb0\x50\x6e\x49\xb9\x42\x51\xad\x15\x8a\x20\x71\x0e\x15\x8e\xf5\x18\xc1\x4c\x2d\x12\xe9\x56\x89\x2c\xc1\x4d\x67\x59\x70\xe9\x50\xa1\xd8\x21\x6f\xf4\x7c\xda\xa1\xcd\x4c\x82\x99\x64\x3a\x87\x02\x13\xf9\xf5\x8c\x2c\xfc\x01\x12\x31\xeb\xb3\x18\x1f\x0a\x41\x90\xa8\x2f\x3c\x05\x43\x82\xdd\xe6\x9a\x3f\xb2\x51\x19\x17\x9c\xdb\xcf\x7a\xf4\xd2\xe8\xf6\xb2\xa5\x62\xdc\x47\xfd\xed\x65\x0b\xdf\x06\x68\xa2\xfd\x0d\xf8\x23\xea\x4d\xb6\xc0\xa2\x9f\x2d\x41\xd4\xd3\xf2\x54\x1f\xf5\x6e\x61\x18\xa0\xc9\xaa\x9f\x1c\xbf\x7c\x88\x32\xed\xf6\x39\x85\x7b\x63\xe1\x53\x07\x0e\xa4\xa6\xba\x0a\x06\x4d\x86\xcc\x6e\xdd\x36\x0a\x86\x7f\xc9\x1d\xe7\x85\xf5\x1f\x85\x1f\x8f\xaf\xe0\x53\x55\xfe\x82\x63\x55\xf0\x3e\xef\xd8\xf9\xb5\x8b\x7b\xa9\xa5\x3b\xbe\x96\x8f\x4c\xfb\x0b\x4e\x32\xc9\xfb\xbc\xe4\x0a\xd6\x6e\x9e\xcc\x1c\xfb\x7e\x71\xd0\x49\xfd\xe0\x8a\x93\x3f\x45\x18\x2c\x7c\xb7\x34\xec\x77\x7d\x90\x1a\x3f\x84\x56\xf0\x08\x0a\xf7\x41\x47\x94\xfd\xeb\xdd\xdf\x1d\x58\x79\x38\xf2\x37\x70\x25\x53\x95\xc3\x85\xd2\xf7\xf7\x2b\x7d\x7f\x5b\xe9\xaf\x4f\x75\xc6\x89\xd1\x36\xde\x18\x38\x09\xfd\x35\x45\x0e\x2e\x42\xcd\xe8\x60\x6f\xcd\x09\xce\x21\x36\xcd\xb6\x44\x33\x86\xe7\x8f\xf0\xa9\xe7\x79\x88\xfa\x52\x9e\x63\xac\xeb\x80\x6d\x9a\xae\xbc\x3e\xe7\x56\x4f\xaf\xcb\xfb\x37\x6f\xe0\x4f\xa9\x14\xec\x10\x2c\x9e\xcc\x05\x27\x70\xc6\xe8\xbe\xef\x59\x42\xa9\x77\xf2\x4c\xa0\xda\x77\x51\x67\xcd\x7b\xcd\x7d\x9a\x92\x51\xaa\x8f\x17\xf7\x3d\x34\x3f\x37\xe1\xbf\xe5\x19\xd4\x13\xb3\x25\xcd\xda\xd7\xb9\x27\x3e\x5f\xd3\xdc\x33\xd3\xac\xd0\x85\xc9\x26\x55\xe8\x84\xf9\x42\x08\xa1\xed\x47\x85\xc2\xa6\x8a\xbe\x95\xf7\xb7\x35\xe1\x6f\xe1\x4a\x39\x59\x6a\xd8\xcd\x52\x4d\x49\x03\x6d\x28\x6d\x7c\x9f\x2f\xfe\x16\x6f\xee\xa0\xd1\x06\xac\x18\x1f\xa2\xdf\x61\x3a\xe3\xd4\x87\xf5\x27\x9c\x5b\x99\x42\x1d\x8f\xeb\x49\x92\xf4\xb9\xc4\x3b\x3b\xaa\x98\x2e\xbb\xc8\x78\xe7\x85\x9f\x5d\x17\xaa\xae\x03\x8b\x6e\x56\xbe\x8c\x83\xb6\x44\xbc\xc6\xac\xee\x6b\x65\xef\xa1\xcb\x51\x5f\xca\xae\x56\xc5\x1d\xfa\x3d\xea\x4b\x1b\x97\x0f\xbe\xb9\x55\x11\x6e\x61\xc8\x32\xd7\x58\xa5\x6a\x72\x78\x14\x43\xa5\xd0\x5e\xd6\x7b\x2b\x46\xdc\x89\xf1\xa1\xb4\x02\x6d\x7c\x4b\x1e\x6d\x41\xe8\x29\xb8\x98\x42\xbc\xfc\xe0\xe2\x8e\xd1\xa2\xb5\xff\xd9\xad\x6a\xe4\xab\x19\xeb\x50\xd2\x5d\x31\x94\x79\xa7\xb9\x15\x41\xd6\xc4\x97\x59\x5b\x86\x38\xb5\x1c\x78\x3c\xaf\x45\xaf\xec\xbb\xae\xbd\xf2\xb6\xb6\xd8\x2f\x79\x93\x44\x3d\xf1\x10\xe5\xa4\x3f\x17\x92\xd2\x63\x96\xf5\x7c\x66\x3d\xfe\xde\x7a\x26\x67\xa3\xa6\x1f\xaa\x2a\x3f\x3d\x5b\x91\xdf\xb3\xee\xee\x8b\xc8\x0f\x50\x65\xff\x7b\x84\x9e\x6c\x02\xc6\xca\x83\xd4\x42\x7d\xa4\xb9\x16\x76\x5b\x71\xc0\xd8\xf2\x9f\x56\x6a\x28\xba\xb6\xd6\x00\x17\x4d\x09\x0e\x8f\xfd\x24\x2d\x49\xd1\xc8\xf8\xad\x57\xb3\xf8\x25\x0e\x0e\x7e\x17\x6b\x07\xbc\xbc\xe8\x4f\x1c\x7f\x5c\xb2\x65\xc2\xde\xbc\x87\x67\x78\x59\xbd\xd5\xd8\x17\x8a\x75\xdd\xe6\xd7\x25\xbb\xee\x5f\xd9\x24\x6a\xa6\xcb\xe2\xe6\x95\x51\x04\x6f\x17\xc8\x66\x95\xfb\x90\x9f\x7f\x03\x00\x00\xff\xff\xae\x2d\xbe\x29\xd4\x0f\x00\x00")
+var _luaLibRackRackLua = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x3b\x7f\xaf\xdb\x38\x72\xff\xfb\x53\x0c\x5e\x7b\x90\x0d\xe8\xa9\xb7\x01\xfa\xcf\x1e\xdc\x62\x7b\x97\xa2\x07\xdc\x16\xc1\x76\x8b\x6b\x91\x3c\x04\xb4\x34\xb6\x59\x4b\xa4\x8f\xa4\xec\x18\xc1\xbb\xcf\x5e\x0c\x39\xa4\x28\x59\xb6\x37\x49\x17\xb8\xee\x7b\x79\x6b\x71\x86\x33\xc3\xe1\x70\x7e\x51\x6e\x75\x2d\x5a\xd8\x08\x57\xef\x81\xfe\x5b\x83\xc1\xbf\xf4\xd2\xe0\xb2\x40\xdd\x4a\xa1\x2a\x23\xea\x43\xe5\x11\x8a\xd5\x22\xa0\x6f\x65\x8b\x47\xe1\xf6\x73\xe8\x11\x96\x90\x77\x46\x1c\xef\xd2\xf6\x08\x09\xfd\x68\x34\x91\xb8\x8d\xce\x08\x69\x82\x15\xaa\xd9\xe8\x4f\xb3\x13\x18\x36\xe0\x2a\x71\xb4\x7b\xed\x6e\x12\x8f\x08\xc3\x8c\x8b\x72\xb7\xa4\xf7\xb0\x84\xe9\xc4\xe6\x6c\xa4\x43\x33\x83\x99\x60\x03\x36\xb1\x43\x73\x9b\x6e\xc5\x18\x69\xc6\xf9\xd1\x1e\x9d\x79\x8f\x16\xcf\xcf\xf0\xaf\xb2\x45\x0b\xad\x16\x0d\x36\xb0\xb9\x80\xdb\x23\x10\x3d\x10\x06\x99\x77\x03\x56\xd3\xf8\x05\x6a\xa1\x60\x83\xe0\xe7\x63\x03\x5b\x6d\xa0\xde\x0b\xb5\x43\xcb\xac\x99\x5d\x03\x6b\xf8\xfc\xba\xe0\x41\xa6\xf2\x07\xbf\x1b\xb0\x86\x6d\xaf\x6a\x27\xb5\x5a\xd2\xee\xaf\x16\x24\xa9\xa7\x18\x16\x92\x8d\x1a\x74\xbd\x51\xd0\xf8\x89\x3c\x8e\xaa\x99\xd0\xfd\x29\xf0\xcc\x09\x2b\xd1\x61\x20\xcc\xa6\x42\x26\xb8\x66\x2e\x06\xad\x6e\x4f\x98\x21\xc9\x2d\x10\x71\xf8\xeb\x1a\x94\x6c\x69\xa9\xca\x8f\xdf\x96\x8c\x7e\xe2\x5a\xdf\x13\xa1\x17\x58\x83\x33\x3d\xfa\x79\x24\x63\x26\x3f\x23\x32\x43\x02\x92\xe2\x7f\x22\x25\xd3\xc2\x2c\x1c\x8d\x54\x0e\xdc\xde\xe8\x7e\xb7\x87\xf3\x5e\x38\x3c\xa1\x21\x39\x60\xd7\xea\x8d\xb7\x75\xc2\x90\x16\xce\x7b\x54\x04\xb8\x80\xe9\x55\x49\x1b\x83\x8d\x74\xda\x58\xc0\x93\x68\x7b\xe1\xa4\xda\x41\xad\x1b\x04\x4d\x24\x9e\x5b\x69\x1d\x2a\xd8\xa1\x23\x9e\x6e\x8f\xd2\x80\xee\xdd\xb1\x77\xac\xc4\xa8\x33\xe6\xf4\x8e\x44\x59\x56\x55\x35\xda\x81\xe3\x30\x9a\xe9\x1f\xd5\x49\x1a\xad\x3a\x54\x8e\xf6\xdb\x4f\x10\xd6\xa2\x71\xac\xa1\x35\x3f\x96\x1e\x44\x96\xa2\xb0\x8d\x20\x7e\x64\x98\x36\xba\x77\x52\x21\xc3\xe2\x63\x80\x36\xb8\xe9\x77\x4c\x13\xd6\xe1\x91\x21\xd1\x05\xd0\xcf\x3a\x5a\x44\xb0\xb4\x80\x81\xc6\x68\xc3\x08\xb0\x0e\x8f\x01\xb2\x43\xd7\xa1\x13\x4e\x6c\x5a\x84\xf5\xe8\x31\x20\x48\x1d\xe7\xf9\xa9\x52\xf3\xf0\x51\x48\x63\x87\x61\xff\x58\xb2\xb5\x89\x86\x01\x34\x83\x1e\x07\x40\x92\x74\x9d\x1e\x07\xa0\x75\x86\x76\x2e\x02\xc3\x63\x00\x77\x82\xfd\x0a\xfd\xac\xfd\x63\x00\x28\xfc\x14\x35\x4d\x00\x7a\x0c\x00\x1d\xa5\xa3\x9f\x35\x68\x1b\x86\x73\xb9\x61\x0d\x99\xdc\xc7\x5a\xb4\xbc\x31\x34\xc1\x3f\x32\x84\x76\x7e\x80\x64\x46\x12\xe0\x46\x9c\xf1\x2f\xbd\x68\x19\x1e\x1f\x13\x70\x87\x71\xf6\x9a\x1f\x13\xc8\x8e\x41\x36\x81\xf8\x3c\x8f\xf7\x94\x4f\x79\x40\xb1\xd8\x62\x3d\xcc\x0e\x8f\x11\x34\xda\xd5\xfc\x91\x11\xa2\xa6\x79\x6e\xa6\x69\xbf\xf7\x0c\x21\xce\xc3\x24\xa7\x55\xdf\x6d\xbc\x17\xa6\x49\xf1\x31\x02\xd3\xee\x05\x60\xbe\x7b\xee\x72\x8c\xf6\xe9\x81\x97\x23\x93\xec\xd5\x91\x3c\x40\x04\x84\xc7\x00\xfa\x94\xef\xc7\x9a\x1f\xcb\xc5\xeb\x82\xce\xf0\x9f\xe4\xc6\x08\x23\xd1\x82\x00\x8e\x5a\xd8\x04\x97\xdd\x89\x4b\xd4\x5e\x05\x3f\xa8\x8b\xdb\xd3\x4a\xb1\xb5\x08\xd2\x45\x88\x85\xae\xb7\x8e\xdc\xb8\x80\x3f\xf5\x02\xc8\x0e\xe1\x2c\x09\x37\x39\xff\xc2\x42\x23\x0d\xd6\x4e\x9b\x4b\xc5\xa7\x9d\x79\x0d\xec\xe3\x91\x7f\x7f\x15\xd0\x5f\xa2\xe4\xe4\x11\xcb\x09\x52\xaf\xea\xe2\x05\x72\x95\x5c\x23\x1d\x85\x73\x68\x54\xf1\x72\x0f\x89\x96\x5c\x75\xba\x57\xae\x78\xb9\x8f\x44\xce\x04\xef\x20\x59\x6d\xdc\x43\x99\xc2\xa6\x16\x2f\xf7\x91\x28\x20\x17\x2f\x0f\x28\x11\x52\x55\x6b\xe5\x8c\x6e\x8b\x97\x7b\x48\x47\xa3\x3f\x5d\x8a\x97\x5b\x94\xdc\x1e\xb5\xb9\x3c\x90\x89\xe2\x02\xce\xc8\x14\xcc\xa9\xd6\xc7\x0b\x74\xe2\x10\xec\x69\x2f\xda\x56\x9f\xc3\xa0\xde\x82\x80\xd6\x6f\xf7\xc5\x47\x9a\x2b\x7b\xab\x85\x2a\x1c\x67\x00\x64\x61\x5b\x6d\x80\x22\xd7\x45\x2b\xf4\x66\x37\x8d\x31\x44\x77\xd9\xca\x4d\x1e\x9b\x0d\xda\xbe\xf5\x01\xe4\xd5\x8f\x12\x91\x43\x09\x27\x90\x2a\xb8\x28\x3f\x01\x1a\xcd\x98\xef\x0f\xa4\xb1\xd3\x75\x90\x25\x32\x29\xba\xb2\xa4\x6f\xb3\x10\x15\x10\xad\x37\xf2\x3c\x74\xe9\x2d\x98\x21\x16\x73\x26\x44\x07\x02\x9e\x99\x4a\x05\x7f\x74\xd0\xa2\x38\xa1\xa5\xe0\x09\x22\x1e\x2d\xb7\x17\x0e\x4c\xaf\x2c\xb1\xac\x75\xd7\x09\xd5\x58\xd0\x06\x0c\x8a\x7a\x8f\x96\x69\xea\xde\x59\x49\x21\x79\x3b\x7b\xc2\x4a\x10\xaa\xf1\x10\x1f\xb8\xa5\xf3\xf1\xc1\x82\xb4\xd0\xca\x4e\x3a\x6c\x48\x17\xd6\xe1\xd1\x7a\xcc\x0e\xbb\xec\x5c\x26\xe5\x5e\x2f\x79\x99\xeb\x19\xd5\x29\x1d\xd9\x07\x91\x7a\x36\x22\x1f\x2f\xcb\x34\xb6\x1a\xf0\x6e\xc7\xd7\x07\xb1\x72\x26\xb4\x79\xf3\xa0\xb1\x8c\xfe\xcd\x20\x37\x13\xe8\x3e\x43\xdd\xea\xfa\xe0\x43\x5e\xe5\x3f\x96\xd0\x08\x47\xd1\x5d\xdb\x8a\x3e\x95\xd0\xc8\xed\xd6\xc9\x2e\x8e\xf1\x53\x09\xc3\x98\xff\xf4\x3a\x30\xb9\x1d\x36\xef\x87\xce\x5f\x12\x3e\x1f\x86\xd0\x07\x61\xf4\x41\x28\x7d\x10\x2b\x67\xc2\xa1\xdf\x82\x30\x96\x6d\xc2\x24\x30\x7a\x24\x3f\x96\xe3\xdc\x0b\x91\x0f\xc3\xe4\xdd\x50\xf9\x20\x5c\xde\x09\x99\xf4\xf8\xba\xe0\xa4\xfc\xc4\x3e\xbc\x32\x78\x24\x4d\xf0\xe1\x36\x78\x0c\x18\xcf\xcf\xf0\x63\xcc\x15\xac\xaf\x7f\xec\x5e\x98\xe8\x0c\xe8\x7c\xee\xb5\x75\xb0\x0c\x54\x6c\x09\xde\xb3\xda\x12\x28\x31\x2e\xb9\x48\xf2\xa7\x1b\xb4\x6a\x2f\x94\x51\x5a\xd8\xd0\x23\xcd\xd5\x0a\x2d\x39\x49\x4a\x7b\xa4\xb3\xd8\x6e\xb3\xc3\xa9\xcf\x0a\x9b\x49\xba\xb2\xfc\xfc\x5a\xc2\x67\xf8\xf8\xb1\x23\xaf\xb0\x86\xe2\x50\xc0\xeb\x6a\x58\x4b\x86\x9a\xd7\x3e\xae\x84\xce\x0d\x55\x8a\xdc\x42\xe7\x66\x2b\x1b\xfa\xf5\x7c\xdf\x77\x6e\x54\xbe\xe4\x25\x4c\xe6\x61\x47\xb2\x65\x5c\x22\x2a\x09\xb5\xbb\x21\xd4\x69\x10\x28\x78\xa3\x8e\x3c\x7e\x8e\x9d\xa3\xc8\xad\xdf\xfc\xe5\x69\x05\xeb\x35\x14\x7e\x89\x85\x77\x7d\x83\xbc\x57\x4b\x61\x29\x3b\x77\x6f\x0d\x4a\xb6\x49\xe6\x24\xb4\xd4\x23\xc7\xa8\x8f\xa8\x72\xd9\x29\xa5\x29\x81\x36\x61\x90\x90\x7e\xa2\xfd\xd4\x7b\xbc\xaa\x0d\x33\x96\x52\x57\x44\x31\x23\x43\x01\xa2\x30\xc5\x80\x8d\x8a\xcb\x04\xfa\x6d\x25\x19\xca\x84\xff\x98\x72\x70\xdd\xcb\xac\x66\x2d\xa1\x90\xba\x0a\x53\x15\x62\x43\xc1\x9c\x7b\x2b\x5f\x27\xb2\x27\x35\xc1\x48\x62\x66\x27\xaa\xb9\x53\xd4\x3f\x2a\x9f\x3d\xaf\x28\x52\x2c\x89\x58\x4f\xa8\x4e\xab\xe5\xb5\x7d\x0d\xb5\x51\xce\xd2\x9a\xba\x84\xa1\x9c\xbf\x41\x3d\xcc\x0b\xc8\xa8\x4e\xf9\x8c\x29\x8b\x9c\xf8\x56\x4d\x69\xc7\x9e\x82\x71\x76\x48\x5b\x62\xea\x42\xc3\x14\xae\xb7\xd4\xb7\x48\x10\xb6\x6a\x0f\x24\x93\x2e\xc8\x55\x28\xd8\x18\x14\x87\xc4\x3d\xfe\x78\x7b\xaf\xa4\xe2\x4d\x36\xce\x96\x7e\xe6\x20\x41\x3e\x83\x57\x3a\xd6\xce\x32\xd0\xa8\xb5\xaa\x05\xd3\x58\x4d\x17\x9c\xb9\x1f\x4e\x7a\xd2\x62\x88\x18\x97\x0b\xb9\x2a\xc6\x6a\x90\xdb\xab\x9a\x80\x5b\x21\xb7\xce\xe6\xb8\x11\x32\xb7\x18\xb9\xe5\x04\x2c\x36\x55\x6e\xf8\xad\x51\x5b\x87\x3a\x29\x15\x89\xec\x9f\xab\x0a\x8a\x7f\x28\xa0\xaa\xa2\xa3\xae\x76\xb6\xdf\xf8\xe6\x4b\x09\xc5\x6f\xaa\xa2\x24\xf8\x6a\x45\x18\x45\xd5\xf6\xa2\x98\x50\xce\xd9\x67\x36\x3e\x31\xdf\x2f\x91\x76\x86\xee\xc8\xd9\x4e\xb5\x30\xb3\xbd\xf9\xf4\xf1\x0e\x32\x02\xaa\x53\x4a\x7e\x63\xd6\x48\x21\x91\x3c\x41\xdc\x40\xd8\x1a\xdd\x81\x18\xd2\xdd\x32\x2f\xfc\x78\x33\x0b\xce\x3a\x87\x5e\x93\xc1\xc2\x82\x56\x57\xd9\x3c\xb3\xf1\x27\x24\x75\x8c\x06\xab\xa8\x50\x91\x11\x36\xcb\xd5\x58\x23\x2c\x70\xc4\x32\xbd\x1a\x53\x88\xab\x67\xbc\xad\xca\x1a\x4f\xcf\xcf\xd0\x21\xa5\x15\x16\x6a\xdd\x52\x62\x13\xf2\xf9\x4e\x37\x3d\x05\x6d\xa9\x40\x84\xba\x9d\x7b\x99\x92\x4e\x24\x75\x2b\xcd\x54\x7a\xa6\xb3\x3c\x95\x5c\x3b\xac\x16\x93\xd8\xf3\xd7\x21\xf6\xcc\x2d\x80\x2b\x8e\x5c\x64\xb9\x85\x53\xf5\xf1\x23\x73\x9c\x8d\xba\x5c\xc4\x64\x68\x57\xe6\x70\x9b\x3e\x39\x98\x8f\x25\xd8\xa1\x36\x3a\xf9\xca\x28\xae\xc5\xa6\xb5\xa4\x29\x63\x62\x51\x89\x75\xab\x2d\xfe\xa7\xea\x2d\x36\x94\x33\x5b\xbc\xa9\x46\x5f\xe2\x08\x83\x54\xe9\xb5\xf2\x74\x5d\xd2\x0d\x94\x48\x95\x84\xf2\x85\x8a\xfc\x52\x0d\xca\x2d\x28\x1d\x84\x19\xab\x91\x12\x84\x90\x37\x50\xf2\x6f\x71\x45\xee\xa3\x88\x92\x4e\x78\xd3\xef\xe9\x7b\x8f\xc7\x71\x26\x17\xe3\x86\x74\xb7\xf4\x9f\x2b\xc1\xb2\x12\xfc\xa4\xa8\x6f\x83\xbd\x45\xb0\x67\x71\xb4\x63\x25\x2b\x3c\xb7\x17\xd8\xf4\xb2\x75\xac\x70\xe2\x91\xef\xc5\x56\xf7\xaa\x81\x5e\x35\xdc\x11\xb6\xa2\x43\x38\xe0\x25\x56\x93\xba\x6d\x78\xa6\x3f\xaf\x1b\xed\xf6\x70\x46\x83\xc4\xb6\x36\x28\x5c\x9e\xc0\xfa\xc9\xa4\x23\x9f\x4d\xd5\x5a\x6d\xe5\xae\x82\x9f\xf7\x08\x0a\xcf\x20\x95\x75\x42\xd5\xde\x18\x84\xf3\x54\xbc\xcc\x47\x6c\x42\xdd\x6b\x10\x44\x43\x17\x05\x4e\x43\x23\x6d\x2d\x4c\x83\xcd\x55\x21\xea\x17\xbb\xd4\x6d\x53\x12\xd5\x72\xc0\x5c\x25\x25\x1e\x4a\x50\x83\x12\x15\x9e\x57\x79\xb4\xe4\x9c\x98\xca\xb1\xb6\x79\x7f\x78\x49\x80\x68\x53\xea\x2a\x31\xa4\x35\x2d\xf5\x68\xf8\x6a\xbb\xc9\x70\xae\x6d\x8b\x14\xa1\xaf\x87\xaf\x66\x67\x14\xac\xdc\x29\xe1\x7a\x83\x39\x8d\x31\x60\xbd\x06\x3d\x1a\x98\xa5\x47\xbf\x0a\xcf\xa1\xa5\xa1\x17\x33\xd0\x71\x32\x90\x34\x59\x82\x1a\x87\xa2\xa9\xf1\xd2\x0f\x75\x60\x26\x6b\x5e\xcf\xaf\xf9\x5e\xe0\xe2\xdd\x2c\x41\x5d\xed\xe4\x1c\xe3\xf8\x39\x59\x3f\x77\x0f\x9d\x30\xee\x1d\xb1\x9b\x29\x0b\xbe\xda\x55\xf0\x69\x1f\x88\xdf\x3d\xf2\xa7\xef\x07\xc4\xe5\xea\x1e\xe9\x5b\xe7\x3c\x9b\x6f\x57\xd3\x25\x6e\xa5\x92\x76\xff\x6b\xad\x31\xa3\xfe\x60\x91\x19\xe6\xd7\xad\x32\x27\x90\x2f\xf3\xf9\x39\xc6\xd2\x2f\x8b\xbe\xfb\x12\x6c\x5f\xef\x41\xf8\x7e\x18\xdd\x7b\x6a\x03\x27\x2d\x6b\xb4\xef\xbf\x7b\xa9\xb4\xad\xa7\x1e\x24\xb2\xa1\x88\x72\x34\xb8\x95\x9f\xc6\x41\x9a\x5d\xc8\x54\xf2\xc5\x75\x7a\x98\x86\xa2\x26\x0f\x41\x7b\xa1\x39\x31\xd1\x1d\xfd\x72\x4e\xb9\x0c\x6c\x7d\xa9\x56\x84\x5c\xf1\xbd\x4f\x2a\x0f\xf4\xa7\x78\x29\x16\x93\x53\x96\x13\xe7\x56\xb1\x3f\x66\x9c\x83\x52\x0a\x7a\x28\xe1\xbb\x12\xde\x04\x03\xf8\xf8\xf1\x36\xf3\xc3\x68\x90\xea\x86\x20\xcc\x3d\xd7\xc4\x53\x19\x93\x64\xac\x82\xbc\x23\xcc\xb8\xf5\xd3\xcf\x93\x1b\xd0\xe4\x51\xed\x43\x8f\x1a\xd0\x06\x5f\x12\x26\xf4\x16\x4d\x23\x9c\x98\x99\x93\x25\x41\x24\x33\x79\xbe\x6c\xfa\x08\x95\x54\x7b\x35\x97\x11\x7d\xb2\x43\x04\xc6\x96\x31\xb7\xb8\xf8\xf9\x4e\x4a\xc4\x06\x43\x8d\xb9\x3f\x0b\xa3\xa4\xda\x8d\x4a\xf0\x33\x8f\x0d\xd6\xf7\xb1\x04\x8a\x97\xdc\xd1\x1c\x10\xe8\xfc\xf8\xa6\xdf\xf2\x7c\xe5\x20\xfc\x5d\x45\x4e\xd6\x4a\x75\xb0\xc9\x3d\xfc\x9d\x7f\x24\xf5\x7d\x37\xd6\xda\x5b\xb5\x93\x0a\xbf\xa7\xc6\xd1\x67\x68\x71\x4b\xad\x13\x8f\xfb\xfe\xbb\x97\x12\x8c\xdc\xed\xf3\x11\xe0\x12\x2e\x38\xff\x81\xe8\x9b\xaf\x27\xfa\x66\x9e\xe8\x3f\x4d\x69\xfa\x5e\xf0\xb2\x70\x5a\x43\x27\xd4\x25\x2a\x3a\xb4\xc9\x42\xf1\x71\xe4\x57\x0f\xe2\x7e\xd0\xbf\x05\x15\x70\xa9\x05\x33\xed\x53\xc7\x5b\x25\xa0\xb2\x79\x68\x93\x1c\x63\xeb\x38\x0c\xa7\xf1\xe7\x67\xf8\xb3\x6c\x5b\xba\xdf\x32\xd8\xe9\x93\x7f\x7b\x41\xab\xaa\xaa\x12\xca\x4c\x65\x4b\xed\xb8\x12\xae\x2b\x3c\x5e\xc1\xe8\x5e\xd9\x23\x4f\x6b\xcd\xf1\x54\xdf\x29\x49\x4f\xd7\x3d\x92\xff\x7b\x76\xfe\x33\x37\xad\xa3\x3b\xf6\xde\x82\xaf\x79\xfe\x23\x25\x22\x9c\x36\x52\xca\xd9\x24\x89\xbc\xab\xa2\x17\x0f\xa8\x43\x19\x2f\x2f\xe2\x0b\x09\xd2\x59\xe8\x8f\xb1\xd9\x49\x99\xbe\xcf\xff\xb0\xa6\x3b\x8d\xf3\x1e\x1d\x65\xea\x82\xef\x81\xf8\x6a\xc8\x37\xa1\x28\x05\x75\x3a\x6c\x05\xf1\x73\xe4\x4e\x7d\x6b\xd4\xd0\x5b\x41\xf5\x1e\x1b\x3e\x1a\x04\x6d\x06\x19\x83\xe4\x34\x9d\x52\xd3\x58\xdf\xc3\x5e\x28\x4e\x3e\x87\x0b\x9b\x12\xce\x7b\x59\xef\xf9\xca\x84\x14\x66\x61\x5c\xd0\xde\xaa\x63\x89\xc6\x5b\x6f\x6c\x9e\xd9\xd0\x09\x61\x73\x4c\xbb\x95\x4e\xe8\x83\xc2\x36\xbf\x67\xb9\x79\x19\x43\xbf\x03\xe7\xc1\xcc\x27\xa6\x9e\x7a\x1a\x71\xac\x5c\x4c\x9c\x60\xb4\xfe\x84\x49\x63\x99\xd5\x7d\xa1\xa5\x67\xe6\x97\xf5\x3c\x1e\xdb\xde\xb5\xb9\x7f\x99\xc9\x7f\x23\xdf\xf4\x3c\x74\xe1\x62\x36\xe6\xf9\x67\x34\xaf\x54\xba\x5a\x4c\x04\xf0\x68\xbc\x39\x83\x8b\x5a\x8c\xb7\x2c\x92\x59\x5c\xf1\x1a\x1f\xdb\x1b\xec\x2c\xba\x2d\xaa\xd3\x92\x78\x95\xf9\x3d\xe4\xe8\x85\x9b\x11\x05\xee\x7d\x44\x55\x82\xa7\x5c\xb7\x28\x0c\xdb\xd4\x9c\x2f\xff\xed\xe0\xc4\x7f\x0b\xaf\x73\x14\xfc\x99\x63\x0a\xdc\x51\xf6\x94\xa3\xfb\x48\x9d\x65\xa5\xfd\xf2\x49\x26\x6e\x63\x55\xc5\x2a\x5d\x99\xfc\x0b\x91\xf1\xe7\x8d\x4a\xc8\x38\x79\x83\x5b\x4d\x2f\x90\xe9\xbe\xe6\x9b\xd2\x50\xa9\xfa\x5b\x10\x7f\x97\xbc\x31\xfa\x80\xca\x53\x8e\x77\xab\x44\xa4\xee\x8d\xa1\x5b\x59\xeb\xcb\x5e\xd3\x2b\x0a\xbc\x99\xae\xbd\xd4\xde\x90\xea\xd0\x4e\xa7\xcc\xd3\xc3\xf9\x35\xb8\xca\x27\xe9\xbc\xae\x54\x7c\xb8\xde\x96\x74\x39\x19\xb3\x85\x74\x47\xb4\x8c\x4a\xe1\x19\xf4\x9b\xf3\x80\x75\xea\x73\x45\xdf\x30\x20\xc6\xd5\x0e\x38\x7e\x6a\xb2\x9d\x32\xbc\xab\xe4\x5f\x6c\xc3\x4d\x9a\x1a\xa4\x8a\xc5\x79\x34\x1c\x53\x59\xa7\x8f\x2c\x06\xd5\x6d\xda\x2d\x39\xb5\xf1\xee\x19\xcd\x7c\x71\x1a\xd2\x0d\x34\x66\x90\xeb\xf9\x19\x7e\x4f\x2d\x89\xf1\xbb\x64\xac\x71\x2f\x62\xe2\xce\x1b\x45\xb7\xf7\x42\xb6\x38\x64\x4d\x9c\xe5\x74\x59\x96\xc3\x73\x28\xd5\x0e\x17\x9d\xcb\x2e\x74\x5a\x4a\xe8\x56\xa3\xd3\x38\xa9\x36\xd2\xf5\x1a\xeb\xcb\x37\x95\x0e\x78\x74\x5c\x24\x1c\xf0\x52\x4e\xdb\x12\x50\x0b\x63\x2e\xa0\x55\xea\x5e\x48\xe3\x77\x12\x7f\x17\xae\xd7\x68\x49\x06\x6d\x68\x4b\x18\x3c\xb6\xa2\x66\xf1\x83\x7e\x53\xb1\x3a\x34\xe9\x43\x2d\x9b\x5b\x79\x32\xa1\x51\x71\x9b\x51\x39\x1a\x3c\x49\xdd\xdb\x78\xf2\x19\xdd\x63\xe4\x03\xb0\x8e\x94\x16\xc9\x84\xf2\x80\x16\x2e\x42\x52\x3f\x20\x37\x94\xfc\x3d\x08\x6f\xa4\xf1\x1e\x79\xe9\x5f\xa6\x2d\x07\x27\x3a\xec\x70\x56\x89\xe6\x52\x0c\x08\x6c\xf9\x21\xfb\x83\xcf\xd1\x3e\xf9\xdc\x8c\xe6\x64\x17\x18\x59\xe5\x37\x4f\xd6\xe7\xb0\x79\xe2\x8a\x8a\x57\xc1\x6d\x39\x3e\x5e\x8f\x2c\xf4\xca\x87\x4d\x6f\x24\xe4\x89\xb4\x16\x1b\x9b\xb9\x30\x25\x7c\xe6\x6b\xd0\xbc\xed\x16\xf7\x29\x6f\x41\xe6\xf0\xb4\xbb\x11\x61\xc6\x23\x1e\xb3\x9a\xf9\x6b\x3d\x22\x5b\x0d\x51\xfa\x06\x0f\xf4\xf1\x91\xf7\xb9\xed\x5c\xbe\xcd\x73\xcc\x1d\xdc\xaf\x5b\x82\xb7\xdd\x39\xc0\x2f\x32\xdf\xaf\x36\xe1\x5f\x66\xc6\xb3\xa6\xcc\x8b\xfe\xf5\x14\x9c\x0a\x1e\x7a\xfd\x17\x69\x43\xed\xf0\xca\x80\xd8\x52\x67\x84\x72\x6d\x32\x2b\x1b\xd3\xe9\xef\x39\x77\x6e\xfc\xcb\x03\x31\x95\x9d\xa6\xef\x31\xf7\x26\xf7\x19\x73\x6b\x3f\x41\x53\xde\x7b\x96\x16\x27\xa6\x6e\x3c\xfb\x6f\xb4\x75\x0a\x11\x74\xf3\x35\xb4\x61\x38\xe1\x6c\x46\xdd\x18\x7a\x71\x43\xec\xb0\x9a\x5c\x81\x5d\xdd\xc5\x67\x01\xfe\x5b\xec\xce\x6b\xe6\x6f\xe1\xdc\xc8\xed\x0d\xaf\x4f\x44\x27\x01\x62\x86\x7e\xf1\x53\xd8\x78\x4a\x9f\x7c\xd1\x77\x23\xa3\x4c\xee\x34\x4f\xe8\x46\xad\x93\x44\xcf\x1b\xc6\x2f\xa6\x97\xbb\xc3\x64\xba\x63\x33\x8a\xdf\x82\xc8\x2e\x47\xbf\xcd\x71\xc6\x19\xeb\xa1\x07\x98\x53\x2a\x03\x9d\x18\x00\x78\xb4\x42\x9f\x00\xc3\x9a\xbb\x1a\x93\x76\xd2\xa8\xad\xb3\x8c\x22\x57\x56\x9c\xe2\xdb\x06\x4c\x67\x35\x1b\x15\x28\xc7\xd0\x06\xff\x3f\x2e\x31\x17\xfd\xc1\x2a\xfd\x77\x6b\xfe\x66\xd7\x18\xcf\x53\xb4\xd4\x0a\x3f\xb1\xcd\x51\x6b\xab\xa8\xfe\xc7\x5e\x35\xc2\xfd\x82\x3c\x60\xb2\xfc\xeb\xf3\x11\x50\x1b\xed\x66\x31\xe7\x2d\x9f\xbf\x5c\xb4\xe4\x5e\xc0\xb7\x29\x4d\x6e\x81\xe9\xdc\xf0\x37\x9e\x17\xf7\x1d\xc6\x2c\x67\x9c\x0f\x53\xa4\x4b\xd2\xf1\xcc\xab\x8e\xc5\x84\x30\xdd\x42\xaf\xa6\x5e\xe3\x9d\xc7\x21\xa7\xc1\x44\x2a\xf8\x3d\xbd\xf2\x38\xd2\xc2\x0a\xc4\x4e\x50\xdb\x45\x83\x45\x8c\xa9\x79\xdf\x3a\x5b\x15\x37\xe5\xcc\xec\xe5\x4c\x77\xe0\xf1\xab\x4f\x95\xc2\xf3\xf2\x1f\x4b\xf8\x6d\x09\x6f\x4a\x78\x82\x27\xfe\x8e\x50\xe5\xc1\xcb\xa7\x3f\xfe\xe1\x83\xfb\xf9\xbf\xdf\xbd\xfd\xe0\xde\xfd\xf0\xf3\xbf\x7d\x70\x3f\xbe\xfd\xe1\xdf\x61\xd9\xdb\xd5\x07\xf7\xe3\x0f\xff\x15\x3e\xa9\xa7\x51\xaf\xd6\x66\xbd\xda\xb8\x68\x4a\x68\xec\xf2\x81\x7d\xae\x46\x91\x2c\x8a\xc0\x4d\xfd\xad\x36\x9d\x70\xcb\xa7\xdf\xd8\x0f\x2e\xfd\xab\xde\x6c\xf9\xaf\x7a\x2a\xc1\x56\xb2\xa1\xbf\x54\xdc\xd0\xff\xf9\x3a\xa4\xea\x50\xd0\xd7\x77\xaa\x4e\x7c\x5a\x0d\x76\x36\xa8\x7d\xf4\xee\xca\xb9\xda\xb6\xbd\xdd\x2f\x57\x25\x3c\x7d\x50\x7f\xff\x54\xc2\xd3\xd3\x6a\xfe\x18\x77\xda\xf0\x31\xb6\xdf\x66\x92\xbf\xe2\x39\x8e\xa4\xcd\x91\xf2\x5b\xff\xdd\xb8\xea\x47\x92\x1b\x3e\xa7\xef\xdd\x31\xcb\xbd\x85\xd7\x7b\x0e\xce\x13\x59\xfa\xbf\x03\x97\xe1\x00\x8f\x7a\x2a\x1e\x2b\xef\xc7\x6b\x23\x77\x52\x89\xf6\x1d\x5f\x9d\x70\x9a\x42\x6c\xa7\x6a\x25\xe5\x64\xce\x31\x66\x34\xdc\xac\x4b\x5e\xa9\x91\x86\xbd\x92\x6f\x5b\xfd\x33\xbd\x5e\xf4\x3b\x1f\x6b\x73\x5e\x8b\xa1\x78\xcc\xa3\x2f\xf0\x92\xc7\xd0\xd4\xe9\x9b\xe5\xb3\xb8\xd1\x19\x99\xad\x1b\x06\xc2\xac\x1f\xb6\xea\x01\x97\x49\x8c\x5a\x60\x93\x44\x85\x66\xdc\xac\x66\x17\x77\x13\xff\x90\x70\xdf\xcf\xe3\xd3\x59\x58\x2d\x50\x35\x8b\xff\x1d\x00\x4b\x10\xd2\x11\x75\x3a\x00\x00")
 
 func luaLibRackRackLuaBytes() ([]byte, error) {
 	return bindataRead(
@@ -153,7 +153,7 @@ func luaLibRackRouteLua() (*asset, error) {
 	return a, nil
 }
 
-var _luaLibReplLua = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x57\x4d\x6f\xe3\x36\x13\xbe\xeb\x57\x0c\xbc\xef\x82\x24\xc0\xd5\x66\x5f\xa0\xc0\xc2\xad\xf6\xd6\x43\x2f\x6d\x91\xed\x2d\x09\x0c\x45\xa6\x1c\xd6\x32\xa9\x92\x94\x9c\x22\x48\x7f\x7b\xc1\x0f\xc9\xa4\x2c\x39\x0e\xea\x8b\x68\xf1\xe1\x7c\x3e\x9c\x19\x35\xb2\x2a\x1b\xf8\x53\x72\x01\xee\x57\x80\x62\x7f\x75\x5c\x31\x8c\x98\x6c\x78\x29\x72\x6d\x14\x17\x3b\x44\x72\x0b\xca\x3c\x5e\xb7\x0d\x37\x6f\xe1\x1d\x68\x38\x20\x95\x59\x54\x20\x95\x41\x24\x00\x4d\xf9\x78\x54\xdc\x30\x35\x03\x1c\xf7\x4e\x68\x7e\x60\x4b\x62\xed\x1e\x22\x59\x40\xd6\x9d\xa8\x0c\x97\x02\xb8\xfe\xbd\x34\xd5\x13\x53\xf8\x40\x32\x7b\x52\x31\xd3\x29\x01\xe6\xef\x96\xe1\x03\x81\xa2\x00\x64\xca\xc7\x86\x21\x28\xc5\x36\x83\xf0\xf3\xdb\x39\x17\x6d\x67\xb4\x47\x0d\x22\x67\x81\xb2\x33\xd7\x23\xaf\x40\x69\x76\x0d\x8a\x6f\xaf\x00\xd9\xc7\x04\x96\x31\xb1\x3d\x8b\x54\xcd\xc5\x16\xef\x94\xec\x5a\x0a\xa2\x3c\x30\x0a\xad\x62\x35\x7f\xf6\x61\xf3\x6b\x28\x86\x85\x54\x80\x50\xe6\xb6\x78\xed\xf0\x4e\xc5\xcf\x62\xc7\x05\x5b\xdf\x20\x30\x4f\x4c\x8c\xa6\x84\xa0\x23\xe6\xb6\x91\x7b\xef\x6c\xb0\x8b\x5a\x2a\xd8\x53\xe8\x81\x0b\x68\x4b\xae\xb4\xb7\x82\xc0\x56\x8e\x02\x78\x1d\x65\xb2\x27\xa9\xf4\x00\xe8\xd7\x7c\x8b\x9d\xa7\xce\x9c\x33\x48\x64\x88\xe5\x36\x7e\xf1\xae\x50\xd8\xbf\x52\x58\xe5\x2b\x92\xa0\x59\xa3\x19\xaf\x7d\x10\xfb\xfc\xc0\x0e\x8f\x4c\x9d\xe5\x77\x56\x87\xf5\x67\x43\x0f\xd6\x1d\xee\xfd\xe9\xd7\xe1\x3c\x26\x89\x57\x13\x07\x0e\x97\x6d\x7f\xbf\x0f\xa3\x2f\x11\x2b\x96\xde\xc5\xff\x13\xdf\x93\x3b\x72\x66\x98\x27\x91\x62\xba\x6b\x0c\x14\x9e\x44\xfd\x40\xa0\x79\x13\xc9\x34\x71\xe1\xf4\x3f\x05\x08\xde\x5c\xcc\x9b\x47\x2e\xdb\x1d\xd6\xf6\x39\x47\x71\x57\x4e\x7e\x71\x77\x1a\x1f\xbd\x91\x9a\x42\xb8\xe4\x23\x17\x37\x74\x1f\xe5\xce\x81\x92\xac\x79\xa1\x2e\x4f\x50\x84\xd3\x77\xfb\x87\xc9\x7e\x5b\x2a\xa3\xa1\xf0\xb5\x13\xfb\x78\xa0\xcf\x88\x9c\xc1\xcc\x13\x0c\x81\xc3\xb7\x65\xb5\xcf\x0f\x72\xdb\x35\x4c\xdb\x2b\xf6\xf2\x4a\xa8\x97\x74\xf7\xe5\x81\x64\xf1\x75\x70\x07\x97\x42\x36\x66\xc5\xe6\xe4\xe5\x35\xd9\xb3\x2e\xf2\xe2\xff\xf4\x83\xb7\x70\x86\x8e\x2e\xd7\x39\x17\x9a\x29\x83\xad\x90\xc1\x06\xfe\x40\x16\x63\x6f\x7f\xbe\x0c\x80\x6f\x09\x79\x2d\xd5\xa1\x34\x78\xf5\x51\x7f\xfe\xa8\x57\xd4\x59\x1c\x38\xe1\x85\xda\x70\x90\x24\x79\xe3\x9f\x63\xee\x72\x85\xcf\x44\xdd\x9b\x9f\x3e\xdd\x1b\xfb\xbc\x17\x2b\x6a\xeb\x86\x55\x1a\xc4\x5c\xcc\xfb\x6f\xbe\x44\x47\x89\x1f\x8a\xf6\x3b\x33\x2f\x3b\xa3\x6d\xca\xc2\xf1\xf3\xd4\xbb\x83\x3e\xf0\x59\x1c\xf5\x0d\x75\x01\x3a\x69\x90\xae\x65\x4c\x32\x70\x25\x7d\xfe\x2b\x85\xae\xa1\xd1\x5b\x54\xba\x8e\x4e\xef\xa2\xd4\x1c\xad\xce\x04\x84\xfc\xbd\x8b\x67\xcb\xcc\xbd\x96\x78\x9f\xbe\xa5\xc4\x73\x1a\x82\x29\x88\xc2\xa8\xe3\x22\x0b\xbf\x9b\xd2\x30\xcb\x41\x6d\x17\xfe\x40\x98\x99\xec\x8b\x5f\x4f\xd4\x81\xb1\x35\x46\x9d\xd1\x9f\x8a\xa3\x9c\x04\xe6\x24\x83\xc2\xfe\x64\x8d\x7d\x26\xe2\xed\x0c\x16\x26\x37\x1d\x1d\x22\xd9\xc2\x55\x88\x20\xb1\xee\x0b\xc1\x2a\x8a\x34\x58\x4e\xc2\xdd\xfe\xe1\x8d\x18\xb5\x8a\x0b\xf3\x87\x75\xe9\xbb\x51\x5d\x65\x3a\xc5\xb0\x89\xa3\x74\x84\xe2\x34\x34\xe6\x82\x1d\xf1\x57\x0a\x5f\x29\x7c\xa1\xb0\xba\x37\x2b\x0a\xab\xb2\xe1\x3b\x71\xcb\x77\x4f\x66\x45\x16\xe6\x0b\x33\x9d\x2d\xf6\xf6\x0a\xa0\xcd\xc6\x65\xb3\x2d\xab\xb9\x4e\xf7\xe6\x08\x62\x7f\x3d\x14\xd0\xaf\x5d\xe3\xbc\x5c\x2a\x2f\x84\xee\xa3\x1e\x82\xd6\x4f\xca\x63\xfc\x0c\xa4\xa1\x50\xc9\x4e\x98\x53\xd5\xdd\xe9\xee\x11\x1f\xf3\xba\xe9\xf4\x13\x26\x36\x2a\xe2\x7f\x36\x2c\x21\x18\xbc\x0e\x07\xbe\xc1\x8d\x73\xc1\x87\x1c\x6b\xb2\x98\x93\x4a\x36\x0d\xab\x8c\x4b\x7f\x9a\x0c\x71\x81\xa9\x66\x99\xa5\xe2\x8c\x9c\xa1\xbd\x8b\x59\xfd\x5c\xe8\x96\x55\x06\xcb\x74\x22\x4d\xf2\x21\x27\xf9\x58\x22\xcb\x0f\x14\x6e\x3c\x59\x20\x9a\x95\x78\x0d\x32\x77\x0c\x5d\x2c\x85\xe9\xe5\x95\x6b\x87\xc6\x33\xf9\x39\x69\xf7\x93\x81\xeb\x14\x6b\xbf\xc6\xd3\xee\x1f\x5a\x88\x83\x84\x35\x8e\x6a\xf4\x64\x5e\x49\x6e\x6d\x92\x95\x30\xc2\x90\x74\x98\x19\x45\x44\xad\x6f\x59\xc6\xd0\x0d\x49\xd4\x18\x27\xe6\xbe\x9b\x6d\x57\x30\x6e\x80\x79\x0a\x78\x56\x44\xe3\xa7\x5c\x1e\x3f\xe7\x6a\x85\x24\xa3\x84\x14\x37\xee\x2c\x90\xbc\xec\x8c\xbc\x75\x26\xe0\x86\x8b\xa4\x30\xd7\x14\x98\xb2\x1f\xa8\x8d\x2c\xb7\xde\x6f\x8c\xfc\x96\xbd\xee\x18\x41\x9e\x83\x3d\x04\x79\x8e\xc8\x8f\xee\x53\x24\xe6\xd1\xc0\xee\xde\x2a\x47\x23\x79\x85\x34\x50\xa7\x1e\xcd\x69\x3a\x59\x33\xb9\x2c\x1e\x7c\xe9\xc6\xde\xba\x69\x59\x63\xdd\x55\x15\xd3\x9a\x42\x9e\xe7\xd3\xeb\xab\x99\x45\x62\xf4\x01\x45\xdb\x41\xc3\x78\xee\xc5\x41\x85\x43\xc0\xeb\xac\x4a\xf6\xcc\xaa\xe5\xc8\xcd\x46\x77\x3e\x06\x4c\x29\xa9\x30\x53\x8a\xcc\x52\x63\xe8\xd5\x5e\xc1\x86\x86\x4f\x02\x7b\x85\x26\x5e\xb7\x55\xd9\x34\xb8\x26\xa3\xb2\x11\x39\x73\xc5\xe7\x54\xf8\x36\xd8\x47\x6d\x30\x48\x48\xbb\x47\xa8\x4e\xfd\x84\x5f\x21\x86\x2f\x5e\xfb\x50\xa8\xec\x07\xc3\xb0\xa6\xfe\xc0\x33\xab\xbc\xa4\xc2\xad\xfd\xdb\x20\x15\xfc\x17\x86\x5b\xfb\x0d\x3b\xe4\x0d\x70\xbb\xa6\xd9\x6b\xf6\x6f\x00\x00\x00\xff\xff\x8a\x03\x41\x72\xd5\x11\x00\x00")
+var _luaLibReplLua = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x58\x5f\x6f\xe3\xb8\x11\x7f\xf7\xa7\x18\xf8\xd6\x20\x79\x90\x75\x7b\x05\x0a\x1c\xd2\xea\xf6\xa9\x05\xfa\xd2\x16\x7b\x7d\xdb\x6c\x03\x45\xa6\x6c\xd6\x32\xe9\x23\x29\x79\x83\x20\xfb\xd9\x8b\xe1\x1f\x99\x92\x25\xdb\x69\x63\x03\x66\xc4\xf9\x3f\xbf\x19\x0e\xd5\xa8\xaa\x6c\x60\xc3\x4d\xa5\xc5\x33\x07\x28\x40\xf3\xdf\x5b\xa1\x39\x25\x5c\x35\xa2\x94\xf9\x8e\x37\x47\xc2\xf2\x48\xb2\xf0\x1c\xa6\x3d\x1c\x4a\xfd\x02\x57\x38\x02\x49\x60\xf8\x8f\x12\x12\x00\x26\x19\x8c\xd5\x42\x6e\x09\xcb\x91\x28\x2a\x38\x36\xc2\xde\xa2\x77\x44\x91\x41\x69\x3b\xab\x40\x69\x4b\x58\x20\xb4\xe5\xf3\x49\x0b\xcb\xf5\x04\x61\xbf\x77\xa6\x16\x07\x3e\x27\xd6\x8a\x03\x27\x6c\x11\x28\xeb\x56\x56\x56\x28\x09\xc2\xfc\xb3\xb4\xd5\x8e\x6b\x7a\x60\x0b\xe4\xd4\xdc\xb6\x5a\x82\x7d\x39\x72\x7a\x60\x50\x14\x40\x6c\xf9\xdc\x70\x02\xa5\xdc\x38\x0a\xfc\xfa\xed\x5c\xc8\x63\x6b\x8d\xa7\x8a\x22\x27\x09\x55\x6b\xef\xa7\xbc\x83\xca\xf0\x7b\xa8\xc4\xe6\x0e\x22\xfc\x19\x91\x2d\xb8\xdc\x5c\x44\xaa\x16\x72\x43\xb7\x5a\xb5\xc7\x0c\x64\x79\xe0\x19\x1c\x35\xaf\xc5\x37\x1f\x36\xbf\x86\x22\x2e\x94\x06\x42\x16\x6e\x4b\xd4\x8e\xde\xa9\xf8\x8b\xdc\x0a\xc9\x1f\x3e\x12\xb0\x3b\x2e\x7b\x53\x42\xd0\x09\x77\xdb\xc4\x3d\x77\x36\xe0\xa2\x56\x1a\xf6\x19\x74\x20\x24\x1c\x4b\xa1\x8d\xb7\x82\xc1\x46\xf5\x02\x44\x9d\x64\xb2\x63\x43\xe9\x81\xa0\x7b\x10\x1b\xca\xd0\x0c\x67\xce\x05\x49\x62\x08\x62\x9b\xbe\x7a\x57\x32\xd8\xbf\x65\xb0\xcc\x97\x6c\x40\xcd\x1b\xc3\x45\xed\x23\xdd\xe5\x07\x7e\x78\xe6\xfa\x22\xbf\x93\x3a\xd0\x9f\xa7\xec\x80\xee\x08\xef\x4f\xf7\x10\xf8\x29\x1b\x78\x95\x7e\x44\x0d\x87\xeb\xb6\xbf\xdf\x87\xf8\xc7\x13\x54\xcc\x3d\x4b\xff\x1f\xf8\x3e\xa8\x91\x0b\xc3\x3c\x88\x34\x37\x6d\x63\xa1\xf0\x20\xea\x22\x80\xa6\x4d\x1c\xda\x28\xea\xc8\xfd\xbd\x00\x29\x9a\xab\x79\xf3\x94\xf3\x76\x87\x35\xfa\x32\x05\x71\xd7\x6a\xfe\xe6\x6a\x9a\x9e\xbc\x91\x26\x83\x50\xe4\x8b\x73\xee\xf6\x49\xee\x1c\xd1\x20\x6b\xde\x65\x7c\x8e\x7d\xc8\x73\x7f\xd9\x7f\x1d\xed\x1f\x4b\x6d\x0d\x14\xbe\x77\x52\xa4\xce\x80\xfc\x44\xd8\x05\x99\xdd\x41\x0c\x1c\xfd\x5c\x56\xfb\xfc\xa0\x36\x6d\xc3\x0d\x28\x0d\xaf\x6f\x2c\xf3\x92\xbe\xfc\xfc\x95\x2d\x7a\x5e\x51\x7b\xc6\xb9\x90\xf5\x59\xc1\x9c\xbc\xbe\x0d\xf6\x10\x9e\xa2\xf8\x43\xf6\x83\x93\x3b\x05\x47\x97\xeb\x5c\x48\xc3\xb5\xa5\x28\x24\xda\x20\xbe\xb2\xd9\xd8\xe3\x07\xbd\x44\x97\xdd\x91\x90\xd7\x4a\x1f\x4a\x4b\x97\x2b\xf3\xd3\xca\x2c\x51\x86\xdd\x05\x4c\x78\xa1\x18\x8e\xb3\xc0\xbe\x1f\xe0\xf7\x94\xbb\x5c\xd1\x0b\x51\x8f\xf6\xcf\xeb\x47\xbb\x32\x8f\xf6\x51\x2e\x33\xec\x1b\xa8\x94\xb1\xdb\x79\xff\x47\x6b\x47\x89\x8f\x4d\xfb\x9d\x99\x57\xad\x35\x98\xb2\xc0\x7e\x99\x7a\x27\xde\x07\xbe\xdf\xc1\xa8\x3f\x65\xb8\x93\x68\x40\x49\x03\x05\xef\x80\xcf\xff\x0b\xa1\x7b\x60\x74\x0b\x4a\xf7\xc1\xe9\x5d\x90\x8a\x69\x8c\xeb\x49\x01\x21\x7f\xef\xc2\x19\x5b\xcc\xa9\xb8\x17\x78\xeb\x5f\x87\xc0\x73\x48\x0e\xa6\x90\x0c\x08\xbb\x07\x85\xbf\xd9\xd2\x72\xc4\xa0\xc1\x85\x37\x2a\xcc\x4c\xf8\xe0\xef\x67\xe8\xc4\xe0\xee\xb3\xe4\x64\xf4\x5c\x69\x94\x07\x81\x39\xcb\xc8\x60\x7f\xb6\x06\x7f\xcf\x5b\x58\xa1\x4a\xdb\x30\xb9\x05\x99\x4e\x31\x5b\xcc\x94\x42\x42\x92\xea\xbe\x12\xac\xa2\x18\x06\xcb\x49\xf8\xb2\xff\x7a\x23\x46\x47\x2d\xa4\xfd\x17\xba\xf4\x9b\xd5\x6d\x65\x5b\xcd\xa9\x4d\xa3\x74\x82\x02\xfa\xc1\x30\x97\xfc\x44\x7f\xc9\xe0\x97\x0c\x7e\xce\x60\xf9\x68\x97\x19\x2c\xcb\x46\x6c\xe5\x67\xb1\xdd\xd9\x25\x9b\x99\x2f\xec\x20\x82\xa2\x86\x3d\x7c\x2f\x80\x3c\x3d\xb9\x6c\x1e\xcb\x6a\xea\xa4\xbb\x39\x82\xe0\xb7\x83\x02\xba\x07\x37\x34\xcc\x03\xee\x46\xe8\x56\x26\x06\xad\x0b\xd1\x4a\x05\xc4\xdf\x00\x9a\x0c\x2a\xd5\x4a\x7b\xee\xba\x5b\xd3\x3e\xd3\x53\x5e\x37\xad\xd9\x51\x86\x51\x91\x1f\x30\x2c\x21\x18\xa2\x0e\x0c\xbf\xc2\x47\xe7\xa3\x0f\x39\x35\x6c\x36\x27\x95\x6a\x1a\x5e\x59\x87\x90\x61\x32\xe4\x15\xa4\xda\x79\x94\xca\x0b\x70\x86\xe3\x5d\x4e\xea\x17\xd2\x1c\x79\x65\xa9\x1a\x4e\xa4\x83\x7c\xa8\x51\x3e\xe6\xc0\xf2\xc7\x0c\x3e\x7a\xb0\x40\x08\x48\x10\xa5\x72\x87\xd0\xd9\x56\x38\x2c\x5e\xf5\xe0\xa8\xe9\x44\x7e\xce\xda\xfd\x64\x80\x9d\x59\x3d\xf8\x35\x65\x97\x47\x09\x3e\x77\x24\x61\x4d\x93\x1e\x3d\x9a\x57\x06\x55\x3b\xc8\x4a\x18\x61\xd8\x70\x98\xe9\x45\x24\x47\xdf\xbc\x8c\xa0\x9f\xb1\xe4\x60\xec\xc5\xfc\x8f\x68\xbb\x03\x71\x91\xcc\x43\x60\x31\x1e\x3f\xd5\xfc\xf8\x39\xd5\x2b\x54\xc0\x55\x63\xf8\x90\x8e\xaa\xeb\x8d\xa7\x6c\xad\xfa\xec\x4c\xa0\x8d\x90\x83\xc6\x5c\x67\xc0\x35\x5e\x50\x1b\x55\x6e\x7c\x95\x51\xe2\x03\x82\xe5\x4e\x09\xe4\x39\x20\x13\xe4\x39\x61\x7f\x72\x57\x91\x14\x47\x71\x78\xed\xd0\xdf\x70\x82\xe3\x9d\x49\x59\xa8\x87\x1e\x4d\x69\x3a\x5b\x33\x2a\x16\x4f\x3c\xe9\x4c\xc8\xeb\x67\x37\x2d\x1b\x6a\xda\xaa\xe2\xc6\x64\x90\xe7\xf9\xb8\x7c\x0d\x47\x04\x50\xf2\x03\x49\xb6\x83\x86\x9e\xef\xd5\x91\x4a\x47\x01\x6f\x33\x2a\xa5\x71\x1d\x5b\x69\x84\x13\xad\xe7\x7a\xef\xf8\xde\x6e\x5e\xa4\xdd\x91\xe1\xbd\xc8\x45\xb0\x28\xc6\xe1\x49\x4c\xdb\xf7\x0f\x63\x54\xfa\xc4\xae\xd7\xc0\xbf\x1d\x9b\x52\x04\xa0\x19\x38\xed\x4a\x0b\x25\x74\x65\xd3\x72\x10\xe6\x01\x4a\xf0\x33\x36\x31\xee\x8e\x93\xc1\x51\xe1\xb4\x55\xca\x0d\x54\xad\xd6\x5c\x5a\x30\xaa\xd5\x15\x1e\xa3\x65\xea\x1a\x31\x80\x2f\x54\x70\xae\xb2\x3b\xee\x98\x41\xd5\x8b\xf5\x1a\x4a\xf9\x62\x77\x42\x6e\x1d\x78\xc7\xc1\x09\xf6\xd0\x8e\x2d\x6e\x9e\x24\xc9\x4c\x77\x75\xa4\xf3\xb7\x5d\x86\xff\xfb\x65\x2f\x21\x94\xd7\xe0\x60\x21\x2b\x03\x74\x65\x18\xc9\xfa\xe3\xc9\x0f\xe4\x49\x07\x4b\x5f\x3f\x1d\x5d\x52\x8b\xf8\x6a\x89\x46\xa6\x33\xb5\xa8\x07\xa4\x73\x7d\xd3\x1b\x93\x50\xb2\x8b\xcc\xe1\x27\xf6\xf8\x8e\x5d\x74\x80\x6e\x7c\xe9\x76\x69\x3a\xe7\xc4\xc1\xad\x63\x93\x9d\xdb\x6b\x8f\xb1\x70\xcd\x2a\xbe\x3b\xa3\x63\xc4\x76\x6c\xd0\xbf\xd8\xac\x25\xb3\xbd\x88\x86\x2d\x76\xb5\x45\x25\x2e\x8e\xd8\x2f\x52\x86\x23\x14\xc9\xa2\xf2\x0c\xac\x0a\x4d\xa1\xbb\x98\x35\xd7\x6b\xf8\xbd\xe5\xfa\x25\x14\x88\x41\xf3\xb0\x0e\x34\x37\x06\x53\xa9\x6a\x28\x7d\x93\x2a\xcd\x1e\x71\x5a\x3e\xab\xd6\x82\xb0\x70\x12\x76\x87\x7b\xbc\xdc\xe0\x73\x84\xb6\x2e\x45\x83\xeb\x4f\x63\x20\x3b\x15\x49\x4b\x8a\x8d\xc2\x59\x95\x1f\x10\xd2\x6e\x37\x03\xf2\xef\xd5\xa7\x95\xf9\x91\xe6\x6b\xb6\x32\x3f\x7e\x20\x0e\xa8\xd3\x74\x3d\xd5\xea\xd3\x07\xc2\x26\xbb\x0b\xff\xc6\xab\x44\xad\xdf\x45\xef\xa0\xb8\xb0\x49\xd4\xce\xef\x49\x3c\x5c\x69\xe8\xc1\x15\xd7\xcc\x91\xff\x9c\xc4\xe9\x5e\x8d\x1f\xae\xb5\xd2\x94\x6b\x3d\x0d\xea\x58\xf6\x75\x5a\x37\xe9\x39\x17\x2f\x1e\x23\xb3\x26\xcf\xa2\x69\x2b\x26\x2c\x98\x57\xf0\x94\x85\x57\x2d\x78\xb1\x1c\x9d\x11\xc7\xaa\x6c\x1a\x5a\x07\x43\xfb\x97\x32\x06\x8a\x89\x30\x4e\xa9\xc0\x4e\xff\x94\x75\xc9\xa5\x21\x48\x18\x76\xf6\x51\x9d\x47\xf8\x8e\xf2\x8d\x4d\x96\x76\x83\xb3\x0a\x6f\xce\x05\x74\xd1\xbc\xb4\x20\x23\xdb\xa8\x26\xc3\xdb\x88\xcb\x3a\x4f\x0b\x7b\xbe\x11\x07\xf6\xd8\xf9\x7a\x7b\x53\x03\x90\xc6\x75\x1e\x12\x5e\x83\x4f\x76\x85\xd6\x94\x5b\xfe\xe0\x4e\x0e\xea\x8e\xbb\xfc\xaf\xa2\xb1\x5c\x33\xc2\xe6\x42\x7a\xab\x79\x39\xc5\xa3\x76\x85\x7c\x8b\x00\xe3\x57\x27\x18\x35\xc6\xf7\xe5\xb8\xce\xdc\xd3\xde\x65\x28\xce\x6b\xbf\x85\x75\x16\x19\x70\x1d\x9f\x3a\x1c\x87\xa7\x6e\x1d\x24\xf9\x6c\xba\x8d\x90\x59\xbf\x81\xe7\x56\x94\x53\x0b\xb9\xc9\x16\x6f\x8b\xff\x0e\x00\xf7\xb2\xd4\x6b\xde\x18\x00\x00")
 
 func luaLibReplLuaBytes() ([]byte, error) {
 	return bindataRead(
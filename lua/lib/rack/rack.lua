@@ -1,11 +1,43 @@
-local filepath = require('eolian.filepath')
+local batch     = require('eolian.rack.batch')
+local filepath  = require('eolian.filepath')
+local graph     = require('eolian.rack.graph')
+local profile   = require('eolian.rack.profile')
+local sandbox   = require('eolian.sandbox')
+local snapshot  = require('eolian.rack.snapshot')
+local synth     = require('eolian.synth')
+local tabwriter = require('eolian.tabwriter')
+local tracker   = require('eolian.synth.tracker')
+local watch     = require('eolian.rack.watch')
+
+-- Files loaded by the rack are tracked so they can be watched for changes
+local required = {}
+
+local trackedDofile = function(path)
+    watch.track(path)
+    return dofile(path)
+end
+
+local trackedRequire = function(name)
+    local path = watch.resolve(name)
+    if path ~= nil then
+        watch.track(path)
+        required[name] = true
+    end
+    return require(name)
+end
+
+-- Rack files print through whatever the global print is when they run, so editors evaluating code over -listen get
+-- their output
+local function globalPrint(...)
+    return print(...)
+end
 
 local environment = {
     assert       = assert,
     channel      = channel,
     coroutine    = coroutine,
     debug        = debug,
-    dofile       = dofile,
+    dofile       = trackedDofile,
     error        = error,
     getmetatable = getmetatable,
     io           = io,
@@ -18,11 +50,11 @@ local environment = {
     os           = os,
     pairs        = pairs,
     pcall        = pcall,
-    print        = print,
+    print        = globalPrint,
     rawequal     = rawequal,
     rawget       = rawget,
     rawset       = rawset,
-    require      = require,
+    require      = trackedRequire,
     select       = select,
     setmetatable = setmetatable,
     string       = string,
@@ -34,15 +66,171 @@ local environment = {
     xpcall       = xpcall,
 }
 
-local close = function(v)
+-- Libraries a sandboxed rack may require. Anything else it requires must be a Lua file within the rack's directory.
+local sandboxLibraries = {
+    ['eolian.filepath']      = true,
+    ['eolian.func']          = true,
+    ['eolian.pattern']       = true,
+    ['eolian.rack.mount']    = true,
+    ['eolian.rack.route']    = true,
+    ['eolian.sort']          = true,
+    ['eolian.string']        = true,
+    ['eolian.synth']         = true,
+    ['eolian.synth.control'] = true,
+    ['eolian.synth.proxy']   = true,
+    ['eolian.theory']        = true,
+    ['eolian.value']         = true,
+}
+
+-- copy makes a shallow copy of a library, so a sandboxed rack can't change it for everyone else
+local function copy(lib)
+    local result = {}
+    for k, v in pairs(lib) do result[k] = v end
+    return result
+end
+
+-- sandboxEnvironment returns the environment of rack files loaded with -sandbox. It leaves out anything that runs
+-- commands or reaches files outside of the rack's directory, and the code it loads is limited in steps and memory.
+local function sandboxEnvironment()
+    local env = {
+        assert       = assert,
+        coroutine    = copy(coroutine),
+        error        = error,
+        ipairs       = ipairs,
+        math         = copy(math),
+        next         = next,
+        os           = { clock = os.clock, date = os.date, difftime = os.difftime, time = os.time },
+        pairs        = pairs,
+        pcall        = pcall,
+        print        = globalPrint,
+        rawequal     = rawequal,
+        rawget       = rawget,
+        rawset       = rawset,
+        select       = select,
+        string       = copy(string),
+        table        = copy(table),
+        tonumber     = tonumber,
+        tostring     = tostring,
+        type         = type,
+        unpack       = unpack,
+        xpcall       = xpcall,
+    }
+
+    env.string.rep = sandbox.rep
+
+    -- Metatables are shared with the host (strings, values, ...), so the rack only gets back the ones it set itself
+    local owned = setmetatable({}, { __mode = 'k' })
+    env.setmetatable = function(t, mt)
+        if mt ~= nil then
+            owned[mt] = true
+        end
+        return setmetatable(t, mt)
+    end
+    env.getmetatable = function(v)
+        local mt = getmetatable(v)
+        if type(v) == 'table' and owned[mt] then
+            return mt
+        end
+        return nil
+    end
+
+    env.io = {
+        open = function(path, mode)
+            sandbox.check(path)
+            return io.open(path, mode or 'r')
+        end,
+        lines = function(path)
+            assert(path ~= nil, 'io.lines needs a path')
+            sandbox.check(path)
+            return io.lines(path)
+        end,
+    }
+
+    env.dofile = function(path)
+        watch.track(path)
+        return sandbox.loadfile(path, env)()
+    end
+    env.loadstring = function(src, name)
+        return sandbox.loadstring(src, env, name)
+    end
+    env.load = function(fn, name)
+        local parts = {}
+        for part in fn do
+            if part == '' then break end
+            table.insert(parts, part)
+        end
+        return env.loadstring(table.concat(parts), name)
+    end
+
+    local loaded = {}
+    env.require = function(name)
+        if sandboxLibraries[name] then
+            return require(name)
+        end
+        if loaded[name] == nil then
+            local path = Rack.env.path .. '/' .. (string.gsub(name, '%.', '/')) .. '.lua'
+            loaded[name] = env.dofile(path)
+            if loaded[name] == nil then
+                loaded[name] = true
+            end
+        end
+        return loaded[name]
+    end
+
+    return env
+end
+
+-- limited calls a function from a rack file, within the sandbox's limits when there's one
+local function limited(fn, ...)
+    if sandbox.enabled() then
+        return sandbox.run(fn, ...)
+    end
+    return fn(...)
+end
+
+-- members collects the modules in a table by their patcher
+local function members(v, result)
+    if type(v) ~= 'table' then
+        return result
+    end
+    if v.__patcher ~= nil then
+        result[v.__patcher] = true
+        return result
+    end
+    for _, s in pairs(v) do members(s, result) end
+    return result
+end
+
+-- closeUnused closes the modules in a table that aren't live
+local function closeUnused(v, live)
     if type(v) ~= 'table' then
         return
     end
-    if type(v.close) == 'function' then
-        v:close()
+    if v.__patcher ~= nil then
+        if not live[v.__patcher] and type(v.close) == 'function' then
+            v:close()
+        end
         return
     end
-    for _, s in pairs(v) do close(s) end
+    for _, s in pairs(v) do closeUnused(s, live) end
+end
+
+-- reuse swaps modules in a newly built table for the modules found under the same key of the old table when both were
+-- created with the same type and config. The new instances that were swapped out are added to discarded.
+local function reuse(old, new, discarded)
+    for k, n in pairs(new) do
+        local o = old[k]
+        if type(n) == 'table' and type(o) == 'table' then
+            if n.__patcher ~= nil and o.__patcher ~= nil then
+                if n.__signature ~= nil and n.__signature == o.__signature then
+                    new[k] = o
+                    table.insert(discarded, n)
+                end
+            elseif n.__patcher == nil and o.__patcher == nil then
+                reuse(o, n, discarded)
+            end
+        end
+    end
 end
 
 local startPatch = function(v)
@@ -67,6 +255,33 @@ local finishPatch = function(v)
     for _, s in pairs(v) do finishPatch(s) end
 end
 
+-- patchers collects the modules in a table by their path, such as filter or voices[1].osc
+local function patchers(v, prefix, result)
+    for k, s in pairs(v) do
+        local path
+        if type(k) == 'number' then
+            path = (prefix or '') .. '[' .. k .. ']'
+        elseif type(k) == 'string' and string.sub(k, 1, 2) ~= '__' then
+            path = k
+            if prefix ~= nil then
+                path = prefix .. '.' .. k
+            end
+        end
+        if path ~= nil and type(s) == 'table' then
+            if type(s.__patcher) == 'userdata' then
+                result[path] = s.__patcher
+            else
+                patchers(s, path, result)
+            end
+        end
+    end
+    return result
+end
+
+local printWarnings = function(warnings)
+    for _, w in ipairs(warnings) do print(w) end
+end
+
 local mount = function(sinks)
     if #sinks == 1 then
         Engine:set { left = sinks[1], right = sinks[1] }
@@ -84,16 +299,48 @@ Rack = {
 
         -- Will be removed soon...
         require = function(self, path)
-            return dofile(self.path .. '/' .. path)
+            return trackedDofile(self.path .. '/' .. path)
         end,
 
         dofile = function(self, path)
-            return dofile(self.path .. '/' .. path)
+            return trackedDofile(self.path .. '/' .. path)
         end
     },
     modules = nil
 }
 
+-- Signature of the build function and what it reaches through its upvalues, used to decide whether a changed rack needs
+-- to be rebuilt or only repatched
+local buildSignature = nil
+
+-- The Rack.env handed to rack files, which loads files in the sandbox when there's one
+local rackEnv = nil
+
+local loadRack = function()
+    if sandbox.enabled() then
+        local env = sandboxEnvironment()
+        rackEnv = {
+            filepath = Rack.env.filepath,
+            path     = Rack.env.path,
+
+            require = function(self, path)
+                return env.dofile(self.path .. '/' .. path)
+            end,
+
+            dofile = function(self, path)
+                return env.dofile(self.path .. '/' .. path)
+            end
+        }
+        local file = env.dofile(Rack.env.filepath)
+        return file(rackEnv)
+    end
+
+    rackEnv = Rack.env
+    local file = trackedDofile(Rack.env.filepath)
+    setfenv(file, environment)
+    return file(Rack.env)
+end
+
 function Rack.clear()
     Engine:set { left = 0, right = 0 }
 end
@@ -101,31 +348,42 @@ end
 function Rack.build()
     assert(Rack.modules ~= nil, 'no rackfile loaded.')
 
-    Rack.clear()
-    close(Rack.modules)
-
+    -- Build the new modules before touching the old ones so a broken rack leaves the current sound running
     local build, patch, modules
+    tracker.start()
     local status, err, result = xpcall(function()
-        local file = dofile(Rack.env.filepath)
-        setfenv(file, environment)
-        build, patch = file(Rack.env)
-        modules = build()
+        build, patch = limited(loadRack)
+        modules = limited(build)
     end, debug.traceback)
+    local created = tracker.stop()
     if not(result) and err ~= nil then
         print(err)
+        -- Close whatever the broken build created before it failed
+        for _, m in ipairs(created) do pcall(m.close, m) end
         return
     end
 
+    -- Modules that kept their key, type and config carry on with their state; only the rest are replaced
+    local discarded = {}
+    reuse(Rack.modules, modules, discarded)
+
+    local previous = Rack.modules
     Rack.modules = modules
-    local result, err = pcall(function()
+    buildSignature = watch.signature(build)
+    local result, err = pcall(batch, function()
         startPatch(Rack.modules)
-        local sinks = {patch(Rack.modules)}
+        local sinks = {limited(patch, Rack.modules)}
         finishPatch(Rack.modules)
         mount(sinks)
     end)
     if not result then
         print(err)
+        Rack.clear()
     end
+
+    local live = members(Rack.modules, {})
+    closeUnused(previous, live)
+    closeUnused(discarded, live)
 end
 
 function Rack.patch()
@@ -133,9 +391,7 @@ function Rack.patch()
 
     local patch
     local status, err, result = xpcall(function()
-        local file = dofile(Rack.env.filepath)
-        setfenv(file, environment)
-        _, patch = file(Rack.env)
+        _, patch = limited(loadRack)
     end, debug.traceback)
     if not(result) and err ~= nil then
         print(err)
@@ -143,14 +399,103 @@ function Rack.patch()
     end
 
     local status, err, result = xpcall(function()
-        startPatch(Rack.modules)
-        local sinks = {patch(Rack.modules)}
-        finishPatch(Rack.modules)
-        mount(sinks)
+        batch(function()
+            startPatch(Rack.modules)
+            local sinks = {limited(patch, Rack.modules)}
+            finishPatch(Rack.modules)
+            mount(sinks)
+        end)
+    end, debug.traceback)
+    if not(result) and err ~= nil then
+        print(err)
+    end
+end
+
+-- Reloads the rack after its files changed: rebuilds it when the build function changed and repatches it otherwise
+function Rack.reload()
+    assert(Rack.modules ~= nil, 'no rackfile loaded.')
+
+    for name in pairs(required) do
+        package.loaded[name] = nil
+    end
+
+    local build
+    local status, err, result = xpcall(function()
+        build = limited(loadRack)
     end, debug.traceback)
     if not(result) and err ~= nil then
         print(err)
+        return
+    end
+
+    if watch.signature(build) ~= buildSignature then
+        print('Rebuilding ' .. Rack.env.filepath)
+        Rack.build()
+    else
+        print('Repatching ' .. Rack.env.filepath)
+        Rack.patch()
+    end
+end
+
+function Rack.snapshot(path)
+    assert(Rack.modules ~= nil, 'no rackfile loaded.')
+
+    local modules = patchers(Rack.modules, nil, {})
+    modules.engine = Engine.__patcher
+    printWarnings(snapshot.save(path, modules))
+end
+
+function Rack.restore(path)
+    assert(Rack.modules ~= nil, 'no rackfile loaded.')
+
+    local modules = patchers(Rack.modules, nil, {})
+    modules.engine = Engine.__patcher
+    printWarnings(snapshot.restore(path, modules))
+end
+
+function Rack.graph(path)
+    assert(Rack.modules ~= nil, 'no rackfile loaded.')
+
+    local modules = patchers(Rack.modules, nil, {})
+    modules.engine = Engine.__patcher
+
+    if filepath.ext(path) == '.json' then
+        graph.json(path, modules)
+    else
+        graph.dot(path, modules)
+    end
+end
+
+function Rack.profile(enabled)
+    assert(Rack.modules ~= nil, 'no rackfile loaded.')
+
+    if enabled ~= nil then
+        profile.enable(enabled)
+        return
+    end
+    if not profile.enabled() then
+        profile.enable(true)
+        print('Profiling enabled. Call Rack.profile() again to see the results.')
+        return
+    end
+
+    local w = tabwriter.new(5, 0, 2, " ")
+    w.write("ID\tTYPE\tPATH\tMEAN (us)\tMAX (us)\n")
+    for _, s in ipairs(profile.stats(patchers(Rack.modules, nil, {}))) do
+        w.write(string.format("%s\t%s\t%s\t%.2f\t%.2f\n", s.id, s.type, s.path, s.mean, s.max))
     end
+    print((string.gsub(w.flush(), "\n$", "")))
+end
+
+function Rack.morph(paths)
+    assert(Rack.modules ~= nil, 'no rackfile loaded.')
+
+    local modules = patchers(Rack.modules, nil, {})
+    modules.engine = Engine.__patcher
+
+    local morph = synth.Morph { snapshots = paths }
+    printWarnings(snapshot.morph(morph.__patcher, modules))
+    return morph
 end
 
 local originalPath = package.path
@@ -161,12 +506,12 @@ function Rack.load(path)
     Rack.env.filepath  = path
     Rack.env.path      = filepath.dir(path)
 
-    local file = dofile(path)
-    setfenv(file, environment)
-    local build, patch = file(Rack.env)
+    local build, patch = limited(loadRack)
 
-    Rack.modules       = build(Rack.env)
-    local sinks        = {patch(Rack.modules)}
+    Rack.modules       = limited(build, rackEnv)
+    buildSignature     = watch.signature(build)
 
-    mount(sinks)
+    batch(function()
+        mount({limited(patch, Rack.modules)})
+    end)
 end
@@ -1,3 +1,5 @@
+local describe  = require('eolian.help').describe
+local summary   = require('eolian.help').summary
 local join      = require('eolian.string').join
 local split     = require('eolian.string').split
 local sort      = require('eolian.sort')
@@ -150,7 +152,51 @@ local function collectResults(success, ...)
     return success, { n = n, ... }
 end
 
+local function constructorName(f)
+    for k, v in pairs(require('eolian.synth')) do
+        if v == f then
+            return k
+        end
+    end
+end
+
+-- explain prints what a value is: a module's type, ports and current sources, a constructor's help or the type of
+-- anything else
+local function explain(v)
+    if isPatcher(v) then
+        local path = find((Rack.modules or {}), v:id()) or v:id()
+        print(string.format('%s (%s)', v:type(), path))
+        local description = summary(v:type())
+        if description ~= nil then
+            print(description)
+        end
+        inspect(v)
+    elseif type(v) == 'function' and constructorName(v) ~= nil then
+        print((string.gsub(describe(constructorName(v)), "\n$", "")))
+    elseif type(v) == 'table' then
+        print('table')
+        printTableStructure(v)
+    else
+        print(string.format('%s\t%s', type(v), tostring(v)))
+    end
+end
+
+-- query returns the expression of a line asking about it with a leading or trailing ?
+local function query(line)
+    return string.match(line, '^%?%s*(.-)%s*$') or string.match(line, '^%s*(.-)%s*%?$')
+end
+
 local function exec(line)
+    local expr = query(line)
+    if expr ~= nil then
+        local f, err = loadstring('return ' .. expr)
+        if not f then
+            error(err)
+        end
+        explain(f())
+        return
+    end
+
     local f, err = autoReturn(line)
     if not f then
         error(err)
@@ -167,9 +213,25 @@ local function exec(line)
     end
 end
 
+local function help(v)
+    local name = v
+    if type(v) == 'function' then
+        name = constructorName(v)
+    elseif isPatcher(v) then
+        name = v:type()
+    end
+    if type(name) ~= 'string' then
+        print('usage: help(synth.Filter)')
+        return
+    end
+    print((string.gsub(describe(name), "\n$", "")))
+end
+
 return {
+    help      = help,
     isPatcher = isPatcher,
     exec      = exec,
+    explain   = explain,
     inspect   = inspect,
     find      = find,
 }
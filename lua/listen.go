@@ -0,0 +1,164 @@
+package lua
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const maxRequestSize = 16 << 20
+
+// Editors evaluate code in a running VM over a socket. Each request is a line holding a JSON object
+// {"id": ..., "token": "...", "code": "..."} and each response is a line holding a JSON object
+// {"id": ..., "output": "...", "error": "..."} where the id is copied from the request and output is everything the code
+// printed. The token is only needed when the VM is served with one, which it always is over TCP: browsers will send
+// requests to localhost on behalf of any page, so knowing the port mustn't be enough to run code.
+
+var (
+	errBadToken   = errors.New("missing or wrong token")
+	errNotRequest = errors.New(`requests are JSON objects like {"code": "..."}`)
+
+	// Matches the first line of an HTTP request, e.g. a cross-origin fetch from a browser
+	httpRequestLine = regexp.MustCompile(`^[A-Z]+ \S+ HTTP/\d`)
+)
+
+type evalRequest struct {
+	ID    json.RawMessage `json:"id,omitempty"`
+	Token string          `json:"token,omitempty"`
+	Code  string          `json:"code"`
+}
+
+type evalResponse struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Output string          `json:"output"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Listen opens a socket for Serve. Addresses starting with "unix:" are Unix socket paths; anything else is a TCP address
+// whose host defaults to localhost, so the VM isn't exposed to the network unless asked for.
+func Listen(addr string) (net.Listener, error) {
+	if strings.HasPrefix(addr, "unix:") {
+		path := strings.TrimPrefix(addr, "unix:")
+		// Remove a socket left behind by a previous run
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(path); err != nil {
+				return nil, err
+			}
+		}
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		// Only the user running eolian may connect
+		if err := os.Chmod(path, 0600); err != nil {
+			l.Close()
+			return nil, err
+		}
+		return l, nil
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return net.Listen("tcp", net.JoinHostPort(host, port))
+}
+
+// NewToken returns a random token for Serve
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Serve evaluates requests from connections accepted on the listener until it's closed. Unless token is empty, requests
+// that don't carry it are refused.
+func (vm *VM) Serve(l net.Listener, token string) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go vm.serveConn(conn, token)
+	}
+}
+
+func (vm *VM) serveConn(conn net.Conn, token string) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxRequestSize)
+	enc := json.NewEncoder(conn)
+	for first := true; scanner.Scan(); first = false {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if first && httpRequestLine.Match(line) {
+			return
+		}
+		if len(line) == 0 {
+			continue
+		}
+
+		var req evalRequest
+		err := errNotRequest
+		if line[0] == '{' {
+			err = json.Unmarshal(line, &req)
+		}
+		if err == nil && subtle.ConstantTimeCompare([]byte(req.Token), []byte(token)) != 1 {
+			err = errBadToken
+		}
+		if err != nil {
+			if err := enc.Encode(evalResponse{ID: req.ID, Error: err.Error()}); err != nil {
+				return
+			}
+			continue
+		}
+
+		resp := evalResponse{ID: req.ID}
+		output, err := vm.Eval(req.Code)
+		resp.Output = output
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Eval runs code the way the REPL does and returns what it printed
+func (vm *VM) Eval(code string) (string, error) {
+	vm.mtx.Lock()
+	defer vm.mtx.Unlock()
+
+	var buf bytes.Buffer
+	print := vm.GetGlobal("print")
+	vm.SetGlobal("print", vm.NewFunction(func(state *lua.LState) int {
+		for i := 1; i <= state.GetTop(); i++ {
+			if i > 1 {
+				buf.WriteString("\t")
+			}
+			buf.WriteString(state.ToStringMeta(state.Get(i)).String())
+		}
+		buf.WriteString("\n")
+		return 0
+	}))
+	defer vm.SetGlobal("print", print)
+
+	err := vm.LState.DoString(fmt.Sprintf("repl.exec(%q)", code))
+	return buf.String(), err
+}
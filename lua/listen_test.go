@@ -0,0 +1,104 @@
+package lua
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestEval(t *testing.T) {
+	vm := newVM(t)
+	output, err := vm.Eval("print('a', 1) print('b')")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "a\t1\nb\n")
+
+	output, err = vm.Eval("1 + 1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "2\n")
+
+	// Rack files print through the global print at the time they run
+	dir, err := ioutil.TempDir("", "eolian-listen")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	rack := filepath.Join(dir, "rack.lua")
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(`return function(_)
+		return function() return {} end, function() print('patched') end
+	end`), 0644), nil)
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s')", rack)), nil)
+	output, err = vm.Eval("Rack.patch()")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "patched\n")
+}
+
+func TestServe(t *testing.T) {
+	vm := newVM(t)
+
+	dir, err := ioutil.TempDir("", "eolian-listen")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	for _, addr := range []string{"localhost:0", "unix:" + filepath.Join(dir, "eolian.sock")} {
+		l, err := Listen(addr)
+		assert.Equal(t, err, nil)
+		go vm.Serve(l, "secret")
+
+		conn, err := net.Dial(l.Addr().Network(), l.Addr().String())
+		assert.Equal(t, err, nil)
+		dec := json.NewDecoder(conn)
+
+		var resp evalResponse
+		fmt.Fprintln(conn, `{"id": 7, "token": "secret", "code": "print('hello')"}`)
+		assert.Equal(t, dec.Decode(&resp), nil)
+		assert.Equal(t, string(resp.ID), "7")
+		assert.Equal(t, resp.Output, "hello\n")
+		assert.Equal(t, resp.Error, "")
+
+		resp = evalResponse{}
+		fmt.Fprintln(conn, `{"id": 8, "token": "guess", "code": "x = 1"}`)
+		assert.Equal(t, dec.Decode(&resp), nil)
+		assert.Equal(t, string(resp.ID), "8")
+		assert.Equal(t, resp.Error, errBadToken.Error())
+
+		// Raw Lua isn't evaluated
+		resp = evalResponse{}
+		fmt.Fprintln(conn, "x = 1")
+		assert.Equal(t, dec.Decode(&resp), nil)
+		assert.Equal(t, resp.Error, errNotRequest.Error())
+		assert.Equal(t, vm.GetGlobal("x"), lua.LNil)
+
+		resp = evalResponse{}
+		fmt.Fprintln(conn, `{"code": `)
+		assert.Equal(t, dec.Decode(&resp), nil)
+		assert.NotEqual(t, resp.Error, "")
+
+		conn.Close()
+		l.Close()
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	vm := newVM(t)
+
+	l, err := Listen("localhost:0")
+	assert.Equal(t, err, nil)
+	defer l.Close()
+	go vm.Serve(l, "")
+
+	// A browser posting a request body that looks like one
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.Equal(t, err, nil)
+	defer conn.Close()
+	fmt.Fprint(conn, "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Type: text/plain\r\n\r\n")
+	fmt.Fprintln(conn, `{"code": "x = 1"}`)
+
+	_, err = ioutil.ReadAll(conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, vm.GetGlobal("x"), lua.LNil)
+}
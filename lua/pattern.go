@@ -0,0 +1,101 @@
+package lua
+
+import (
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+	"buddin.us/eolian/pattern"
+)
+
+func preloadPattern(state *lua.LState) int {
+	mod := state.NewTable()
+	state.SetFuncs(mod, map[string]lua.LGFunction{
+		"pat": func(state *lua.LState) int {
+			p, err := pattern.Parse(state.CheckString(1))
+			if err != nil {
+				state.RaiseError("%s", err.Error())
+			}
+			state.Push(newPatternUserData(state, p))
+			return 1
+		},
+	})
+	state.Push(mod)
+	return 1
+}
+
+func checkPattern(state *lua.LState, n int) pattern.Pattern {
+	if p, ok := state.CheckUserData(n).Value.(pattern.Pattern); ok {
+		return p
+	}
+	state.ArgError(n, "expected a pattern")
+	return nil
+}
+
+func newPatternUserData(state *lua.LState, p pattern.Pattern) *lua.LUserData {
+	methods := state.NewTable()
+	state.SetFuncs(methods, map[string]lua.LGFunction{
+		"rev": func(state *lua.LState) int {
+			state.Push(newPatternUserData(state, pattern.Reverse(checkPattern(state, 1))))
+			return 1
+		},
+		"rotate": func(state *lua.LState) int {
+			amount := float64(state.CheckNumber(2))
+			state.Push(newPatternUserData(state, pattern.Rotate(checkPattern(state, 1), amount)))
+			return 1
+		},
+		"degrade": func(state *lua.LState) int {
+			var (
+				probability = float64(state.OptNumber(2, 0.5))
+				seed        = int64(state.OptInt64(3, module.Seed()))
+			)
+			state.Push(newPatternUserData(state, pattern.Degrade(checkPattern(state, 1), probability, seed)))
+			return 1
+		},
+		"every": func(state *lua.LState) int {
+			self := state.CheckUserData(1)
+			n := state.CheckInt(2)
+			fn := state.CheckFunction(3)
+			if err := state.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, self); err != nil {
+				state.RaiseError("%s", err.Error())
+			}
+			transformed := state.Get(-1)
+			state.Pop(1)
+			data, ok := transformed.(*lua.LUserData)
+			if !ok {
+				state.RaiseError("every: function must return a pattern")
+			}
+			t, ok := data.Value.(pattern.Pattern)
+			if !ok {
+				state.RaiseError("every: function must return a pattern")
+			}
+			state.Push(newPatternUserData(state, pattern.Every(checkPattern(state, 1), n, t)))
+			return 1
+		},
+		"cycle": func(state *lua.LState) int {
+			events := state.NewTable()
+			for _, e := range checkPattern(state, 1).Cycle(state.OptInt(2, 0)) {
+				event := state.NewTable()
+				event.RawSetString("start", lua.LNumber(e.Start))
+				event.RawSetString("stop", lua.LNumber(e.End))
+				event.RawSetString("value", lua.LString(fmt.Sprint(e.Value)))
+				event.RawSetString("velocity", lua.LNumber(e.Velocity))
+				events.Append(event)
+			}
+			state.Push(events)
+			return 1
+		},
+	})
+
+	mt := state.NewTable()
+	mt.RawSetString("__index", methods)
+	mt.RawSetString("__tostring", state.NewFunction(func(state *lua.LState) int {
+		state.Push(lua.LString(checkPattern(state, 1).String()))
+		return 1
+	}))
+	return &lua.LUserData{
+		Metatable: mt,
+		Value:     p,
+	}
+}
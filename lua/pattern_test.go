@@ -0,0 +1,35 @@
+package lua
+
+import (
+	"testing"
+
+	assert "gopkg.in/go-playground/assert.v1"
+)
+
+func TestPattern(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+	err := vm.DoString(`
+		local p = pat('c3 e3 [g3 b3]')
+		assert(tostring(p) == 'c3 e3 [g3 b3]')
+		assert(tostring(p:rev()) == 'rev(c3 e3 [g3 b3])')
+
+		local events = p:cycle()
+		assert(#events == 4)
+		assert(math.abs(events[3].start - 2/3) < 1e-9 and math.abs(events[3].stop - 5/6) < 1e-9)
+		assert(events[1].velocity == 1)
+
+		local rotated = p:every(2, function(p) return p:rotate(1/3) end)
+		assert(rotated:cycle(0)[1].start == 0)
+		assert(#rotated:cycle(1) == 4)
+		assert(not pcall(function() p:every(2, function() return 1 end) end))
+		assert(not pcall(pat, 'c3 ['))
+
+		local synth = require('eolian.synth')
+		local player = synth.Pattern { pulses = 2 }
+		player:play(p:degrade(0.25, 1))
+		player:play('c3 e3')
+		assert(not pcall(function() player:play('c3 [') end))
+	`)
+	assert.Equal(t, err, nil)
+}
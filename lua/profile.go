@@ -0,0 +1,95 @@
+package lua
+
+import (
+	"sort"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+)
+
+type profiler interface {
+	Profile() module.ProfileStats
+}
+
+func preloadProfile(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			"enable":  profileEnable(exec),
+			"enabled": profileEnabled(exec),
+			"stats":   profileStats(exec),
+		})
+		state.Push(mod)
+		return 1
+	}
+}
+
+func profileEnable(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		enabled := state.OptBool(1, true)
+		exec.Exec(func() error {
+			module.EnableProfiling(enabled)
+			return nil
+		})
+		return 0
+	}
+}
+
+func profileEnabled(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		var enabled bool
+		exec.Exec(func() error {
+			enabled = module.ProfilingEnabled()
+			return nil
+		})
+		state.Push(lua.LBool(enabled))
+		return 1
+	}
+}
+
+// profileStats returns the CPU time spent by a set of modules (keyed by their path in the rack), ordered from most to
+// least expensive. Times are in microseconds per frame.
+func profileStats(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		modules := rackPatchers(state, state.CheckTable(1))
+
+		stats := []module.ProfileStats{}
+		paths := map[string]string{}
+		exec.Exec(func() error {
+			for path, p := range modules {
+				if p, ok := p.(profiler); ok {
+					s := p.Profile()
+					stats = append(stats, s)
+					paths[s.ID] = path
+				}
+			}
+			return nil
+		})
+		sort.Slice(stats, func(i, j int) bool {
+			if stats[i].Mean == stats[j].Mean {
+				return stats[i].ID < stats[j].ID
+			}
+			return stats[i].Mean > stats[j].Mean
+		})
+
+		t := state.NewTable()
+		for _, s := range stats {
+			row := state.NewTable()
+			row.RawSetString("id", lua.LString(s.ID))
+			row.RawSetString("type", lua.LString(s.Type))
+			row.RawSetString("path", lua.LString(paths[s.ID]))
+			row.RawSetString("frames", lua.LNumber(s.Frames))
+			row.RawSetString("mean", lua.LNumber(microseconds(s.Mean)))
+			row.RawSetString("max", lua.LNumber(microseconds(s.Max)))
+			t.Append(row)
+		}
+		state.Push(t)
+		return 1
+	}
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
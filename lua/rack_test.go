@@ -0,0 +1,246 @@
+package lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+)
+
+const rebuiltRack = `return function(_)
+    local synth = require('eolian.synth')
+
+    local function build()
+        return {
+            osc    = synth.Oscillator(),
+            lfo    = synth.Oscillator { multiplier = %d },
+            voices = { synth.Direct(), synth.Direct() },
+            %s
+        }
+    end
+
+    local function patch(rack)
+        rack.voices[1]:set { input = rack.osc:out('sine') }
+    end
+
+    return build, patch
+end`
+
+func TestRebuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-rack")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	rack := filepath.Join(dir, "rack.lua")
+	write := func(multiplier int, extra string) {
+		content := fmt.Sprintf(rebuiltRack, multiplier, extra)
+		assert.Equal(t, ioutil.WriteFile(rack, []byte(content), 0644), nil)
+	}
+	write(1, "noise = synth.Noise(),")
+
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s')", rack)), nil)
+
+	ids := func() map[string]string {
+		result := map[string]string{}
+		for _, key := range []string{"osc", "lfo", "voices[1]", "voices[2]", "noise", "filter"} {
+			output, err := vm.Eval(fmt.Sprintf("local m = Rack.modules.%s; print(m and m:id() or '')", key))
+			assert.Equal(t, err, nil)
+			result[key] = strings.TrimSpace(output)
+		}
+		return result
+	}
+	before := ids()
+
+	write(2, "filter = synth.Filter(),")
+	output, err := vm.Eval("Rack.build()")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "")
+	after := ids()
+
+	// Unchanged modules are kept, including those nested in tables
+	assert.Equal(t, after["osc"], before["osc"])
+	assert.Equal(t, after["voices[1]"], before["voices[1]"])
+	assert.Equal(t, after["voices[2]"], before["voices[2]"])
+
+	// Changed configs and new keys get new modules, and removed keys are gone
+	assert.NotEqual(t, after["lfo"], before["lfo"])
+	assert.NotEqual(t, after["filter"], "")
+	assert.Equal(t, after["noise"], "")
+
+	// Kept modules are repatched
+	output, err = vm.Eval("print(Rack.modules.voices[1]:inputs().input)")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.TrimSpace(output), before["osc"]+"/sine")
+}
+
+// closeCounter is a module that counts how many of its kind were closed
+type closeCounter struct {
+	module.IO
+}
+
+var closedCounters int
+
+func (c *closeCounter) Close() error {
+	closedCounters++
+	return nil
+}
+
+func init() {
+	module.Register("CloseCounter", func(module.Config) (module.Patcher, error) {
+		return &closeCounter{}, nil
+	})
+}
+
+func TestRebuildFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-rack")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	rack := filepath.Join(dir, "rack.lua")
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(fmt.Sprintf(rebuiltRack, 1, "")), 0644), nil)
+
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s')", rack)), nil)
+	osc, err := vm.Eval("print(Rack.modules.osc:id())")
+	assert.Equal(t, err, nil)
+
+	// The modules created before build failed are closed, and the running rack is left alone
+	closedCounters = 0
+	broken := fmt.Sprintf(rebuiltRack, 1, "counter = synth.CloseCounter(), broken = error('broken'),")
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(broken), 0644), nil)
+	output, err := vm.Eval("Rack.build()")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.Contains(output, "broken"), true)
+	assert.Equal(t, closedCounters, 1)
+
+	after, err := vm.Eval("print(Rack.modules.osc:id())")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, after, osc)
+}
+
+// countingExecutor counts the trips made to the module graph
+type countingExecutor struct {
+	module.LockExecutor
+	calls int
+}
+
+func (e *countingExecutor) Exec(fn func() error) error {
+	e.calls++
+	return e.LockExecutor.Exec(fn)
+}
+
+const batchedRack = `return function(_)
+    local synth = require('eolian.synth')
+
+    local function build()
+        return { voices = { synth.Direct(), synth.Direct(), synth.Direct() } }
+    end
+
+    local function patch(rack)
+        for i, voice in ipairs(rack.voices) do
+            voice:set { input = i }
+        end
+        %s
+    end
+
+    return build, patch
+end`
+
+func TestPatchBatched(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-rack")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	init, err := module.Lookup("Direct")
+	assert.Equal(t, err, nil)
+	direct, err := init(nil)
+	assert.Equal(t, err, nil)
+	exec := &countingExecutor{LockExecutor: module.LockExecutor{Locker: &sync.Mutex{}}}
+	vm, err := NewVM(direct, exec)
+	assert.Equal(t, err, nil)
+	defer vm.Close()
+
+	rack := filepath.Join(dir, "rack.lua")
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(fmt.Sprintf(batchedRack, "")), 0644), nil)
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s')", rack)), nil)
+
+	// Patching takes a single trip to the module graph however many changes it makes
+	exec.calls = 0
+	assert.Equal(t, vm.DoString("Rack.patch()"), nil)
+	assert.Equal(t, exec.calls, 1)
+	assert.Equal(t, vm.DoString("assert(Rack.modules.voices[3]:inputs().input == '3.00')"), nil)
+
+	// Mistakes are reported with the line they were made on
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(fmt.Sprintf(batchedRack, "rack.voices[1]:set { nope = 1 }")), 0644), nil)
+	err = vm.DoString(fmt.Sprintf("Rack.load('%s')", rack))
+	assert.NotEqual(t, err, nil)
+	assert.Equal(t, strings.Contains(err.Error(), "rack.lua:12: unknown input \"nope\""), true)
+}
+
+const fadedRack = `return function(_)
+    local synth = require('eolian.synth')
+
+    local function build()
+        return {
+            proc  = synth.LuaProcessor { script = 'function process(i, o) o.output[1] = %d end' },
+            voice = synth.Direct(),
+        }
+    end
+
+    local function patch(rack)
+        rack.voice:set { input = rack.proc:out() }
+    end
+
+    return build, patch
+end`
+
+func TestRebuildFade(t *testing.T) {
+	defer func(fade int) { module.DefaultFade = fade }(module.DefaultFade)
+	module.DefaultFade = 2 * dsp.FrameSize
+
+	dir, err := ioutil.TempDir("", "eolian-rack")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	rack := filepath.Join(dir, "rack.lua")
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(fmt.Sprintf(fadedRack, 1)), 0644), nil)
+
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s'); old = Rack.modules.proc", rack)), nil)
+
+	voice, ok := patcherOf(lookupPath(vm.G.Global, "Rack.modules.voice"))
+	assert.Equal(t, ok, true)
+	out, err := voice.Output("output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+	status := func() string {
+		output, err := vm.Eval("print(old:status())")
+		assert.Equal(t, err, nil)
+		return strings.TrimSpace(output)
+	}
+
+	// The replaced processor is closed only once the voice has faded away from it
+	assert.Equal(t, ioutil.WriteFile(rack, []byte(fmt.Sprintf(fadedRack, 2)), 0644), nil)
+	output, err := vm.Eval("Rack.build()")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "")
+	assert.Equal(t, status(), "running")
+	for i := 0; i < 3; i++ {
+		out.Process(frame)
+	}
+	assert.Equal(t, frame[0], dsp.Float64(2))
+	assert.Equal(t, status(), "module was closed")
+}
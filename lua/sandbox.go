@@ -0,0 +1,174 @@
+package lua
+
+import (
+	"io/ioutil"
+	"runtime/debug"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/lua/instrument"
+	"buddin.us/eolian/module"
+)
+
+// sandbox confines rack files loaded with -sandbox. Their chunks are instrumented, so every loop iteration and function
+// call counts as a step and the heap is checked every so many of them.
+type sandbox struct {
+	enabled bool
+	counter instrument.Counter
+
+	// Limits apply while the rack is being loaded, built or patched; depth tracks nested runs
+	depth int
+
+	// The string library strings index while the limits apply, with rep limited
+	strings *lua.LTable
+}
+
+// Sandbox confines the rack files loaded from now on to a directory: they get a restricted environment, modules may
+// only read and write files within it, and loading, building or patching the rack is limited in steps and memory. The
+// top level of LuaProcessor scripts is held to the same limits.
+func (vm *VM) Sandbox(dir string, limits instrument.Limits) error {
+	vm.mtx.Lock()
+	defer vm.mtx.Unlock()
+	if err := module.SetSandbox(dir, limits); err != nil {
+		return err
+	}
+	vm.sandbox.enabled, vm.sandbox.counter.Limits = true, limits
+	return nil
+}
+
+func preloadSandbox(s *sandbox) lua.LGFunction {
+	return func(state *lua.LState) int {
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			"enabled": func(state *lua.LState) int {
+				state.Push(lua.LBool(s.enabled))
+				return 1
+			},
+			"check": func(state *lua.LState) int {
+				if err := module.CheckPath(state.CheckString(1)); err != nil {
+					state.RaiseError("%s", err.Error())
+				}
+				return 0
+			},
+			"loadfile": func(state *lua.LState) int {
+				path := state.CheckString(1)
+				env := state.CheckTable(2)
+				if err := module.CheckPath(path); err != nil {
+					state.RaiseError("%s", err.Error())
+				}
+				src, err := ioutil.ReadFile(path)
+				if err != nil {
+					state.RaiseError("%s", err.Error())
+				}
+				fn, err := s.compile(state, string(src), "@"+path, env)
+				if err != nil {
+					state.RaiseError("%s", err.Error())
+				}
+				state.Push(fn)
+				return 1
+			},
+			"loadstring": func(state *lua.LState) int {
+				src := state.CheckString(1)
+				env := state.CheckTable(2)
+				fn, err := s.compile(state, src, state.OptString(3, "<string>"), env)
+				if err != nil {
+					state.Push(lua.LNil)
+					state.Push(lua.LString(err.Error()))
+					return 2
+				}
+				state.Push(fn)
+				return 1
+			},
+			"run": func(state *lua.LState) int {
+				return s.run(state)
+			},
+			"rep": s.rep,
+		})
+		state.Push(mod)
+		return 1
+	}
+}
+
+// compile loads a chunk instrumented to count its steps, running in env
+func (s *sandbox) compile(state *lua.LState, src, name string, env *lua.LTable) (*lua.LFunction, error) {
+	proto, err := instrument.Compile(src, name)
+	if err != nil {
+		return nil, err
+	}
+	env.RawSetString(instrument.StepName, state.NewFunction(s.step))
+	return &lua.LFunction{Env: env, Proto: proto, Upvalues: []*lua.Upvalue{}}, nil
+}
+
+// run calls a function with the limits in place, returning whatever it returns
+func (s *sandbox) run(state *lua.LState) int {
+	fn := state.CheckFunction(1)
+	if s.depth == 0 {
+		s.counter.Reset()
+		defer s.confine(state)()
+	}
+	s.depth++
+	defer func() { s.depth-- }()
+
+	top := state.GetTop()
+	state.Push(fn)
+	for i := 2; i <= top; i++ {
+		state.Push(state.Get(i))
+	}
+	state.Call(top-1, lua.MultRet)
+	if err := s.counter.CheckMemory(); err != nil {
+		state.RaiseError("rack %s", err)
+	}
+	return state.GetTop() - top
+}
+
+// confine limits what the process and the string methods allow until the returned function restores them. Strings
+// share the string library as their metatable's index, so s:rep(n) would otherwise reach the host's string.rep. The
+// memory limit is a backstop for growth between heap checks: the runtime collects harder as the process nears it.
+func (s *sandbox) confine(state *lua.LState) func() {
+	mt := state.GetMetatable(lua.LString(""))
+	index := state.GetField(mt, "__index")
+	if s.strings == nil {
+		s.strings = state.NewTable()
+		if lib, ok := index.(*lua.LTable); ok {
+			lib.ForEach(func(k, v lua.LValue) { s.strings.RawSet(k, v) })
+		}
+		s.strings.RawSetString("rep", state.NewFunction(s.rep))
+	}
+	state.SetField(mt, "__index", s.strings)
+
+	memoryLimit := int64(-1)
+	if limit := s.counter.Limits.Memory; limit > 0 {
+		memoryLimit = debug.SetMemoryLimit(int64(s.counter.HeapAlloc() + 2*limit))
+	}
+	return func() {
+		state.SetField(mt, "__index", index)
+		if memoryLimit >= 0 {
+			debug.SetMemoryLimit(memoryLimit)
+		}
+	}
+}
+
+func (s *sandbox) step(state *lua.LState) int {
+	if s.depth == 0 {
+		return 0
+	}
+	if err := s.counter.Step(); err != nil {
+		state.RaiseError("rack %s", err)
+	}
+	return 0
+}
+
+// rep is string.rep, refusing to build strings larger than the memory limit
+func (s *sandbox) rep(state *lua.LState) int {
+	str, n := state.CheckString(1), state.CheckInt(2)
+	if n <= 0 {
+		state.Push(lua.LString(""))
+		return 1
+	}
+	if limit := s.counter.Limits.Memory; limit > 0 && uint64(len(str))*uint64(n) > limit {
+		state.RaiseError("string.rep: result is over the sandbox's memory limit")
+	}
+	state.Push(lua.LString(strings.Repeat(str, n)))
+	return 1
+}
@@ -0,0 +1,104 @@
+package lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+
+	"buddin.us/eolian/lua/instrument"
+	"buddin.us/eolian/module"
+)
+
+const sandboxedRack = `return function(env)
+    local synth  = require('eolian.synth')
+    local helper = require('helper')
+
+    local function build()
+        %s
+        return { osc = synth.Oscillator { multiplier = helper.multiplier } }
+    end
+
+    local function patch(rack)
+        rack.osc:set { pitchMod = 0 }
+    end
+
+    return build, patch
+end`
+
+func TestSandbox(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-sandbox")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	defer module.SetSandbox("", instrument.Limits{})
+
+	outside := filepath.Join(os.TempDir(), "eolian-sandbox-outside.txt")
+	assert.Equal(t, ioutil.WriteFile(outside, []byte("secret"), 0644), nil)
+	defer os.Remove(outside)
+
+	helper := filepath.Join(dir, "helper.lua")
+	assert.Equal(t, ioutil.WriteFile(helper, []byte("return { multiplier = 2 }"), 0644), nil)
+
+	rack := filepath.Join(dir, "rack.lua")
+	load := func(body string) error {
+		content := fmt.Sprintf(sandboxedRack, body)
+		assert.Equal(t, ioutil.WriteFile(rack, []byte(content), 0644), nil)
+		vm := newVM(t)
+		defer vm.Close()
+		assert.Equal(t, vm.Sandbox(dir, instrument.Limits{Steps: 100000, Memory: 16 << 20}), nil)
+		err := vm.DoString(fmt.Sprintf("Rack.load('%s')", rack))
+
+		// The limits are lifted once the rack's code returns
+		assert.Equal(t, debug.SetMemoryLimit(-1), int64(math.MaxInt64))
+		assert.Equal(t, vm.DoString("assert(#('x'):rep(32 * 1024 * 1024) > 0)"), nil)
+		return err
+	}
+
+	var tests = []struct {
+		body, err string
+	}{
+		{"", ""},
+		{"local f = io.open('" + helper + "'); f:close()", ""},
+		{"os.execute('true')", "non-function"},
+		{"io.open('" + outside + "')", "outside of the sandbox"},
+		{"dofile('" + outside + "')", "outside of the sandbox"},
+		{"require('eolian.rack.snapshot')", "no such file"},
+		{"local f = loadstring('return 1'); assert(getfenv == nil and f() == 1)", ""},
+		{"while true do end", "steps"},
+		{"for i = 1, 10 do pcall(function() while true do end end) end", "steps"},
+		{"local s = ('x'):rep(1e9)", "memory limit"},
+		{"local s = 'x' for i = 1, 27 do s = s .. s end", "memory limit"},
+		{"assert(getmetatable('') == nil and getmetatable(require('eolian.value').hz(1)) == nil)", ""},
+		{"local mt = {}; assert(getmetatable(setmetatable({}, mt)) == mt)", ""},
+		{"synth.LuaProcessor { script = 'while true do end function process() end' }", "steps"},
+		{"synth.Tape { file = '" + outside + "' }", "outside of the sandbox"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			err := load(test.body)
+			if test.err == "" {
+				assert.Equal(t, err, nil)
+				return
+			}
+			assert.NotEqual(t, err, nil)
+			assert.Equal(t, strings.Contains(err.Error(), test.err), true)
+		})
+	}
+}
+
+func TestCheckPathWithoutSandbox(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+	assert.Equal(t, vm.DoString(`
+		local sandbox = require('eolian.sandbox')
+		assert(not sandbox.enabled())
+		sandbox.check('/etc/passwd')
+	`), nil)
+}
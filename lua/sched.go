@@ -0,0 +1,462 @@
+package lua
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+)
+
+const (
+	// Tasks are resumed this far ahead of their time so the changes they make are queued before the frame they land in
+	schedLookahead = 50 // ms
+
+	schedEdgeBacklog = 64
+	taskTypeName     = "eolian.sched.task"
+)
+
+// scheduler runs Lua tasks against the engine's sample clock. Each task is a coroutine with its own logical time that
+// advances exactly by the durations it waits, so it doesn't drift however late it's resumed. Changes made with :set
+// while a task runs are applied at the task's time.
+type scheduler struct {
+	exec    module.Executor
+	clock   module.Scheduler
+	tasks   []*task
+	current *task
+	watches map[*module.Out]*edgeWatch
+
+	// While a rack is being patched its changes are queued, to be applied together instead of each waiting for the
+	// audio thread
+	batching int
+	batchLua *lua.LState
+	batch    []batched
+}
+
+// batched is a change queued while patching, along with the place in the rack's files it was made
+type batched struct {
+	where string
+	fn    func() error
+}
+
+type task struct {
+	thread    *lua.LState
+	fn        *lua.LFunction
+	args      []lua.LValue
+	time      int64
+	cancelled bool
+
+	// A task waits either until a time or for a number of edges of an output
+	wake  int64
+	watch *edgeWatch
+	edges int
+	ready bool
+
+	// The watch whose pulses the task predicted with beat, which is kept attached while the task relies on it
+	beat *edgeWatch
+}
+
+// edgeWatch follows the rising edges of an output and the interval between the last two of them
+type edgeWatch struct {
+	out          *module.Out
+	edges        *module.Edges
+	last, period int64
+}
+
+func newScheduler(exec module.Executor) *scheduler {
+	clock, _ := exec.(module.Scheduler)
+	return &scheduler{exec: exec, clock: clock, watches: map[*module.Out]*edgeWatch{}}
+}
+
+// Exec runs a function through the Executor the scheduler was created with, after any changes queued while patching
+func (s *scheduler) Exec(fn func() error) error {
+	if len(s.batch) == 0 {
+		return s.exec.Exec(fn)
+	}
+	batch := s.batch
+	s.batch = nil
+	return s.exec.Exec(func() error {
+		if err := runBatch(batch); err != nil {
+			return err
+		}
+		return fn()
+	})
+}
+
+// ExecPatch applies patches at the time of the running task, if there is one, and right away otherwise. When the
+// patches are delayed, check is run right away instead so that mistakes like unknown inputs are raised in the task
+// rather than by the engine.
+func (s *scheduler) ExecPatch(check, fn func() error) error {
+	if s.current == nil && s.batching > 0 {
+		s.batch = append(s.batch, batched{where: s.batchLua.Where(1), fn: fn})
+		return nil
+	}
+	if s.current == nil || s.clock == nil {
+		return s.Exec(fn)
+	}
+	if check != nil {
+		if err := s.exec.Exec(check); err != nil {
+			return err
+		}
+	}
+	s.clock.ExecAt(s.current.time, fn)
+	return nil
+}
+
+// flush applies the changes queued while patching
+func (s *scheduler) flush() error {
+	return s.Exec(func() error { return nil })
+}
+
+// runBatch applies queued changes in order until one of them fails
+func runBatch(batch []batched) error {
+	for _, b := range batch {
+		if err := b.fn(); err != nil {
+			if b.where == "" {
+				return err
+			}
+			return fmt.Errorf("%s %s", b.where, err)
+		}
+	}
+	return nil
+}
+
+// preloadBatch returns a function that calls a function with the remaining arguments, queueing the changes it makes to
+// the module graph until it returns. The rack patches through it so patching takes a single trip to the audio thread.
+func preloadBatch(s *scheduler) lua.LGFunction {
+	return func(state *lua.LState) int {
+		state.Push(state.NewFunction(func(state *lua.LState) int {
+			top := state.GetTop()
+			state.Push(state.CheckFunction(1))
+			for i := 2; i <= top; i++ {
+				state.Push(state.Get(i))
+			}
+
+			s.batching++
+			if s.batching == 1 {
+				s.batchLua = state
+			}
+			err := state.PCall(top-1, lua.MultRet, nil)
+			if s.batching--; s.batching == 0 {
+				// Changes made before an error are applied all the same, as they would have been without batching
+				if flushErr := s.flush(); err == nil && flushErr != nil {
+					state.Error(lua.LString(flushErr.Error()), 0)
+				}
+			}
+			if err != nil {
+				if apiErr, ok := err.(*lua.ApiError); ok {
+					state.Error(apiErr.Object, 0)
+				}
+				state.RaiseError("%s", err)
+			}
+			return state.GetTop() - top
+		}))
+		return 1
+	}
+}
+
+func lookahead() int64 {
+	return int64(dsp.Duration(schedLookahead).Value())
+}
+
+func (s *scheduler) active() bool {
+	return len(s.tasks) > 0 || len(s.watches) > 0
+}
+
+// tick collects edges seen by the audio thread and resumes the tasks that are due
+func (s *scheduler) tick(state *lua.LState) {
+	for _, w := range s.watches {
+		for more := true; more; {
+			select {
+			case t := <-w.edges.Times():
+				s.edge(w, t)
+			default:
+				more = false
+			}
+		}
+	}
+
+	now := s.clock.Now()
+	due := []*task{}
+	for _, t := range s.tasks {
+		if t.ready || (t.watch == nil && t.wake-lookahead() <= now) {
+			due = append(due, t)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].wake < due[j].wake })
+	for _, t := range due {
+		if !t.cancelled {
+			s.resume(state, t)
+		}
+	}
+
+	tasks := s.tasks[:0]
+	for _, t := range s.tasks {
+		if !t.cancelled {
+			tasks = append(tasks, t)
+		}
+	}
+	s.tasks = tasks
+	s.detach()
+}
+
+// detach removes the edge watches no task is waiting on or predicting beats from anymore
+func (s *scheduler) detach() {
+	used := map[*edgeWatch]bool{}
+	for _, t := range s.tasks {
+		used[t.watch] = true
+		used[t.beat] = true
+	}
+	for out, w := range s.watches {
+		if used[w] {
+			continue
+		}
+		s.exec.Exec(func() error {
+			out.RemoveEdges(w.edges)
+			return nil
+		})
+		delete(s.watches, out)
+	}
+}
+
+func (s *scheduler) edge(w *edgeWatch, t int64) {
+	if w.last > 0 {
+		w.period = t - w.last
+	}
+	w.last = t
+	for _, task := range s.tasks {
+		if task.watch != w {
+			continue
+		}
+		if task.edges--; task.edges == 0 {
+			task.watch = nil
+			task.wake = t
+			task.ready = true
+		}
+	}
+}
+
+func (s *scheduler) resume(state *lua.LState, t *task) {
+	t.time = t.wake
+	t.ready = false
+	t.beat = nil
+	s.current = t
+	status, err, _ := state.Resume(t.thread, t.fn, t.args...)
+	s.current = nil
+	t.args = nil
+
+	switch status {
+	case lua.ResumeError:
+		fmt.Println("error:", err)
+		t.cancelled = true
+	case lua.ResumeOK:
+		t.cancelled = true
+	}
+}
+
+func (s *scheduler) running(state *lua.LState, name string) *task {
+	if s.clock == nil {
+		state.RaiseError("sched.%s needs the audio engine's clock", name)
+	}
+	if s.current == nil || s.current.thread != state {
+		state.RaiseError("sched.%s must be called from a task started with sched.spawn", name)
+	}
+	return s.current
+}
+
+// watch returns the edge watch of an output, attaching one if needed
+func (s *scheduler) watch(state *lua.LState, v lua.LValue) *edgeWatch {
+	port, ok := toPort(v)
+	if !ok {
+		state.RaiseError("expected a module or output; got %s", v.Type())
+	}
+	var out *module.Out
+	err := s.exec.Exec(func() error {
+		var err error
+		out, err = port.Patcher.Output(port.Port)
+		return err
+	})
+	if err != nil {
+		state.RaiseError("%s", err.Error())
+	}
+	if w, ok := s.watches[out]; ok {
+		return w
+	}
+
+	w := &edgeWatch{out: out, edges: module.NewEdges(s.clock.Now, schedEdgeBacklog)}
+	s.exec.Exec(func() error {
+		out.AddEdges(w.edges)
+		return nil
+	})
+	s.watches[out] = w
+	return w
+}
+
+// toPort accepts an output, or a module whose "output" is used
+func toPort(v lua.LValue) (module.Port, bool) {
+	switch v := v.(type) {
+	case *lua.LUserData:
+		port, ok := v.Value.(module.Port)
+		return port, ok
+	case *lua.LTable:
+		if data, ok := v.RawGetString("__patcher").(*lua.LUserData); ok {
+			if p, ok := data.Value.(module.Patcher); ok {
+				return module.Port{Patcher: p, Port: "output"}, true
+			}
+		}
+	}
+	return module.Port{}, false
+}
+
+// stop cancels every task and detaches the edge watches
+func (s *scheduler) stop() {
+	for _, t := range s.tasks {
+		t.cancelled = true
+	}
+	s.tasks = nil
+	for out, w := range s.watches {
+		s.exec.Exec(func() error {
+			out.RemoveEdges(w.edges)
+			return nil
+		})
+	}
+	s.watches = map[*module.Out]*edgeWatch{}
+}
+
+// samples converts a duration to samples. Numbers are milliseconds, and frequencies are the length of one cycle.
+func samples(state *lua.LState, v lua.LValue) int64 {
+	switch v := v.(type) {
+	case lua.LNumber:
+		return int64(dsp.Duration(float64(v)).Value())
+	case *lua.LUserData:
+		switch d := v.Value.(type) {
+		case dsp.MS:
+			return int64(d.Value())
+		case dsp.Hz, dsp.BeatsPerMin:
+			if f := d.(dsp.Valuer).Value(); f > 0 {
+				return int64(1 / f)
+			}
+			state.RaiseError("can't wait for a cycle of %s", d)
+		}
+	}
+	state.RaiseError("expected a duration; got %s", v.Type())
+	return 0
+}
+
+func preloadSched(s *scheduler) lua.LGFunction {
+	return func(state *lua.LState) int {
+		taskType := state.NewTypeMetatable(taskTypeName)
+		state.SetField(taskType, "__index", state.SetFuncs(state.NewTable(), map[string]lua.LGFunction{
+			"cancel": func(state *lua.LState) int {
+				t := state.CheckUserData(1).Value.(*task)
+				t.cancelled = true
+				return 0
+			},
+		}))
+
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			// now returns the sample time of the running task, or of the engine outside of tasks
+			"now": func(state *lua.LState) int {
+				if s.current != nil {
+					state.Push(lua.LNumber(s.current.time))
+				} else if s.clock != nil {
+					state.Push(lua.LNumber(s.clock.Now()))
+				} else {
+					state.Push(lua.LNumber(0))
+				}
+				return 1
+			},
+
+			// spawn starts a task that runs fn with the remaining arguments. Tasks started by other tasks begin at
+			// their parent's time; others begin at the engine's current time.
+			"spawn": func(state *lua.LState) int {
+				if s.clock == nil {
+					state.RaiseError("sched.spawn needs the audio engine's clock")
+				}
+				t := &task{fn: state.CheckFunction(1), thread: state.NewThread(), ready: true}
+				for i := 2; i <= state.GetTop(); i++ {
+					t.args = append(t.args, state.Get(i))
+				}
+				if s.current != nil {
+					t.wake = s.current.time
+				} else {
+					t.wake = s.clock.Now()
+				}
+				s.tasks = append(s.tasks, t)
+
+				data := state.NewUserData()
+				data.Value = t
+				state.SetMetatable(data, taskType)
+				state.Push(data)
+				return 1
+			},
+
+			// wait pauses the running task for a duration
+			"wait": func(state *lua.LState) int {
+				t := s.running(state, "wait")
+				t.wake = t.time + samples(state, state.CheckAny(1))
+				return state.Yield()
+			},
+
+			// edge pauses the running task until the nth next rising edge of an output, and sets its time to the
+			// time of the edge. Edges are only seen while the output is patched into the rack.
+			"edge": func(state *lua.LState) int {
+				t := s.running(state, "edge")
+				t.watch = s.watch(state, state.CheckAny(1))
+				t.edges = state.OptInt(2, 1)
+				return state.Yield()
+			},
+
+			// beat pauses the running task until the nth next pulse of a clock. Once two pulses have been seen, the
+			// task is resumed ahead of the pulse so its changes land exactly on it; until then it waits for the
+			// pulses like edge.
+			"beat": func(state *lua.LState) int {
+				t := s.running(state, "beat")
+				w := s.watch(state, state.CheckAny(1))
+				n := int64(state.OptInt(2, 1))
+				if w.period == 0 {
+					t.watch, t.edges = w, int(n)
+					return state.Yield()
+				}
+				t.beat = w
+				next := w.last + w.period
+				if next <= t.time {
+					next += ((t.time-next)/w.period + 1) * w.period
+				}
+				t.wake = next + (n-1)*w.period
+				return state.Yield()
+			},
+
+			// stop cancels every task
+			"stop": func(state *lua.LState) int {
+				s.stop()
+				return 0
+			},
+		})
+		state.Push(mod)
+		return 1
+	}
+}
+
+// Schedule resumes the tasks started with the eolian.sched module as they come due. It checks them at an interval
+// until done is closed.
+func (vm *VM) Schedule(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			vm.mtx.Lock()
+			if vm.sched.clock != nil && vm.sched.active() {
+				vm.sched.tick(vm.LState)
+			}
+			vm.mtx.Unlock()
+		}
+	}
+}
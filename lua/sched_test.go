@@ -0,0 +1,156 @@
+package lua
+
+import (
+	"sync"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+)
+
+// fakeClock records the functions queued with ExecAt instead of running them
+type fakeClock struct {
+	module.LockExecutor
+	now    int64
+	queued []int64
+}
+
+func (c *fakeClock) Now() int64 { return c.now }
+
+func (c *fakeClock) ExecAt(at int64, fn func() error) {
+	c.queued = append(c.queued, at)
+	c.Exec(fn)
+}
+
+func TestSched(t *testing.T) {
+	init, err := module.Lookup("Direct")
+	assert.Equal(t, err, nil)
+	direct, err := init(nil)
+	assert.Equal(t, err, nil)
+
+	clock := &fakeClock{LockExecutor: module.LockExecutor{Locker: &sync.Mutex{}}}
+	vm, err := NewVM(direct, clock)
+	assert.Equal(t, err, nil)
+	defer vm.Close()
+
+	var (
+		wait  = int64(dsp.Duration(100).Value())
+		ahead = lookahead()
+		frame = dsp.NewFrame()
+	)
+
+	// Tasks keep exact time and are resumed ahead of it
+	err = vm.DoString(`
+		sched = require('eolian.sched')
+		synth = require('eolian.synth')
+		osc   = synth.Direct()
+		pulse = synth.Direct()
+
+		task = sched.spawn(function(n)
+			for i = 1, n do
+				osc:set { input = i }
+				sched.wait(100)
+			end
+		end, 3)
+	`)
+	assert.Equal(t, err, nil)
+	vm.sched.tick(vm.LState)
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{0})
+	clock.now = wait - ahead
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{0, wait})
+
+	// Changes outside of tasks aren't scheduled, and cancelled tasks stop
+	assert.Equal(t, vm.DoString(`osc:set { input = 0 }; task:cancel()`), nil)
+	clock.now = 2 * wait
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{0, wait})
+	assert.Equal(t, len(vm.sched.tasks), 0)
+
+	// Tasks waiting for an edge take its time
+	clock.queued = nil
+	err = vm.DoString(`
+		sched.spawn(function()
+			sched.edge(pulse)
+			osc:set { input = 1 }
+			sched.beat(pulse:out(), 2)
+			osc:set { input = 2 }
+		end)
+	`)
+	assert.Equal(t, err, nil)
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, len(vm.sched.watches), 1)
+
+	var out *module.Out
+	for o := range vm.sched.watches {
+		out = o
+	}
+	edge := func(at int64) {
+		clock.now = at
+		assert.Equal(t, vm.DoString(`pulse:set { input = 1 }`), nil)
+		out.Process(frame)
+		clock.now += int64(len(frame))
+		assert.Equal(t, vm.DoString(`pulse:set { input = 0 }`), nil)
+		out.Process(frame)
+	}
+	edge(1000)
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{1000})
+
+	// The first beat is found by waiting for the pulses, after which beats are predicted
+	edge(5000)
+	vm.sched.tick(vm.LState)
+	edge(9000)
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{1000, 9000})
+
+	// Watches are detached once no task waits on them, so the beat is learned again
+	assert.Equal(t, len(vm.sched.watches), 0)
+
+	// Waiting is only possible within a task
+	assert.NotEqual(t, vm.DoString(`sched.wait(100)`), nil)
+
+	assert.Equal(t, vm.DoString(`
+		sched.spawn(function()
+			sched.beat(pulse, 2)
+			osc:set { input = 3 }
+			sched.beat(pulse, 2)
+			osc:set { input = 4 }
+		end)
+	`), nil)
+	vm.sched.tick(vm.LState)
+	for o := range vm.sched.watches {
+		out = o
+	}
+	edge(13000)
+	vm.sched.tick(vm.LState)
+	edge(17000)
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{1000, 9000, 17000})
+	assert.Equal(t, len(vm.sched.watches), 1)
+	clock.now = 25000 - ahead
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{1000, 9000, 17000, 25000})
+	assert.Equal(t, len(vm.sched.watches), 0)
+
+	// Every change to the graph made by a task lands at its time, and mistakes are raised within the task
+	clock.queued = nil
+	clock.now = 30000
+	assert.Equal(t, vm.DoString(`
+		sched.spawn(function()
+			osc:set { input = 5 }
+			osc:close()
+			osc:resetOnly { 'input' }
+			ok, err = pcall(osc.set, osc, { nope = 1 })
+		end)
+	`), nil)
+	vm.sched.tick(vm.LState)
+	assert.Equal(t, clock.queued, []int64{30000, 30000, 30000})
+	assert.Equal(t, vm.DoString(`assert(not ok and err:find('unknown input "nope"'))`), nil)
+
+	assert.Equal(t, vm.DoString(`sched.stop()`), nil)
+	assert.Equal(t, vm.sched.active(), false)
+}
@@ -2,25 +2,31 @@ package lua
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sync"
 
 	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
 )
 
 type session struct {
 	sync.Mutex
 	history []string
+	exec    module.Executor
 }
 
-func newSession(state *lua.LState) *session {
+func newSession(state *lua.LState, exec module.Executor) *session {
 	s := &session{
 		history: []string{},
+		exec:    exec,
 	}
 	fns := map[string]lua.LGFunction{
-		"reset": s.reset,
-		"save":  s.save,
+		"export": s.export,
+		"reset":  s.reset,
+		"save":   s.save,
 	}
 	state.RegisterModule("session", fns)
 	return s
@@ -54,6 +60,24 @@ func (s *session) save(state *lua.LState) int {
 	return 0
 }
 
+// export writes the modules of the session to a rack file that recreates them along with their connections and values
+func (s *session) export(state *lua.LState) int {
+	name := state.CheckString(1)
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		state.RaiseError("absolute path %s: %s", name, err)
+	}
+	src, warnings := exportSession(state, s.exec)
+	if err := ioutil.WriteFile(abs, []byte(src), 0660); err != nil {
+		state.RaiseError("write %s: %s", abs, err)
+	}
+	for _, w := range warnings {
+		fmt.Println(w)
+	}
+	fmt.Printf("Rack written to %s\n", abs)
+	return 0
+}
+
 func (s *session) reset(state *lua.LState) int {
 	s.Lock()
 	s.history = []string{}
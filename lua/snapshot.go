@@ -0,0 +1,228 @@
+package lua
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/module"
+)
+
+func preloadSnapshot(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			"save":    snapshotSave(exec),
+			"restore": snapshotRestore(exec),
+			"morph":   snapshotMorph(exec),
+		})
+		state.Push(mod)
+		return 1
+	}
+}
+
+// snapshotSave writes the inputs of a set of modules (keyed by their path in the rack) to a JSON file
+func snapshotSave(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		name := state.CheckString(1)
+		modules := rackPatchers(state, state.CheckTable(2))
+
+		paths := map[string]string{}
+		for path, p := range modules {
+			paths[p.ID()] = path
+		}
+
+		var (
+			s        = module.Snapshot{Modules: map[string]module.ModuleSnapshot{}}
+			warnings []string
+		)
+		exec.Exec(func() error {
+			for path, p := range modules {
+				ms := module.ModuleSnapshot{Type: p.Type(), Inputs: map[string]module.InputState{}}
+				for name, in := range p.Inputs() {
+					is, ok := in.State()
+					if !ok {
+						continue
+					}
+					if is.IsConnection() {
+						source, ok := paths[is.Module]
+						if !ok {
+							warnings = append(warnings, fmt.Sprintf("%s.%s: source %s is not part of the rack", path, name, is.Module))
+							continue
+						}
+						is.Module = source
+					}
+					ms.Inputs[name] = is
+				}
+				s.Modules[path] = ms
+			}
+			return nil
+		})
+
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			state.RaiseError("absolute path %s: %s", name, err)
+		}
+		raw, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			state.RaiseError("encode snapshot: %s", err)
+		}
+		if err := ioutil.WriteFile(abs, raw, 0660); err != nil {
+			state.RaiseError("write %s: %s", abs, err)
+		}
+		fmt.Printf("Snapshot written to %s\n", abs)
+
+		state.Push(stringList(state, warnings))
+		return 1
+	}
+}
+
+// snapshotRestore reads a JSON snapshot and patches its values and connections onto a set of modules (keyed by their
+// path in the rack)
+func snapshotRestore(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		name := state.CheckString(1)
+		modules := rackPatchers(state, state.CheckTable(2))
+
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			state.RaiseError("absolute path %s: %s", name, err)
+		}
+		s, err := module.ReadSnapshot(abs)
+		if err != nil {
+			state.RaiseError("%s", err)
+		}
+
+		var warnings []string
+		exec.Exec(func() error {
+			warnings = restoreSnapshot(s, modules)
+			return nil
+		})
+		state.Push(stringList(state, warnings))
+		return 1
+	}
+}
+
+type morphTargeter interface {
+	Targets() []module.MorphTarget
+}
+
+// snapshotMorph patches each input targeted by a Morph module to the corresponding output of that module
+func snapshotMorph(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		data := state.CheckUserData(1)
+		modules := rackPatchers(state, state.CheckTable(2))
+
+		morph, ok := data.Value.(module.Patcher)
+		if !ok {
+			state.RaiseError("expected module.Patcher; got %T", data.Value)
+		}
+		targeter, ok := morph.(morphTargeter)
+		if !ok {
+			state.RaiseError("expected Morph module; got %s", morph.Type())
+		}
+
+		var warnings []string
+		exec.Exec(func() error {
+			for i, t := range targeter.Targets() {
+				p, ok := modules[t.Module]
+				if !ok {
+					warnings = append(warnings, fmt.Sprintf("%s: module is not part of the rack", t.Module))
+					continue
+				}
+				if err := p.Patch(t.Input, module.Port{Patcher: morph, Port: strconv.Itoa(i)}); err != nil {
+					warnings = append(warnings, fmt.Sprintf("%s.%s: %s", t.Module, t.Input, err))
+				}
+			}
+			return nil
+		})
+		state.Push(stringList(state, warnings))
+		return 1
+	}
+}
+
+func restoreSnapshot(s module.Snapshot, modules map[string]module.Patcher) []string {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	paths := []string{}
+	for path := range s.Modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		ms := s.Modules[path]
+		p, ok := modules[path]
+		if !ok {
+			warn("%s: module is not part of the rack", path)
+			continue
+		}
+		if p.Type() != ms.Type {
+			warn("%s: expected module of type %s; got %s", path, ms.Type, p.Type())
+			continue
+		}
+
+		names := []string{}
+		for name := range ms.Inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			is := ms.Inputs[name]
+			var value interface{}
+			if is.IsConnection() {
+				source, ok := modules[is.Module]
+				if !ok {
+					warn("%s.%s: source %s is not part of the rack", path, name, is.Module)
+					continue
+				}
+				value = module.Port{Patcher: source, Port: is.Output}
+			} else {
+				v, err := is.Constant()
+				if err != nil {
+					warn("%s.%s: %s", path, name, err)
+					continue
+				}
+				value = v
+			}
+			if err := p.Patch(name, value); err != nil {
+				warn("%s.%s: %s", path, name, err)
+			}
+		}
+	}
+	return warnings
+}
+
+// rackPatchers maps the path => module table given from the Lua layer to the underlying Patchers
+func rackPatchers(state *lua.LState, t *lua.LTable) map[string]module.Patcher {
+	modules := map[string]module.Patcher{}
+	t.ForEach(func(k, v lua.LValue) {
+		data, ok := v.(*lua.LUserData)
+		if !ok {
+			state.RaiseError("expected userdata for module %s; got %s", k, v.Type())
+		}
+		p, ok := data.Value.(module.Patcher)
+		if !ok {
+			state.RaiseError("expected module.Patcher for module %s; got %T", k, data.Value)
+		}
+		modules[k.String()] = p
+	})
+	return modules
+}
+
+func stringList(state *lua.LState, strs []string) *lua.LTable {
+	t := state.NewTable()
+	for _, s := range strs {
+		t.Append(lua.LString(s))
+	}
+	return t
+}
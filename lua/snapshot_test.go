@@ -0,0 +1,89 @@
+package lua
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+	assert "gopkg.in/go-playground/assert.v1"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-snapshot")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	vm := newVM(t)
+	defer vm.Close()
+
+	vm.SetGlobal("snapshotPath", lua.LString(filepath.Join(dir, "snapshot.json")))
+
+	err = vm.DoString(`
+		local synth = require('eolian.synth')
+
+		local osc   = synth.Oscillator()
+		local mix   = synth.Mix { size = 2 }
+		Rack.modules = {
+			osc   = osc,
+			mixer = { voices = { mix } },
+		}
+
+		osc:set { pitch = pitch('C4'), detune = hz(2) }
+		mix:set { master = 0.5, { input = osc:out('sine') } }
+
+		local before = { osc = osc:inputs(), mix = mix:inputs() }
+		Rack.snapshot(snapshotPath)
+
+		osc:set { pitch = hz(100), detune = 0 }
+		mix:set { master = 1, { input = 0 } }
+		assert(mix:inputs()['0/input'] ~= before.mix['0/input'])
+
+		local f = io.open(snapshotPath)
+		assert(f:read('*a'):find('"mixer.voices[1]"', 1, true))
+		f:close()
+
+		Rack.restore(snapshotPath)
+
+		for name, source in pairs(before.osc) do
+			assert(osc:inputs()[name] == source, name)
+		end
+		for name, source in pairs(before.mix) do
+			assert(mix:inputs()[name] == source, name)
+		end
+	`)
+	assert.Equal(t, err, nil)
+}
+
+func TestSnapshotMorph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-snapshot")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	vm := newVM(t)
+	defer vm.Close()
+
+	vm.SetGlobal("snapshotA", lua.LString(filepath.Join(dir, "a.json")))
+	vm.SetGlobal("snapshotB", lua.LString(filepath.Join(dir, "b.json")))
+
+	err = vm.DoString(`
+		local synth = require('eolian.synth')
+
+		local osc = synth.Oscillator()
+		Rack.modules = { osc = osc }
+
+		osc:set { pitch = pitch('A3'), amp = 0.5 }
+		Rack.snapshot(snapshotA)
+		osc:set { pitch = pitch('A5'), amp = 1 }
+		Rack.snapshot(snapshotB)
+
+		local morph = Rack.morph { snapshotA, snapshotB }
+		morph:set { position = 0.5 }
+
+		local inputs = osc:inputs()
+		assert(string.find(inputs.amp, morph:id(), 1, true) == 1, inputs.amp)
+		assert(string.find(inputs.pitch, morph:id(), 1, true) == 1, inputs.pitch)
+	`)
+	assert.Equal(t, err, nil)
+}
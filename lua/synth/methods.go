@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/yuin/gluamapper"
 	lua "github.com/yuin/gopher-lua"
 
+	"buddin.us/eolian/dsp"
 	"buddin.us/eolian/module"
 )
 
@@ -29,7 +29,7 @@ func getPatcher(state *lua.LState, t *lua.LTable) (module.Patcher, error) {
 	return nil, fmt.Errorf("expected userdata at %s; got %T", patcherKey, v)
 }
 
-func patcherID(_ sync.Locker) func(*lua.LState) int {
+func patcherID(_ module.Executor) func(*lua.LState) int {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -41,19 +41,19 @@ func patcherID(_ sync.Locker) func(*lua.LState) int {
 	}
 }
 
-func patcherType(_ sync.Locker) lua.LGFunction {
+func patcherType(_ module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
 		if err != nil {
 			state.RaiseError(err.Error())
 		}
-		state.Push(lua.LString(p.ID()))
+		state.Push(lua.LString(p.Type()))
 		return 1
 	}
 }
 
-func patcherInputs(mtx sync.Locker) lua.LGFunction {
+func patcherInputs(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -62,21 +62,27 @@ func patcherInputs(mtx sync.Locker) lua.LGFunction {
 		}
 
 		namespace := strings.Join(getNamespace(state, self), "/")
-		t := state.NewTable()
-		mtx.Lock()
-		for k, v := range p.Inputs() {
-			if !strings.HasPrefix(k, namespace) {
-				continue
+		sources := map[string]string{}
+		exec.Exec(func() error {
+			for k, v := range p.Inputs() {
+				if !strings.HasPrefix(k, namespace) {
+					continue
+				}
+				sources[k] = v.SourceName()
 			}
-			t.RawSet(lua.LString(k), lua.LString(v.SourceName()))
+			return nil
+		})
+
+		t := state.NewTable()
+		for k, v := range sources {
+			t.RawSet(lua.LString(k), lua.LString(v))
 		}
-		mtx.Unlock()
 		state.Push(t)
 		return 1
 	}
 }
 
-func patcherOutputs(mtx sync.Locker) lua.LGFunction {
+func patcherOutputs(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -85,25 +91,31 @@ func patcherOutputs(mtx sync.Locker) lua.LGFunction {
 		}
 
 		namespace := strings.Join(getNamespace(state, self), "/")
-		t := state.NewTable()
-		mtx.Lock()
-		for k, v := range p.Outputs() {
-			if !strings.HasPrefix(k, namespace) {
-				continue
+		destinations := map[string][]string{}
+		exec.Exec(func() error {
+			for k, v := range p.Outputs() {
+				if !strings.HasPrefix(k, namespace) {
+					continue
+				}
+				destinations[k] = v.DestinationNames()
 			}
+			return nil
+		})
+
+		t := state.NewTable()
+		for k, names := range destinations {
 			dests := state.NewTable()
-			for i, name := range v.DestinationNames() {
+			for i, name := range names {
 				dests.Insert(i+1, lua.LString(name))
 			}
 			t.RawSet(lua.LString(k), dests)
 		}
-		mtx.Unlock()
 		state.Push(t)
 		return 1
 	}
 }
 
-func patcherExtendNamespace(mtx sync.Locker) lua.LGFunction {
+func patcherExtendNamespace(_ module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 
@@ -131,7 +143,7 @@ type stateExposer interface {
 	LuaState() map[string]interface{}
 }
 
-func patcherState(mtx sync.Locker) lua.LGFunction {
+func patcherState(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -141,11 +153,16 @@ func patcherState(mtx sync.Locker) lua.LGFunction {
 
 		t := state.NewTable()
 		if l, ok := p.(stateExposer); ok {
-			mtx.Lock()
-			for k, v := range l.LuaState() {
-				t.RawSet(lua.LString(k), lua.LString(fmt.Sprintf("%v", v)))
+			values := map[string]string{}
+			exec.Exec(func() error {
+				for k, v := range l.LuaState() {
+					values[k] = fmt.Sprintf("%v", v)
+				}
+				return nil
+			})
+			for k, v := range values {
+				t.RawSet(lua.LString(k), lua.LString(v))
 			}
-			mtx.Unlock()
 		}
 		state.Push(t)
 		return 1
@@ -156,7 +173,7 @@ type memberExposer interface {
 	LuaMembers() []string
 }
 
-func patcherMembers(mtx sync.Locker) lua.LGFunction {
+func patcherMembers(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -166,18 +183,21 @@ func patcherMembers(mtx sync.Locker) lua.LGFunction {
 
 		t := state.NewTable()
 		if l, ok := p.(memberExposer); ok {
-			mtx.Lock()
-			for i, v := range l.LuaMembers() {
+			var members []string
+			exec.Exec(func() error {
+				members = l.LuaMembers()
+				return nil
+			})
+			for i, v := range members {
 				t.Insert(i+1, lua.LString(v))
 			}
-			mtx.Unlock()
 		}
 		state.Push(t)
 		return 1
 	}
 }
 
-func patcherSet(mtx sync.Locker) lua.LGFunction {
+func patcherSet(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -206,10 +226,115 @@ func patcherSet(mtx sync.Locker) lua.LGFunction {
 		}
 
 		namespace := getNamespace(state, self)
+		patchState := state.GetField(self, patchStateKey).(*lua.LTable)
+
+		// Mark these inputs as being touched
+		patches := flattenInputs(namespace, inputs)
+		names := make([]string, len(patches))
+		for i, patch := range patches {
+			names[i] = patch.name
+			patchState.RawSetString(patch.name, lua.LBool(true))
+		}
 
-		mtx.Lock()
-		err = setInputs(state, self, p, namespace, inputs)
-		mtx.Unlock()
+		err = execPatch(exec, checkInputs(p, names), func() error {
+			for _, patch := range patches {
+				if err := p.Patch(patch.name, patch.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			state.RaiseError(err.Error())
+		}
+
+		state.Push(self)
+		return 1
+	}
+}
+
+// patchExecutor is an Executor that can delay patches, such as those made by scheduled Lua tasks, to a later time. It
+// runs check right away when it delays fn.
+type patchExecutor interface {
+	ExecPatch(check, fn func() error) error
+}
+
+// execPatch runs a change to the module graph, which is delayed along with the other changes of a scheduled task
+func execPatch(exec module.Executor, check, fn func() error) error {
+	if p, ok := exec.(patchExecutor); ok {
+		return p.ExecPatch(check, fn)
+	}
+	return exec.Exec(fn)
+}
+
+// checkInputs returns a check that the module has all the named inputs
+func checkInputs(p module.Patcher, names []string) func() error {
+	return func() error {
+		inputs := p.Inputs()
+		for _, name := range names {
+			if _, ok := inputs[strings.Replace(name, ".", "/", -1)]; !ok {
+				return fmt.Errorf(`unknown input "%s"`, name)
+			}
+		}
+		return nil
+	}
+}
+
+// patcherFade sets the crossfade length of inputs when they're repatched. Like the -fade flag, plain numbers are in
+// milliseconds; values with a unit, e.g. ms(20), are converted from theirs.
+func patcherFade(exec module.Executor) lua.LGFunction {
+	return func(state *lua.LState) int {
+		self := state.CheckTable(1)
+		p, err := getPatcher(state, self)
+		if err != nil {
+			state.RaiseError(err.Error())
+		}
+
+		raw := map[string]lua.LValue{}
+		switch state.GetTop() {
+		case 2:
+			state.CheckTable(2).ForEach(func(k, v lua.LValue) {
+				raw[k.String()] = v
+			})
+		case 3:
+			raw[state.CheckString(2)] = state.CheckAny(3)
+		default:
+			state.RaiseError("invalid number of arguments")
+		}
+
+		namespace := getNamespace(state, self)
+		lengths := map[string]int{}
+		for k, v := range raw {
+			name := strings.Join(append(namespace, k), "/")
+			switch length := v.(type) {
+			case *lua.LUserData:
+				valuer, ok := length.Value.(dsp.Valuer)
+				if !ok {
+					state.RaiseError("unconvertible userdata assigned: %T", length.Value)
+				}
+				lengths[name] = int(valuer.Value())
+			case lua.LNumber:
+				lengths[name] = int(dsp.Duration(float64(length)).Value())
+			default:
+				state.RaiseError("expected milliseconds or value for fade length, but got %s", v.Type())
+			}
+		}
+
+		names := []string{}
+		for name := range lengths {
+			names = append(names, name)
+		}
+		err = execPatch(exec, checkInputs(p, names), func() error {
+			inputs := p.Inputs()
+			for name, length := range lengths {
+				in, ok := inputs[name]
+				if !ok {
+					return fmt.Errorf(`unknown input "%s"`, name)
+				}
+				in.Fade = length
+			}
+			return nil
+		})
 		if err != nil {
 			state.RaiseError(err.Error())
 		}
@@ -232,41 +357,35 @@ func getNamespace(state *lua.LState, table *lua.LTable) []string {
 	return segs
 }
 
-func setInputs(state *lua.LState, self *lua.LTable, p module.Patcher, namespace []string, inputs map[interface{}]interface{}) error {
+// inputPatch is a value to patch into an input
+type inputPatch struct {
+	name  string
+	value interface{}
+}
+
+// flattenInputs lists the values of a possibly nested table of inputs by their full names
+func flattenInputs(namespace []string, inputs map[interface{}]interface{}) []inputPatch {
+	patches := []inputPatch{}
 	for key, raw := range inputs {
-		full := append(namespace, key.(string))
+		full := append(append([]string{}, namespace...), key.(string))
 		if inputs, ok := raw.(map[interface{}]interface{}); ok {
-			if err := setInputs(state, self, p, full, inputs); err != nil {
-				return err
-			}
+			patches = append(patches, flattenInputs(full, inputs)...)
 			continue
 		}
-		name := strings.Join(full, "/")
-
-		// Mark this input as being touched
-		patchState := state.GetField(self, patchStateKey).(*lua.LTable)
-		patchState.RawSetString(name, lua.LBool(true))
-		state.SetField(self, patchStateKey, patchState)
 
+		patch := inputPatch{name: strings.Join(full, "/"), value: raw}
 		switch v := raw.(type) {
 		case *lua.LUserData:
-			if err := p.Patch(name, v.Value); err != nil {
-				return err
-			}
+			patch.value = v.Value
 		case lua.LNumber:
-			if err := p.Patch(name, float64(v)); err != nil {
-				return err
-			}
-		default:
-			if err := p.Patch(name, raw); err != nil {
-				return err
-			}
+			patch.value = float64(v)
 		}
+		patches = append(patches, patch)
 	}
-	return nil
+	return patches
 }
 
-func patcherClose(mtx sync.Locker) lua.LGFunction {
+func patcherClose(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -274,9 +393,10 @@ func patcherClose(mtx sync.Locker) lua.LGFunction {
 			state.RaiseError(err.Error())
 		}
 
-		mtx.Lock()
-		err = p.Close()
-		mtx.Unlock()
+		// Inputs may still be fading away from the module, in which case it's closed once they're done
+		err = execPatch(exec, nil, func() error {
+			return module.CloseAfterFades(p)
+		})
 		if err != nil {
 			state.RaiseError(err.Error())
 		}
@@ -284,7 +404,7 @@ func patcherClose(mtx sync.Locker) lua.LGFunction {
 	}
 }
 
-func patcherResetOnly(mtx sync.Locker) lua.LGFunction {
+func patcherResetOnly(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -292,15 +412,15 @@ func patcherResetOnly(mtx sync.Locker) lua.LGFunction {
 			state.RaiseError(err.Error())
 		}
 
-		names := state.CheckTable(1)
+		names := state.CheckTable(2)
 		inputs := []string{}
 		names.ForEach(func(k, v lua.LValue) {
 			inputs = append(inputs, v.String())
 		})
 
-		mtx.Lock()
-		err = p.ResetOnly(inputs)
-		mtx.Unlock()
+		err = execPatch(exec, checkInputs(p, inputs), func() error {
+			return p.ResetOnly(inputs)
+		})
 		if err != nil {
 			state.RaiseError("%s", err.Error())
 		}
@@ -308,7 +428,7 @@ func patcherResetOnly(mtx sync.Locker) lua.LGFunction {
 	}
 }
 
-func patcherReset(mtx sync.Locker) lua.LGFunction {
+func patcherReset(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -316,17 +436,14 @@ func patcherReset(mtx sync.Locker) lua.LGFunction {
 			state.RaiseError(err.Error())
 		}
 
-		mtx.Lock()
-		err = p.Reset()
-		mtx.Unlock()
-		if err != nil {
+		if err := execPatch(exec, nil, p.Reset); err != nil {
 			state.RaiseError(err.Error())
 		}
 		return 0
 	}
 }
 
-func patcherOutput(_ sync.Locker) lua.LGFunction {
+func patcherOutput(_ module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -358,7 +475,7 @@ func patcherOutput(_ sync.Locker) lua.LGFunction {
 	}
 }
 
-func patcherStartPatch(_ sync.Locker) lua.LGFunction {
+func patcherStartPatch(_ module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		state.SetField(self, patchStateKey, state.NewTable())
@@ -366,7 +483,7 @@ func patcherStartPatch(_ sync.Locker) lua.LGFunction {
 	}
 }
 
-func patcherFinishPatch(mtx sync.Locker) lua.LGFunction {
+func patcherFinishPatch(exec module.Executor) lua.LGFunction {
 	return func(state *lua.LState) int {
 		self := state.CheckTable(1)
 		p, err := getPatcher(state, self)
@@ -376,37 +493,28 @@ func patcherFinishPatch(mtx sync.Locker) lua.LGFunction {
 
 		exclusions := state.OptTable(2, state.NewTable())
 
+		keep := map[string]bool{}
 		patchState := state.GetField(self, patchStateKey).(*lua.LTable)
-		mtx.Lock()
-		inputs := p.Inputs()
-		mtx.Unlock()
-
-		for _, in := range inputs {
-			var found bool
-			patchState.ForEach(func(k, _ lua.LValue) {
-				if in.Name == k.String() {
-					found = true
-					return
-				}
-			})
-			if !found {
-				var excluded bool
-				exclusions.ForEach(func(_, v lua.LValue) {
-					if in.Name == v.String() {
-						excluded = true
-					}
-				})
-				if excluded {
+		patchState.ForEach(func(k, _ lua.LValue) {
+			keep[k.String()] = true
+		})
+		exclusions.ForEach(func(_, v lua.LValue) {
+			keep[v.String()] = true
+		})
+
+		err = execPatch(exec, nil, func() error {
+			for _, in := range p.Inputs() {
+				if keep[in.Name] {
 					continue
 				}
-
-				mtx.Lock()
-				err := in.Close()
-				mtx.Unlock()
-				if err != nil {
-					state.RaiseError(err.Error())
+				if err := in.Reset(); err != nil {
+					return err
 				}
 			}
+			return nil
+		})
+		if err != nil {
+			state.RaiseError(err.Error())
 		}
 		return 0
 	}
@@ -416,7 +524,7 @@ type methodExposer interface {
 	LuaMethods() map[string]module.LuaMethod
 }
 
-func exposedMethods(p module.Patcher, mtx sync.Locker) map[string]lua.LGFunction {
+func exposedMethods(p module.Patcher, exec module.Executor) map[string]lua.LGFunction {
 	var (
 		luaMethods = map[string]lua.LGFunction{}
 		methods    map[string]module.LuaMethod
@@ -434,11 +542,10 @@ func exposedMethods(p module.Patcher, mtx sync.Locker) map[string]lua.LGFunction
 		case func(string) error:
 			func(k string, lock bool, fn func(string) error) {
 				luaMethods[k] = func(state *lua.LState) int {
-					if lock {
-						mtx.Lock()
-						defer mtx.Unlock()
-					}
-					err := fn(state.CheckString(2))
+					arg := state.CheckString(2)
+					err := call(exec, lock, func() error {
+						return fn(arg)
+					})
 					if err != nil {
 						state.RaiseError(err.Error())
 					}
@@ -448,11 +555,12 @@ func exposedMethods(p module.Patcher, mtx sync.Locker) map[string]lua.LGFunction
 		case func() (string, error):
 			func(k string, lock bool, fn func() (string, error)) {
 				luaMethods[k] = func(state *lua.LState) int {
-					if lock {
-						mtx.Lock()
-						defer mtx.Unlock()
-					}
-					r, err := fn()
+					var r string
+					err := call(exec, lock, func() error {
+						var err error
+						r, err = fn()
+						return err
+					})
 					if err != nil {
 						state.RaiseError(err.Error())
 					}
@@ -460,14 +568,28 @@ func exposedMethods(p module.Patcher, mtx sync.Locker) map[string]lua.LGFunction
 					return 1
 				}
 			}(k, v.Lock, fn)
+		case func(interface{}) error:
+			func(k string, lock bool, fn func(interface{}) error) {
+				luaMethods[k] = func(state *lua.LState) int {
+					arg := fromLuaValue(state.CheckAny(2))
+					err := call(exec, lock, func() error {
+						return fn(arg)
+					})
+					if err != nil {
+						state.RaiseError(err.Error())
+					}
+					return 0
+				}
+			}(k, v.Lock, fn)
 		case func() (float64, error):
 			func(k string, lock bool, fn func() (float64, error)) {
 				luaMethods[k] = func(state *lua.LState) int {
-					if lock {
-						mtx.Lock()
-						defer mtx.Unlock()
-					}
-					r, err := fn()
+					var r float64
+					err := call(exec, lock, func() error {
+						var err error
+						r, err = fn()
+						return err
+					})
 					if err != nil {
 						state.RaiseError(err.Error())
 					}
@@ -475,7 +597,71 @@ func exposedMethods(p module.Patcher, mtx sync.Locker) map[string]lua.LGFunction
 					return 1
 				}
 			}(k, v.Lock, fn)
+		case func() (map[string]interface{}, error):
+			func(k string, lock bool, fn func() (map[string]interface{}, error)) {
+				luaMethods[k] = func(state *lua.LState) int {
+					var r map[string]interface{}
+					err := call(exec, lock, func() error {
+						var err error
+						r, err = fn()
+						return err
+					})
+					if err != nil {
+						state.RaiseError(err.Error())
+					}
+					state.Push(toLuaValue(state, r))
+					return 1
+				}
+			}(k, v.Lock, fn)
 		}
 	}
 	return luaMethods
 }
+
+// toLuaValue converts the values returned by LuaMethods to their Lua equivalent
+func toLuaValue(state *lua.LState, v interface{}) lua.LValue {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		t := state.NewTable()
+		for k, v := range v {
+			t.RawSetString(k, toLuaValue(state, v))
+		}
+		return t
+	case []interface{}:
+		t := state.NewTable()
+		for _, v := range v {
+			t.Append(toLuaValue(state, v))
+		}
+		return t
+	case string:
+		return lua.LString(v)
+	case bool:
+		return lua.LBool(v)
+	case int:
+		return lua.LNumber(v)
+	case float64:
+		return lua.LNumber(v)
+	case dsp.Float64:
+		return lua.LNumber(v)
+	case nil:
+		return lua.LNil
+	default:
+		return lua.LString(fmt.Sprintf("%v", v))
+	}
+}
+
+// fromLuaValue converts an argument for LuaMethods that accept any value. Userdata is passed as the Go value it holds.
+func fromLuaValue(v lua.LValue) interface{} {
+	if data, ok := v.(*lua.LUserData); ok {
+		return data.Value
+	}
+	return gluamapper.ToGoValue(v, mapperOpts)
+}
+
+// call runs fn through the Executor when the method requires exclusive access to the module graph
+func call(exec module.Executor, lock bool, fn func() error) error {
+	if lock {
+		return exec.Exec(fn)
+	}
+	return fn()
+}
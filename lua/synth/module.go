@@ -1,7 +1,7 @@
 package synth
 
 import (
-	"sync"
+	"fmt"
 
 	"buddin.us/eolian/dsp"
 	"buddin.us/eolian/module"
@@ -13,9 +13,10 @@ const (
 	patchStateKey = "__patchstate"
 	namespaceKey  = "__namespace"
 	patcherKey    = "__patcher"
+	signatureKey  = "__signature"
 )
 
-func constructor(name string, mtx sync.Locker) func(state *lua.LState) int {
+func constructor(name string, exec module.Executor, tracker *Tracker) func(state *lua.LState) int {
 	return func(state *lua.LState) int {
 		config := getConfig(state)
 		init, err := module.Lookup(name)
@@ -26,7 +27,14 @@ func constructor(name string, mtx sync.Locker) func(state *lua.LState) int {
 		if err != nil {
 			state.RaiseError("%s", err.Error())
 		}
-		state.Push(CreateModule(state, p, mtx))
+		table := CreateModule(state, p, exec)
+
+		// The signature identifies modules built the same way, so a rebuilt rack can keep the instances it already has.
+		// Maps are formatted with sorted keys.
+		state.RawSet(table, lua.LString(signatureKey), lua.LString(fmt.Sprintf("%s %v", name, config)))
+		tracker.add(table)
+
+		state.Push(table)
 		return 1
 	}
 }
@@ -55,7 +63,7 @@ func getConfig(state *lua.LState) module.Config {
 	return config
 }
 
-func CreateModule(state *lua.LState, p module.Patcher, mtx sync.Locker) *lua.LTable {
+func CreateModule(state *lua.LState, p module.Patcher, exec module.Executor) *lua.LTable {
 	data := state.NewUserData()
 	data.Value = p
 
@@ -63,30 +71,31 @@ func CreateModule(state *lua.LState, p module.Patcher, mtx sync.Locker) *lua.LTa
 	state.RawSet(table, lua.LString(patchStateKey), state.NewTable())
 	state.RawSet(table, lua.LString(namespaceKey), state.NewTable())
 	state.RawSet(table, lua.LString(patcherKey), data)
-	state.SetFuncs(table, moduleMethods(p, mtx))
+	state.SetFuncs(table, moduleMethods(p, exec))
 
 	return table
 }
 
-func moduleMethods(p module.Patcher, mtx sync.Locker) map[string]lua.LGFunction {
+func moduleMethods(p module.Patcher, exec module.Executor) map[string]lua.LGFunction {
 	fns := map[string]lua.LGFunction{
-		"close":       patcherClose(mtx),
-		"finishPatch": patcherFinishPatch(mtx),
-		"id":          patcherID(mtx),
-		"inputs":      patcherInputs(mtx),
-		"ns":          patcherExtendNamespace(mtx),
-		"members":     patcherMembers(mtx),
-		"out":         patcherOutput(mtx),
-		"outputs":     patcherOutputs(mtx),
-		"reset":       patcherReset(mtx),
-		"resetOnly":   patcherResetOnly(mtx),
-		"set":         patcherSet(mtx),
-		"startPatch":  patcherStartPatch(mtx),
-		"state":       patcherState(mtx),
-		"type":        patcherType(mtx),
+		"close":       patcherClose(exec),
+		"fade":        patcherFade(exec),
+		"finishPatch": patcherFinishPatch(exec),
+		"id":          patcherID(exec),
+		"inputs":      patcherInputs(exec),
+		"ns":          patcherExtendNamespace(exec),
+		"members":     patcherMembers(exec),
+		"out":         patcherOutput(exec),
+		"outputs":     patcherOutputs(exec),
+		"reset":       patcherReset(exec),
+		"resetOnly":   patcherResetOnly(exec),
+		"set":         patcherSet(exec),
+		"startPatch":  patcherStartPatch(exec),
+		"state":       patcherState(exec),
+		"type":        patcherType(exec),
 	}
 
-	for k, v := range exposedMethods(p, mtx) {
+	for k, v := range exposedMethods(p, exec) {
 		if _, ok := fns[k]; ok {
 			continue
 		}
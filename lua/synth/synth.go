@@ -1,17 +1,15 @@
 package synth
 
 import (
-	"sync"
-
 	"buddin.us/eolian/module"
 	lua "github.com/yuin/gopher-lua"
 )
 
-func Preload(mtx sync.Locker) lua.LGFunction {
+func Preload(exec module.Executor, tracker *Tracker) lua.LGFunction {
 	return func(state *lua.LState) int {
 		fns := map[string]lua.LGFunction{}
 		for _, name := range module.RegisteredTypes() {
-			fns[name] = constructor(name, mtx)
+			fns[name] = constructor(name, exec, tracker)
 		}
 		mod := state.NewTable()
 		for k, v := range constants {
@@ -22,3 +20,41 @@ func Preload(mtx sync.Locker) lua.LGFunction {
 		return 1
 	}
 }
+
+// Tracker records the modules created from Lua while it's started, so that a rack that fails to build partway through
+// can close the modules it already created
+type Tracker struct {
+	started bool
+	created []*lua.LTable
+}
+
+func (t *Tracker) add(table *lua.LTable) {
+	if t != nil && t.started {
+		t.created = append(t.created, table)
+	}
+}
+
+// PreloadTracker exposes a Tracker to Lua: start() begins recording and stop() ends it, returning the modules created
+// in the meantime
+func PreloadTracker(t *Tracker) lua.LGFunction {
+	return func(state *lua.LState) int {
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			"start": func(state *lua.LState) int {
+				t.started, t.created = true, nil
+				return 0
+			},
+			"stop": func(state *lua.LState) int {
+				created := state.NewTable()
+				for _, table := range t.created {
+					created.Append(table)
+				}
+				t.started, t.created = false, nil
+				state.Push(created)
+				return 1
+			},
+		})
+		state.Push(mod)
+		return 1
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	assert "gopkg.in/go-playground/assert.v1"
+
+	"buddin.us/eolian/dsp"
 )
 
 func TestSynth(t *testing.T) {
@@ -32,3 +34,50 @@ func TestSynth(t *testing.T) {
 	`)
 	assert.Equal(t, err, nil)
 }
+
+func TestFade(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+
+	err := vm.DoString(`
+		local synth = require('eolian.synth')
+		a = synth.Mix { size = 1 }
+		a:fade('master', 10)
+		a:ns(0):fade { level = ms(10) }
+	`)
+	assert.Equal(t, err, nil)
+
+	// Plain numbers are milliseconds, like the -fade flag
+	p, ok := patcherOf(vm.GetGlobal("a"))
+	assert.Equal(t, ok, true)
+	inputs := p.Inputs()
+	assert.Equal(t, inputs["master"].Fade, int(dsp.Duration(10).Value()))
+	assert.Equal(t, inputs["0/level"].Fade, int(dsp.Duration(10).Value()))
+}
+
+func TestHelp(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+
+	err := vm.DoString(`
+		local synth = require('eolian.synth')
+		help(synth.Filter)
+		help(synth.Filter())
+		help('Filter')
+	`)
+	assert.Equal(t, err, nil)
+}
+
+func TestMeter(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+
+	err := vm.DoString(`
+		local synth = require('eolian.synth')
+		local meter = synth.Meter()
+		local levels = meter:read()
+		assert(levels.peak == 0)
+		assert(levels.clips == 0)
+	`)
+	assert.Equal(t, err, nil)
+}
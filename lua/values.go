@@ -2,77 +2,483 @@ package lua
 
 import (
 	"fmt"
+	"math"
 
 	lua "github.com/yuin/gopher-lua"
 
 	"buddin.us/eolian/dsp"
+	"buddin.us/musictheory"
 )
 
+// valueTypeName is the registry key of the metatable shared by every value. Lua only compares userdata that share
+// their comparison metamethods.
+const valueTypeName = "eolian.value"
+
 func preloadValue(state *lua.LState) int {
 	mod := state.NewTable()
 	state.SetFuncs(mod, map[string]lua.LGFunction{
-		"bpm":   bpm,
-		"hz":    hz,
-		"ms":    ms,
-		"pitch": pitch,
+		"bpm":       newValue(bpmUnit),
+		"cents":     newValue(centsUnit),
+		"db":        newValue(dbUnit),
+		"hz":        newValue(hzUnit),
+		"ms":        newValue(msUnit),
+		"pitch":     pitch,
+		"ratio":     newValue(ratioUnit),
+		"semitones": newValue(semitonesUnit),
+		"voct":      newValue(voctUnit),
 	})
 	state.Push(mod)
 	return 1
 }
 
-func hz(state *lua.LState) int {
-	value := state.ToNumber(1)
-	hz := dsp.Frequency(float64(value))
+// Kinds of units. Values can only be converted to, combined with or compared to values of a kind they share.
+const (
+	kindFrequency = 1 << iota
+	kindInterval
+	kindGain
+)
 
-	methods := state.NewTable()
-	state.SetFuncs(methods, valuerMethods)
-	mt := state.NewTable()
-	mt.RawSetString("__index", methods)
+// unit describes how values are converted to and from the base of their kind: hertz for frequencies, periods and
+// tempos, and a ratio for intervals and gains. The raw value of a pitch is its frequency.
+type unit struct {
+	name             string
+	kinds            int
+	toBase, fromBase func(float64) float64
+	make             func(float64) dsp.Valuer
+	// check rejects raw values that don't describe a value of the unit
+	check func(float64) error
+}
 
-	state.Push(&lua.LUserData{Value: hz, Metatable: mt})
-	return 1
+// value makes a value of the unit from a raw value
+func (u *unit) value(raw float64) (dsp.Valuer, error) {
+	if u.check != nil {
+		if err := u.check(raw); err != nil {
+			return nil, err
+		}
+	}
+	return u.make(raw), nil
 }
 
-func bpm(state *lua.LState) int {
-	value := state.ToNumber(1)
-	bpm := dsp.BPM(float64(value))
+func (u *unit) shares(o *unit) bool {
+	return u.kinds&o.kinds != 0
+}
 
-	methods := state.NewTable()
-	state.SetFuncs(methods, valuerMethods)
-	mt := state.NewTable()
-	mt.RawSetString("__index", methods)
+func (u *unit) convert(raw float64, to *unit) float64 {
+	return to.fromBase(u.toBase(raw))
+}
 
-	state.Push(&lua.LUserData{Value: bpm, Metatable: mt})
-	return 1
+func identity(v float64) float64 { return v }
+
+var (
+	hzUnit = &unit{
+		name:     "hz",
+		kinds:    kindFrequency,
+		toBase:   identity,
+		fromBase: identity,
+		make:     func(v float64) dsp.Valuer { return dsp.Frequency(v) },
+	}
+	pitchUnit = &unit{
+		name:     "pitch",
+		kinds:    kindFrequency,
+		toBase:   identity,
+		fromBase: identity,
+		make:     nearestPitch,
+		check: func(v float64) error {
+			if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+				return fmt.Errorf("no pitch has a frequency of %gHz", v)
+			}
+			return nil
+		},
+	}
+	voctUnit = &unit{
+		name:     "voct",
+		kinds:    kindFrequency,
+		toBase:   func(v float64) float64 { return dsp.MiddleC * math.Exp2(v) },
+		fromBase: func(v float64) float64 { return math.Log2(v / dsp.MiddleC) },
+		make:     func(v float64) dsp.Valuer { return dsp.Volts(v) },
+		// Frequencies that aren't positive have no voct, which comes out of the conversion as -Inf or NaN
+		check: func(v float64) error {
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				return fmt.Errorf("voct needs a positive, finite frequency")
+			}
+			return nil
+		},
+	}
+	bpmUnit = &unit{
+		name:     "bpm",
+		kinds:    kindFrequency,
+		toBase:   func(v float64) float64 { return v / 60 },
+		fromBase: func(v float64) float64 { return v * 60 },
+		make:     func(v float64) dsp.Valuer { return dsp.BPM(v) },
+	}
+	msUnit = &unit{
+		name:     "ms",
+		kinds:    kindFrequency,
+		toBase:   func(v float64) float64 { return 1000 / v },
+		fromBase: func(v float64) float64 { return 1000 / v },
+		make:     func(v float64) dsp.Valuer { return dsp.Duration(v) },
+	}
+	semitonesUnit = &unit{
+		name:     "semitones",
+		kinds:    kindInterval,
+		toBase:   func(v float64) float64 { return math.Exp2(v / 12) },
+		fromBase: func(v float64) float64 { return 12 * math.Log2(v) },
+		make:     func(v float64) dsp.Valuer { return dsp.Semitones(v) },
+	}
+	centsUnit = &unit{
+		name:     "cents",
+		kinds:    kindInterval,
+		toBase:   func(v float64) float64 { return math.Exp2(v / 1200) },
+		fromBase: func(v float64) float64 { return 1200 * math.Log2(v) },
+		make:     func(v float64) dsp.Valuer { return dsp.Cents(v) },
+	}
+	ratioUnit = &unit{
+		name:     "ratio",
+		kinds:    kindInterval | kindGain,
+		toBase:   identity,
+		fromBase: identity,
+		make:     func(v float64) dsp.Valuer { return dsp.Times(v) },
+	}
+	dbUnit = &unit{
+		name:     "db",
+		kinds:    kindGain,
+		toBase:   func(v float64) float64 { return math.Pow(10, v/20) },
+		fromBase: func(v float64) float64 { return 20 * math.Log10(v) },
+		make:     func(v float64) dsp.Valuer { return dsp.Gain(v) },
+	}
+)
+
+func nearestPitch(hz float64) dsp.Valuer {
+	p := musictheory.NearestPitch(hz)
+	return dsp.Pitch{Raw: p.Name(musictheory.AscNames), Valuer: dsp.Frequency(p.Freq())}
+}
+
+// unitOf returns the unit and raw value of a value. Pitches and intervals from eolian.theory are understood too.
+func unitOf(v interface{}) (*unit, float64, bool) {
+	switch v := v.(type) {
+	case dsp.Hz:
+		return hzUnit, v.Raw, true
+	case dsp.Pitch:
+		return pitchUnit, float64(v.Value()) * dsp.SampleRate, true
+	case musictheory.Pitch:
+		return pitchUnit, v.Freq(), true
+	case dsp.VOct:
+		return voctUnit, v.Raw, true
+	case dsp.BeatsPerMin:
+		return bpmUnit, v.Raw, true
+	case dsp.MS:
+		return msUnit, v.Raw, true
+	case dsp.Semis:
+		return semitonesUnit, v.Raw, true
+	case musictheory.Interval:
+		return semitonesUnit, float64(v.Semitones()), true
+	case dsp.Cent:
+		return centsUnit, v.Raw, true
+	case dsp.Ratio:
+		return ratioUnit, v.Raw, true
+	case dsp.DB:
+		return dbUnit, v.Raw, true
+	}
+	return nil, 0, false
+}
+
+// arithmeticUnit is the unit of the result of arithmetic on a value. Only transposition keeps a pitch a pitch.
+func arithmeticUnit(u *unit) *unit {
+	if u == pitchUnit {
+		return hzUnit
+	}
+	return u
+}
+
+func newValue(u *unit) lua.LGFunction {
+	return func(state *lua.LState) int {
+		v, err := u.value(float64(state.CheckNumber(1)))
+		if err != nil {
+			state.RaiseError("%s", err.Error())
+		}
+		state.Push(newValueUserData(state, v))
+		return 1
+	}
 }
 
 func pitch(state *lua.LState) int {
-	value := state.ToString(1)
+	value := state.CheckString(1)
 	pitch, err := dsp.ParsePitch(value)
 	if err != nil {
 		state.RaiseError("%s", err.Error())
 	}
+	state.Push(newValueUserData(state, pitch))
+	return 1
+}
+
+func newValueUserData(state *lua.LState, v dsp.Valuer) *lua.LUserData {
+	return &lua.LUserData{Value: v, Metatable: valueMetatable(state)}
+}
+
+func valueMetatable(state *lua.LState) *lua.LTable {
+	if mt, ok := state.GetTypeMetatable(valueTypeName).(*lua.LTable); ok {
+		return mt
+	}
+	mt := state.NewTypeMetatable(valueTypeName)
 
 	methods := state.NewTable()
 	state.SetFuncs(methods, valuerMethods)
-	mt := state.NewTable()
+	for _, u := range []*unit{hzUnit, pitchUnit, voctUnit, bpmUnit, msUnit, semitonesUnit, centsUnit, ratioUnit, dbUnit} {
+		methods.RawSetString(u.name, state.NewFunction(convertTo(u)))
+	}
 	mt.RawSetString("__index", methods)
 
-	state.Push(&lua.LUserData{Value: pitch, Metatable: mt})
-	return 1
+	state.SetFuncs(mt, map[string]lua.LGFunction{
+		"__add": arithmetic(func(a, b lua.LValue) (interface{}, error) { return addValues(a, b, false) }),
+		"__sub": arithmetic(func(a, b lua.LValue) (interface{}, error) { return addValues(a, b, true) }),
+		"__mul": arithmetic(func(a, b lua.LValue) (interface{}, error) { return mulValues(a, b, false) }),
+		"__div": arithmetic(func(a, b lua.LValue) (interface{}, error) { return mulValues(a, b, true) }),
+		"__unm": func(state *lua.LState) int {
+			u, raw, _ := unitOf(state.CheckUserData(1).Value)
+			if u == pitchUnit {
+				state.RaiseError("can't negate a pitch")
+			}
+			v, err := u.value(-raw)
+			if err != nil {
+				state.RaiseError("%s", err.Error())
+			}
+			state.Push(newValueUserData(state, v))
+			return 1
+		},
+		"__eq": func(state *lua.LState) int {
+			a, b, err := compareValues(state.Get(1), state.Get(2))
+			state.Push(lua.LBool(err == nil && a == b))
+			return 1
+		},
+		"__lt": func(state *lua.LState) int {
+			a, b, err := compareValues(state.Get(1), state.Get(2))
+			if err != nil {
+				state.RaiseError("%s", err.Error())
+			}
+			state.Push(lua.LBool(a < b))
+			return 1
+		},
+		"__le": func(state *lua.LState) int {
+			a, b, err := compareValues(state.Get(1), state.Get(2))
+			if err != nil {
+				state.RaiseError("%s", err.Error())
+			}
+			state.Push(lua.LBool(a <= b))
+			return 1
+		},
+		"__tostring": func(state *lua.LState) int {
+			state.Push(lua.LString(fmt.Sprint(state.CheckUserData(1).Value)))
+			return 1
+		},
+	})
+	return mt
 }
 
-func ms(state *lua.LState) int {
-	value := state.ToNumber(1)
-	ms := dsp.Duration(float64(value))
+// operand is either side of an arithmetic operation on values: a value with a unit or a plain number
+type operand struct {
+	value  interface{}
+	unit   *unit
+	raw    float64
+	number bool
+}
 
-	methods := state.NewTable()
-	state.SetFuncs(methods, valuerMethods)
-	mt := state.NewTable()
-	mt.RawSetString("__index", methods)
+func toOperand(v lua.LValue) (operand, error) {
+	switch v := v.(type) {
+	case lua.LNumber:
+		return operand{raw: float64(v), number: true}, nil
+	case *lua.LUserData:
+		if u, raw, ok := unitOf(v.Value); ok {
+			return operand{value: v.Value, unit: u, raw: raw}, nil
+		}
+		return operand{}, fmt.Errorf("%T isn't a value", v.Value)
+	}
+	return operand{}, fmt.Errorf("%s isn't a value", v.Type())
+}
 
-	state.Push(&lua.LUserData{Value: ms, Metatable: mt})
-	return 1
+func operands(a, b lua.LValue) (operand, operand, error) {
+	x, err := toOperand(a)
+	if err != nil {
+		return x, x, err
+	}
+	y, err := toOperand(b)
+	return x, y, err
+}
+
+// arithmetic returns a metamethod for an operator. The result of the operation is either a value or a plain number.
+func arithmetic(op func(a, b lua.LValue) (interface{}, error)) lua.LGFunction {
+	return func(state *lua.LState) int {
+		v, err := op(state.Get(1), state.Get(2))
+		if err != nil {
+			state.RaiseError("%s", err.Error())
+		}
+		if n, ok := v.(float64); ok {
+			state.Push(lua.LNumber(n))
+		} else {
+			state.Push(newValueUserData(state, v.(dsp.Valuer)))
+		}
+		return 1
+	}
+}
+
+// addValues adds or subtracts two values. Values of the same unit add, plain numbers are taken to be in the unit of
+// the other side, intervals transpose frequencies and intervals of different units are stacked.
+func addValues(a, b lua.LValue, subtract bool) (interface{}, error) {
+	x, y, err := operands(a, b)
+	if err != nil {
+		return nil, err
+	}
+	op := "add"
+	if subtract {
+		op = "subtract"
+	}
+
+	if y.unit != nil && y.unit.kinds&kindInterval != 0 && x.unit != nil && x.unit.kinds&kindFrequency != 0 {
+		return transpose(x, y, subtract)
+	}
+	if x.unit != nil && x.unit.kinds&kindInterval != 0 && y.unit != nil && y.unit.kinds&kindFrequency != 0 &&
+		!subtract {
+		return transpose(y, x, false)
+	}
+
+	sign := 1.0
+	if subtract {
+		sign = -1
+	}
+	switch {
+	case x.unit == nil && y.unit == nil:
+		return x.raw + sign*y.raw, nil
+	case y.number && x.unit != pitchUnit:
+		return x.unit.value(x.raw + sign*y.raw)
+	case x.number && y.unit != pitchUnit:
+		return y.unit.value(x.raw + sign*y.raw)
+	case x.unit == y.unit && x.unit != pitchUnit:
+		return x.unit.value(x.raw + sign*y.raw)
+	case x.unit != nil && y.unit != nil && x.unit.kinds&y.unit.kinds&(kindInterval|kindGain) != 0:
+		stacked := x.unit.toBase(x.raw) * math.Pow(y.unit.toBase(y.raw), sign)
+		return x.unit.value(x.unit.fromBase(stacked))
+	}
+	return nil, fmt.Errorf("can't %s %s and %s", op, describeOperand(x), describeOperand(y))
+}
+
+// transpose moves a frequency by an interval. Pitches stay pitches when the interval is a whole number of semitones.
+func transpose(f, interval operand, down bool) (interface{}, error) {
+	if f.unit == pitchUnit {
+		if p, ok := toTheoryPitch(f.value); ok {
+			if i, ok := toTheoryInterval(interval); ok {
+				if down {
+					i = i.Negate()
+				}
+				t := p.Transpose(i).(musictheory.Pitch)
+				return dsp.Pitch{Raw: t.Name(musictheory.AscNames), Valuer: dsp.Frequency(t.Freq())}, nil
+			}
+		}
+	}
+	ratio := interval.unit.toBase(interval.raw)
+	if down {
+		ratio = 1 / ratio
+	}
+	u := arithmeticUnit(f.unit)
+	return u.value(u.fromBase(f.unit.toBase(f.raw) * ratio))
+}
+
+func toTheoryPitch(v interface{}) (musictheory.Pitch, bool) {
+	switch v := v.(type) {
+	case musictheory.Pitch:
+		return v, true
+	case dsp.Pitch:
+		if p, err := musictheory.ParsePitch(v.Raw); err == nil {
+			return *p, true
+		}
+	}
+	return musictheory.Pitch{}, false
+}
+
+func toTheoryInterval(o operand) (musictheory.Interval, bool) {
+	if i, ok := o.value.(musictheory.Interval); ok {
+		return i, true
+	}
+	if o.unit == semitonesUnit && o.raw == math.Trunc(o.raw) {
+		return musictheory.Semitones(int(o.raw)), true
+	}
+	return musictheory.Interval{}, false
+}
+
+// mulValues multiplies or divides two values. Numbers scale a value, ratios and other intervals transpose frequencies
+// and dividing two values of the same kind gives the plain ratio between them.
+func mulValues(a, b lua.LValue, divide bool) (interface{}, error) {
+	x, y, err := operands(a, b)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case x.unit == nil && y.unit == nil:
+		if divide {
+			return x.raw / y.raw, nil
+		}
+		return x.raw * y.raw, nil
+	case y.number:
+		u := arithmeticUnit(x.unit)
+		if divide {
+			return u.value(x.raw / y.raw)
+		}
+		return u.value(x.raw * y.raw)
+	case x.number && !divide:
+		u := arithmeticUnit(y.unit)
+		return u.value(x.raw * y.raw)
+	case x.number:
+	case x.unit.kinds&kindFrequency != 0 && y.unit.kinds&kindInterval != 0:
+		return transpose(x, operand{unit: ratioUnit, raw: y.unit.toBase(y.raw)}, divide)
+	case !divide && y.unit.kinds&kindFrequency != 0 && x.unit.kinds&kindInterval != 0:
+		return transpose(y, operand{unit: ratioUnit, raw: x.unit.toBase(x.raw)}, false)
+	case divide && x.unit.shares(y.unit):
+		return x.raw / y.unit.convert(y.raw, x.unit), nil
+	}
+	op := "multiply"
+	if divide {
+		op = "divide"
+	}
+	return nil, fmt.Errorf("can't %s %s and %s", op, describeOperand(x), describeOperand(y))
+}
+
+// compareValues returns both sides of a comparison in the unit of the left side
+func compareValues(a, b lua.LValue) (float64, float64, error) {
+	x, y, err := operands(a, b)
+	if err != nil {
+		return 0, 0, err
+	}
+	switch {
+	case x.number || y.number:
+		return 0, 0, fmt.Errorf("can't compare %s and %s", describeOperand(x), describeOperand(y))
+	case !x.unit.shares(y.unit):
+		return 0, 0, fmt.Errorf("can't compare %s and %s", x.unit.name, y.unit.name)
+	}
+	return x.raw, y.unit.convert(y.raw, x.unit), nil
+}
+
+func describeOperand(o operand) string {
+	if o.number {
+		return "a number"
+	}
+	return o.unit.name
+}
+
+// convertTo returns a method that converts a value to another unit of its kind
+func convertTo(to *unit) lua.LGFunction {
+	return func(state *lua.LState) int {
+		u, raw, ok := unitOf(state.CheckUserData(1).Value)
+		if !ok {
+			state.ArgError(1, "expected a value")
+		}
+		if !u.shares(to) {
+			state.RaiseError("can't convert %s to %s", u.name, to.name)
+		}
+		v, err := to.value(u.convert(raw, to))
+		if err != nil {
+			state.RaiseError("%s", err.Error())
+		}
+		state.Push(newValueUserData(state, v))
+		return 1
+	}
 }
 
 var valuerMethods = map[string]lua.LGFunction{
@@ -86,4 +492,9 @@ var valuerMethods = map[string]lua.LGFunction{
 		state.Push(lua.LString(pitch.String()))
 		return 1
 	},
+	"number": func(state *lua.LState) int {
+		_, raw, _ := unitOf(state.CheckUserData(1).Value)
+		state.Push(lua.LNumber(raw))
+		return 1
+	},
 }
@@ -33,4 +33,79 @@ func TestValues(t *testing.T) {
 		assert(concertPitch:string() == 'A4')
 	`)
 	assert.Equal(t, err, nil)
+
+	err = vm.DoString(`
+		assert(tostring(voct(1)) == '1.00V')
+		assert(semitones(7):string() == '7.00st')
+		assert(cents(50):string() == '50.00cents')
+		assert(db(-6):string() == '-6.00dB')
+		assert(ratio(1.5):string() == '1.50x')
+	`)
+	assert.Equal(t, err, nil)
+}
+
+func TestValueArithmetic(t *testing.T) {
+	vm := newVM(t)
+	defer vm.Close()
+
+	err := vm.DoString(`
+		local function near(a, b) return math.abs(a - b) < 1e-6 end
+
+		assert((hz(440) * 2):string() == '880.00Hz')
+		assert((2 * hz(440)):string() == '880.00Hz')
+		assert((ms(250) + ms(10)):string() == '260.00ms')
+		assert((ms(250) - 50):string() == '200.00ms')
+		assert((-semitones(3)):string() == '-3.00st')
+		assert(ms(500) / ms(250) == 2)
+		assert(hz(880) / pitch('A4') == 2)
+
+		-- Intervals transpose frequencies; pitches stay pitches for whole semitones
+		local theory = require('eolian.theory')
+		assert((pitch('C4') + theory.major(3)):string() == 'E4')
+		assert((theory.perfect(5) + pitch('C4')):string() == 'G4')
+		assert((pitch('C4') + semitones(12)):string() == 'C5')
+		assert((pitch('E4') - semitones(4)):string() == 'C4')
+		assert(near((hz(440) + semitones(12)):number(), 880))
+		assert(near((hz(440) * ratio(1.5)):number(), 660))
+		assert(near((voct(1) + semitones(12)):number(), 2))
+		assert(near((semitones(1) + cents(50)):number(), 1.5))
+		assert(near((db(-6) + db(-6)):number(), -12))
+
+		-- Conversions between units of the same kind
+		assert(bpm(120):ms():string() == '500.00ms')
+		assert(ms(500):hz():string() == '2.00Hz')
+		assert(hz(2):bpm():string() == '120.00BPM')
+		assert(pitch('C5'):voct():string() == '1.00V')
+		assert(voct(-1):pitch():string() == 'C3')
+		assert(near(hz(445):pitch():number(), pitch('A4'):number()))
+		assert(near(semitones(12):ratio():number(), 2))
+		assert(near(ratio(2):cents():number(), 1200))
+		assert(near(db(20):ratio():number(), 10))
+		assert(near(voct(0):value(), pitch('C4'):value()))
+
+		-- Comparisons happen in the unit of the left side
+		assert(hz(440) == pitch('A4'))
+		assert(hz(440) ~= hz(441))
+		assert(ms(250) < ms(260))
+		assert(ms(250) < bpm(120))
+		assert(pitch('C4') <= pitch('C4'))
+		assert(semitones(1) > cents(50))
+	`)
+	assert.Equal(t, err, nil)
+
+	for _, code := range []string{
+		`return hz(440) + ms(10)`,
+		`return pitch('C4') + pitch('E4')`,
+		`return -pitch('C4')`,
+		`return hz(440) < db(1)`,
+		`return semitones(1) * db(1)`,
+		`return db(1):hz()`,
+		`return hz(0):pitch()`,
+		`return hz(-440):pitch()`,
+		`return (hz(1) / 0):pitch()`,
+		`return hz(0):voct()`,
+		`return bpm(-60):voct()`,
+	} {
+		assert.NotEqual(t, vm.DoString(code), nil)
+	}
 }
@@ -6,7 +6,6 @@ import (
 	"io"
 	"log"
 	"os"
-	"sort"
 	"strings"
 	"sync"
 
@@ -26,49 +25,86 @@ const (
 // VM is the Lua virtual machine
 type VM struct {
 	*lua.LState
+
+	// The Lua state can't be shared between goroutines; the REPL, signal handlers and network clients take turns
+	mtx sync.Mutex
+
+	watcher *watcher
+	sched   *scheduler
+	sandbox *sandbox
 }
 
-// NewVM returns a new lua virtual machine centered around a Patcher
-func NewVM(p module.Patcher, mtx sync.Locker) (*VM, error) {
+// NewVM returns a new lua virtual machine centered around a Patcher. All access to the module graph goes through the
+// Executor.
+func NewVM(p module.Patcher, exec module.Executor) (*VM, error) {
 	state := lua.NewState()
+	watcher := newWatcher()
+
+	// Modules created from Lua patch through the scheduler so changes made by tasks land at the tasks' time
+	sched := newScheduler(exec)
+	sandbox := &sandbox{}
+	tracker := &synth.Tracker{}
 	lua.OpenBase(state)
 	lua.OpenDebug(state)
 	lua.OpenString(state)
 
 	state.PreloadModule("eolian.filepath", preloadFilepath)
+	state.PreloadModule("eolian.help", preloadHelp)
+	state.PreloadModule("eolian.pattern", preloadPattern)
 	state.PreloadModule("eolian.repl", preloadLibFile("lua/lib/repl.lua"))
 	state.PreloadModule("eolian.runtime", preloadRuntime)
+	state.PreloadModule("eolian.sandbox", preloadSandbox(sandbox))
+	state.PreloadModule("eolian.sched", preloadSched(sched))
 	state.PreloadModule("eolian.sort", preloadSort)
 	state.PreloadModule("eolian.string", preloadString)
-	state.PreloadModule("eolian.synth", synth.Preload(mtx))
+	state.PreloadModule("eolian.synth", synth.Preload(sched, tracker))
 	state.PreloadModule("eolian.func", preloadLibFile("lua/lib/func.lua"))
 	state.PreloadModule("eolian.synth.control", preloadLibFile("lua/lib/synth/control.lua"))
 	state.PreloadModule("eolian.synth.proxy", preloadSynthProxy)
+	state.PreloadModule("eolian.synth.tracker", synth.PreloadTracker(tracker))
 	state.PreloadModule("eolian.rack.route", preloadLibFile("lua/lib/rack/route.lua"))
 	state.PreloadModule("eolian.rack.mount", preloadLibFile("lua/lib/rack/mount.lua"))
+	state.PreloadModule("eolian.rack.batch", preloadBatch(sched))
+	state.PreloadModule("eolian.rack.graph", preloadGraph(exec))
+	state.PreloadModule("eolian.rack.profile", preloadProfile(exec))
+	state.PreloadModule("eolian.rack.snapshot", preloadSnapshot(exec))
+	state.PreloadModule("eolian.rack.watch", preloadWatch(watcher))
 	state.PreloadModule("eolian.tabwriter", preloadTabWriter)
 	state.PreloadModule("eolian.theory", theory.Preload)
 	state.PreloadModule("eolian.time", preloadTime)
 	state.PreloadModule("eolian.value", preloadValue)
 
-	state.SetGlobal("Engine", synth.CreateModule(state, p, mtx))
+	state.SetGlobal("Engine", synth.CreateModule(state, p, sched))
 	if err := loadLibFile(state, "lua/lib/rack/rack.lua"); err != nil {
 		return nil, err
 	}
 	if err := state.DoString("repl = require('eolian.repl')"); err != nil {
 		return nil, err
 	}
+	if err := state.DoString("help = repl.help"); err != nil {
+		return nil, err
+	}
 	if err := state.DoString("for k,v in pairs(require('eolian.value')) do _G[k] = v end"); err != nil {
 		return nil, err
 	}
-	return &VM{state}, nil
+	if err := state.DoString("pat = require('eolian.pattern').pat"); err != nil {
+		return nil, err
+	}
+	return &VM{LState: state, watcher: watcher, sched: sched, sandbox: sandbox}, nil
+}
+
+// DoString runs Lua code. Calls are serialized, so it's safe to use from multiple goroutines.
+func (vm *VM) DoString(code string) error {
+	vm.mtx.Lock()
+	defer vm.mtx.Unlock()
+	return vm.LState.DoString(code)
 }
 
 // REPL starts the read-eval-print-loop
 func (vm *VM) REPL() error {
 	fmt.Println("Press Ctrl-D to exit")
 
-	session := newSession(vm.LState)
+	session := newSession(vm.LState, vm.sched)
 
 	history := defaultHistoryFile
 	if env := os.Getenv(historyFileVar); env != "" {
@@ -115,49 +151,3 @@ func (vm *VM) REPL() error {
 	}
 	return nil
 }
-
-func (vm *VM) completion(line [][]rune, pos int) [][]rune {
-	if len(line) > 1 {
-		return [][]rune{}
-	}
-	input := string(line[0])
-
-	if start := strings.LastIndexAny(input, "({["); start > -1 && start != len(input)-1 {
-		input = input[start+1:]
-	}
-	parts := strings.Split(input, ".")
-
-	table := vm.GetGlobal("_G").(*lua.LTable)
-	for i, part := range parts {
-		var found bool
-		table.ForEach(func(k, v lua.LValue) {
-			if part == k.String() {
-				if vt, ok := v.(*lua.LTable); ok {
-					table = vt
-					found = true
-					return
-				}
-			}
-		})
-		if !found && i < len(parts)-1 {
-			return [][]rune{}
-		}
-	}
-
-	candidates := [][]rune{}
-	table.ForEach(func(k, v lua.LValue) {
-		c := k.String()
-		if len(parts) > 1 {
-			last := len(parts) - 1
-			c = strings.Join(append(parts[:last], k.String()), ".")
-			if strings.HasPrefix(parts[last], "__") {
-				return
-			}
-		}
-		candidates = append(candidates, []rune(c))
-	})
-	sort.Slice(candidates, func(i, j int) bool {
-		return string(candidates[i]) < string(candidates[j])
-	})
-	return candidates
-}
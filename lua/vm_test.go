@@ -16,7 +16,7 @@ func newVM(t *testing.T) *VM {
 	direct, err := init(nil)
 	assert.Equal(t, err, nil)
 
-	vm, err := NewVM(direct, &sync.Mutex{})
+	vm, err := NewVM(direct, module.LockExecutor{Locker: &sync.Mutex{}})
 	assert.Equal(t, err, nil)
 
 	err = vm.REPL()
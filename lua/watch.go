@@ -0,0 +1,178 @@
+package lua
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// watcher remembers the files loaded by the rack and when they were last modified
+type watcher struct {
+	mtx   sync.Mutex
+	files map[string]time.Time
+}
+
+func newWatcher() *watcher {
+	return &watcher{files: map[string]time.Time{}}
+}
+
+func (w *watcher) track(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	var modTime time.Time
+	if info, err := os.Stat(abs); err == nil {
+		modTime = info.ModTime()
+	}
+	w.mtx.Lock()
+	w.files[abs] = modTime
+	w.mtx.Unlock()
+}
+
+// changed reports whether any file was modified since it was loaded or last checked. Files that are missing, such as
+// while an editor replaces them, are checked again next time.
+func (w *watcher) changed() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	var changed bool
+	for path, modTime := range w.files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(modTime) {
+			w.files[path] = info.ModTime()
+			changed = true
+		}
+	}
+	return changed
+}
+
+func preloadWatch(w *watcher) lua.LGFunction {
+	return func(state *lua.LState) int {
+		mod := state.NewTable()
+		state.SetFuncs(mod, map[string]lua.LGFunction{
+			"track": func(state *lua.LState) int {
+				w.track(state.CheckString(1))
+				return 0
+			},
+			"resolve":   resolveModule,
+			"signature": functionSignature,
+		})
+		state.Push(mod)
+		return 1
+	}
+}
+
+// resolveModule finds the file require would load for a module name by searching package.path
+func resolveModule(state *lua.LState) int {
+	name := strings.Replace(state.CheckString(1), ".", string(filepath.Separator), -1)
+	path := lua.LVAsString(state.GetField(state.GetGlobal("package"), "path"))
+	for _, pattern := range strings.Split(path, ";") {
+		candidate := strings.Replace(pattern, "?", name, -1)
+		if _, err := os.Stat(candidate); err == nil {
+			state.Push(lua.LString(candidate))
+			return 1
+		}
+	}
+	state.Push(lua.LNil)
+	return 1
+}
+
+// functionSignature describes what a Lua function depends on: its source and, through its upvalues, the source of the
+// functions and the values it can reach, such as local helpers and the tables of required files. Rack.reload compares
+// the signatures of build functions to decide whether a rack needs to be rebuilt.
+func functionSignature(state *lua.LState) int {
+	s := &signer{seen: map[lua.LValue]bool{}}
+	s.value(state.CheckFunction(1))
+	state.Push(lua.LString(s.buf.String()))
+	return 1
+}
+
+type signer struct {
+	seen map[lua.LValue]bool
+	buf  bytes.Buffer
+}
+
+func (s *signer) value(v lua.LValue) {
+	switch v := v.(type) {
+	case *lua.LFunction:
+		if s.seen[v] || v.Proto == nil {
+			return
+		}
+		s.seen[v] = true
+		s.buf.WriteString(functionSource(v))
+		s.buf.WriteByte('\n')
+		for _, uv := range v.Upvalues {
+			if uv != nil {
+				s.value(uv.Value())
+			}
+		}
+	case *lua.LTable:
+		if s.seen[v] {
+			return
+		}
+		s.seen[v] = true
+		fields := map[string]lua.LValue{}
+		keys := []string{}
+		v.ForEach(func(k, v lua.LValue) {
+			key := fmt.Sprintf("%s:%s", k.Type(), k)
+			fields[key] = v
+			keys = append(keys, key)
+		})
+		sort.Strings(keys)
+		for _, key := range keys {
+			s.buf.WriteString(key)
+			s.buf.WriteByte('=')
+			s.value(fields[key])
+			s.buf.WriteByte('\n')
+		}
+	case lua.LNumber, lua.LString, lua.LBool:
+		s.buf.WriteString(v.String())
+	}
+}
+
+// functionSource returns the current text of the lines that define a Lua function
+func functionSource(fn *lua.LFunction) string {
+	data, err := ioutil.ReadFile(strings.TrimPrefix(fn.Proto.SourceName, "@"))
+	if err != nil {
+		return ""
+	}
+	var (
+		lines      = bytes.Split(data, []byte("\n"))
+		start, end = fn.Proto.LineDefined, fn.Proto.LastLineDefined
+	)
+	if start < 1 || end > len(lines) || start > end {
+		return ""
+	}
+	return string(bytes.Join(lines[start-1:end], []byte("\n")))
+}
+
+// Watch reloads the rack with Rack.reload() whenever one of the files it loaded changes. It checks the files at an
+// interval until done is closed.
+func (vm *VM) Watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if vm.watcher.changed() {
+				if err := vm.DoString("Rack.reload()"); err != nil {
+					fmt.Println("error:", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package lua
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+const watchedRack = `return function(_)
+    local synth  = require('eolian.synth')
+    local helper = require('helper')
+    local voices = require('voices')
+
+    local function build()
+        local rack = { %s }
+        for i = 1, voices.count do
+            rack[i] = synth.Direct()
+        end
+        return rack
+    end
+
+    local function patch(rack)
+        rack.osc:set { pitch = helper.pitch }
+    end
+
+    return build, patch
+end`
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-watch")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	var (
+		rack    = filepath.Join(dir, "rack.lua")
+		helper  = filepath.Join(dir, "helper.lua")
+		voices  = filepath.Join(dir, "voices.lua")
+		modTime = time.Now().Add(-time.Hour)
+	)
+	write := func(path, content string) {
+		assert.Equal(t, ioutil.WriteFile(path, []byte(content), 0644), nil)
+		modTime = modTime.Add(time.Second)
+		assert.Equal(t, os.Chtimes(path, modTime, modTime), nil)
+	}
+	write(rack, fmt.Sprintf(watchedRack, "osc = synth.Oscillator()"))
+	write(helper, "return { pitch = 440 }")
+	write(voices, "return { count = 1 }")
+
+	vm := newVM(t)
+	// The engine in tests has no left and right inputs to clear
+	assert.Equal(t, vm.DoString("Rack.clear = function() end"), nil)
+	assert.Equal(t, vm.DoString(fmt.Sprintf("Rack.load('%s')", rack)), nil)
+	assert.Equal(t, vm.watcher.changed(), false)
+
+	id := func() string {
+		output, err := vm.Eval("Rack.modules.osc:id()")
+		assert.Equal(t, err, nil)
+		return output
+	}
+	reload := func() string {
+		assert.Equal(t, vm.watcher.changed(), true)
+		output, err := vm.Eval("Rack.reload()")
+		assert.Equal(t, err, nil)
+		return output
+	}
+	original := id()
+
+	// Changes outside of build only repatch, including changes to required files
+	write(helper, "return { pitch = 220 }")
+	assert.Equal(t, strings.HasPrefix(reload(), "Repatching"), true)
+	assert.Equal(t, id(), original)
+
+	// Changes to what build reaches through its upvalues, like required files, rebuild the rack
+	write(voices, "return { count = 2 }")
+	assert.Equal(t, strings.HasPrefix(reload(), "Rebuilding"), true)
+	assert.Equal(t, id(), original)
+	output, err := vm.Eval("print(Rack.modules[2] ~= nil)")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, output, "true\n")
+
+	// Syntax errors are reported and leave the rack alone
+	write(rack, "return function(")
+	assert.NotEqual(t, reload(), "")
+	assert.Equal(t, id(), original)
+
+	// Changes to build rebuild the rack
+	write(rack, fmt.Sprintf(watchedRack, "osc = synth.Oscillator { multiplier = 2 }"))
+	assert.Equal(t, strings.HasPrefix(reload(), "Rebuilding"), true)
+	assert.NotEqual(t, id(), original)
+}
@@ -8,6 +8,23 @@ import (
 
 func init() {
 	Register("ADSR", func(Config) (Patcher, error) { return newADSR() })
+	Describe("ADSR", Metadata{
+		Description: "Attack-decay-sustain-release envelope generator",
+		Inputs: []PortMetadata{
+			{Name: "gate", Default: 0, Range: &Range{0, 1}, Description: "gate; the envelope sustains while high"},
+			{Name: "attack", Unit: UnitMS, Default: 10, Description: "time to rise to the peak"},
+			{Name: "decay", Unit: UnitMS, Default: 10, Description: "time to fall to the sustain level"},
+			{Name: "release", Unit: UnitMS, Default: 10, Description: "time to fall to zero after the gate ends"},
+			{Name: "ratio", Default: 0.01, Range: &Range{0.0001, 100}, Description: "curvature of the segments; low values are exponential"},
+			{Name: "sustain", Default: 0.1, Range: &Range{0, 1}, Description: "level held while the gate is high"},
+			{Name: "disableSustain", Default: 0, Range: &Range{0, 1}, Description: "skip the sustain stage when high"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "envelope"},
+			{Name: "endcycle", Description: "trigger when the envelope completes"},
+		},
+		ControlRate: true,
+	})
 }
 
 type adsr struct {
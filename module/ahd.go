@@ -8,6 +8,21 @@ import (
 
 func init() {
 	Register("AHD", func(Config) (Patcher, error) { return newAHD() })
+	Describe("AHD", Metadata{
+		Description: "Attack-hold-decay envelope generator",
+		Inputs: []PortMetadata{
+			{Name: "gate", Default: 0, Range: &Range{0, 1}, Description: "gate; a rising edge starts the envelope"},
+			{Name: "attack", Unit: UnitMS, Default: 10, Description: "time to rise to the peak"},
+			{Name: "hold", Unit: UnitMS, Default: 50, Description: "time held at the peak"},
+			{Name: "decay", Unit: UnitMS, Default: 10, Description: "time to fall to zero"},
+			{Name: "ratio", Default: 0.01, Range: &Range{0.0001, 100}, Description: "curvature of the segments; low values are exponential"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "envelope"},
+			{Name: "endcycle", Description: "trigger when the envelope completes"},
+		},
+		ControlRate: true,
+	})
 }
 
 type ahd struct {
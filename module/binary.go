@@ -17,6 +17,25 @@ func init() {
 	Register("AND", func(Config) (Patcher, error) { return newBinary("AND", and, 0, 0) })
 	Register("Max", func(Config) (Patcher, error) { return newBinary("Max", max, 0, 0) })
 	Register("Min", func(Config) (Patcher, error) { return newBinary("Min", min, 0, 0) })
+
+	describeBinary("Multiply", "Multiplies a by b", 0)
+	describeBinary("Divide", "Divides a by b", 1)
+	describeBinary("Sum", "Adds a and b", 0)
+	describeBinary("Difference", "Subtracts b from a", 0)
+	describeBinary("Mod", "Remainder of a divided by b", 1)
+	describeBinary("OR", "Logical OR of two gates: high (1) when either is above zero and low (-1) otherwise", 0)
+	describeBinary("XOR", "Logical XOR of two gates: high (1) when exactly one is above zero and low (-1) otherwise", 0)
+	describeBinary("AND", "Logical AND of two gates: high (1) when both are above zero and low (-1) otherwise", 0)
+	describeBinary("Max", "Larger of a and b", 0)
+	describeBinary("Min", "Smaller of a and b", 0)
+}
+
+func describeBinary(name, description string, b float64) {
+	Describe(name, Metadata{
+		Description: description,
+		Inputs:      []PortMetadata{{Name: "a"}, {Name: "b", Default: b}},
+		Outputs:     []PortMetadata{{Name: "output"}},
+	})
 }
 
 type binary struct {
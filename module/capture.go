@@ -0,0 +1,244 @@
+package module
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/wav"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+func init() {
+	Register("Capture", func(c Config) (Patcher, error) {
+		var config struct {
+			Channels  int
+			Pre, Post float64
+		}
+		if err := mapstructure.Decode(c, &config); err != nil {
+			return nil, err
+		}
+		if config.Channels == 0 {
+			config.Channels = 1
+		}
+		if config.Pre == 0 {
+			config.Pre = 100
+		}
+		if config.Post == 0 {
+			config.Post = 900
+		}
+		return newCapture(config.Channels, int(dsp.Duration(config.Pre).Value()), int(dsp.Duration(config.Post).Value()))
+	})
+	Describe("Capture", Metadata{
+		Description: "Records its inputs around a trigger; write the latest capture with capture:dump('capture.csv') " +
+			"or capture:dump('capture.wav')",
+		Config: []ConfigMetadata{
+			{Name: "channels", Type: "int", Default: "1", Description: "number of signals recorded"},
+			{Name: "pre", Type: "float", Default: "100", Description: "milliseconds recorded before the trigger"},
+			{Name: "post", Type: "float", Default: "900", Description: "milliseconds recorded after the trigger"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "<n>/input", Description: "signal recorded by the channel"},
+			{Name: "trigger", Default: 0, Description: "starts a capture when it rises above zero"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "<n>/output", Description: "the channel's input, unchanged"},
+		},
+	})
+}
+
+type capture struct {
+	multiOutIO
+	trigger     *In
+	ins         []*In
+	outs        []dsp.Frame
+	lastTrigger dsp.Float64
+
+	// Every input is recorded into a ring buffer. Once the post-trigger samples are recorded, the ring is copied into a
+	// recording and handed to the reading side. The audio thread never waits on the reader: it reuses an undumped
+	// recording or a free one, and skips the capture if neither is available.
+	rings      [][]dsp.Float64
+	idx        int
+	pre, post  int
+	remaining  int
+	recordings chan *recording
+	free       chan *recording
+
+	mtx  sync.Mutex
+	last *recording
+}
+
+type recording struct {
+	channels [][]float32
+	pre      int
+}
+
+func newCapture(channels, pre, post int) (*capture, error) {
+	if channels < 1 {
+		return nil, fmt.Errorf("capture needs at least one channel")
+	}
+	if pre < 0 || post < 1 {
+		return nil, fmt.Errorf("capture needs a positive post-trigger length")
+	}
+
+	m := &capture{
+		trigger:    NewInBuffer("trigger", dsp.Float64(0)),
+		pre:        pre,
+		post:       post,
+		recordings: make(chan *recording, 1),
+		free:       make(chan *recording, 2),
+	}
+	for i := 0; i < 2; i++ {
+		r := &recording{channels: make([][]float32, channels), pre: pre}
+		for j := range r.channels {
+			r.channels[j] = make([]float32, pre+post)
+		}
+		m.free <- r
+	}
+
+	var (
+		inputs  = []*In{m.trigger}
+		outputs []*Out
+	)
+	for i := 0; i < channels; i++ {
+		in := NewIn(fmt.Sprintf("%d/input", i), dsp.Float64(0))
+		m.ins = append(m.ins, in)
+		m.outs = append(m.outs, dsp.NewFrame())
+		m.rings = append(m.rings, make([]dsp.Float64, pre+post))
+		inputs = append(inputs, in)
+		outputs = append(outputs, &Out{Name: fmt.Sprintf("%d/output", i), Provider: provideCopyOut(m, &m.outs[i])})
+	}
+	return m, m.Expose("Capture", inputs, outputs)
+}
+
+// LuaMethods exposes methods on the module at the Lua layer
+func (c *capture) LuaMethods() map[string]LuaMethod {
+	return map[string]LuaMethod{
+		"dump": LuaMethod{
+			Func: func(path string) error {
+				return c.Dump(path)
+			},
+		},
+	}
+}
+
+// Dump writes the latest completed capture to a file. Files ending in .wav are written as multichannel WAV; anything
+// else is written as CSV with a time column measured in seconds from the trigger.
+func (c *capture) Dump(path string) error {
+	if err := CheckPath(path); err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	select {
+	case r := <-c.recordings:
+		if c.last != nil {
+			c.free <- c.last
+		}
+		c.last = r
+	default:
+	}
+	if c.last == nil {
+		return fmt.Errorf("nothing captured yet")
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(filepath.Ext(path)) == ".wav" {
+		err = c.last.writeWAV(f)
+	} else {
+		err = c.last.writeCSV(f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (r *recording) writeWAV(f *os.File) error {
+	samples := make([]float32, 0, len(r.channels)*len(r.channels[0]))
+	for i := range r.channels[0] {
+		for _, ch := range r.channels {
+			samples = append(samples, ch[i])
+		}
+	}
+	return wav.WriteInterleaved(f, dsp.SampleRate, len(r.channels), samples)
+}
+
+func (r *recording) writeCSV(f *os.File) error {
+	w := bufio.NewWriter(f)
+	w.WriteString("time")
+	for i := range r.channels {
+		fmt.Fprintf(w, ",%d", i)
+	}
+	w.WriteString("\n")
+	for i := range r.channels[0] {
+		w.WriteString(strconv.FormatFloat(float64(i-r.pre)/dsp.SampleRate, 'f', 6, 64))
+		for _, ch := range r.channels {
+			w.WriteString(",")
+			w.WriteString(strconv.FormatFloat(float64(ch[i]), 'g', -1, 32))
+		}
+		w.WriteString("\n")
+	}
+	return w.Flush()
+}
+
+func (c *capture) Process(out dsp.Frame) {
+	c.incrRead(func() {
+		trigger := c.trigger.ProcessFrame()
+		for i, in := range c.ins {
+			in.Process(c.outs[i])
+		}
+
+		size := c.pre + c.post
+		for i := range out {
+			for j, ring := range c.rings {
+				ring[c.idx] = c.outs[j][i]
+			}
+			c.idx = (c.idx + 1) % size
+
+			if c.remaining > 0 {
+				if c.remaining--; c.remaining == 0 {
+					c.complete()
+				}
+			} else if c.lastTrigger <= 0 && trigger[i] > 0 {
+				c.remaining = c.post - 1
+				if c.remaining == 0 {
+					c.complete()
+				}
+			}
+			c.lastTrigger = trigger[i]
+		}
+	})
+}
+
+// complete copies the ring buffers, oldest sample first, into a recording for the reading side
+func (c *capture) complete() {
+	var r *recording
+	select {
+	case r = <-c.recordings:
+	default:
+		select {
+		case r = <-c.free:
+		default:
+			return
+		}
+	}
+	size := c.pre + c.post
+	for j, ring := range c.rings {
+		for i := 0; i < size; i++ {
+			r.channels[j][i] = float32(ring[(c.idx+i)%size])
+		}
+	}
+	c.recordings <- r
+}
@@ -0,0 +1,77 @@
+package module
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/wav"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+// ramp counts up from zero one sample at a time
+type ramp struct{ v dsp.Float64 }
+
+func (r *ramp) Process(out dsp.Frame) {
+	for i := range out {
+		out[i] = r.v
+		r.v++
+	}
+}
+
+func TestCapture(t *testing.T) {
+	m, err := newCapture(2, 2, 3)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, m.Patch("0/input", &ramp{}), nil)
+	assert.Equal(t, m.Patch("1/input", dsp.Float64(0.5)), nil)
+
+	dir, err := ioutil.TempDir("", "eolian-capture")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+	csv := filepath.Join(dir, "capture.csv")
+
+	out, err := m.Output("0/output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+	assert.Equal(t, frame[10], dsp.Float64(10))
+	assert.NotEqual(t, m.Dump(csv), nil)
+
+	// Rises at sample 10 of the next frame
+	trigger := dsp.NewFrame()
+	for i := 10; i < len(trigger); i++ {
+		trigger[i] = 1
+	}
+	assert.Equal(t, m.Patch("trigger", &frameSource{trigger}), nil)
+	out.Process(frame)
+
+	assert.Equal(t, m.Dump(csv), nil)
+	data, err := ioutil.ReadFile(csv)
+	assert.Equal(t, err, nil)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, len(lines), 6)
+	assert.Equal(t, lines[0], "time,0,1")
+	assert.Equal(t, strings.HasPrefix(lines[3], "0.000000,"), true)
+
+	trig := dsp.FrameSize + 10
+	assert.Equal(t, strings.Split(lines[1], ",")[1], strconv.Itoa(trig-2))
+	assert.Equal(t, strings.Split(lines[3], ",")[1], strconv.Itoa(trig))
+	assert.Equal(t, strings.Split(lines[5], ",")[1], strconv.Itoa(trig+2))
+	assert.Equal(t, strings.Split(lines[5], ",")[2], "0.5")
+
+	// The latest capture can be dumped again
+	path := filepath.Join(dir, "capture.wav")
+	assert.Equal(t, m.Dump(path), nil)
+	w, err := wav.Open(path)
+	assert.Equal(t, err, nil)
+	defer w.Close()
+	assert.Equal(t, w.NumChannels, uint16(2))
+}
+
+type frameSource struct{ frame dsp.Frame }
+
+func (s *frameSource) Process(out dsp.Frame) { copy(out, s.frame) }
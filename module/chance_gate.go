@@ -3,7 +3,25 @@ package module
 import "buddin.us/eolian/dsp"
 
 func init() {
-	Register("ChanceGate", func(Config) (Patcher, error) { return newChanceGate() })
+	Register("ChanceGate", func(c Config) (Patcher, error) {
+		seed, err := seedConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		return newChanceGate(seed)
+	})
+	Describe("ChanceGate", Metadata{
+		Description: "Routes each gate of its input to one of two outputs at random",
+		Config:      []ConfigMetadata{seedMetadata},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "gates to route"},
+			{Name: "bias", Default: 0, Range: &Range{-1, 1}, Description: "chance of choosing a; -1 never, 1 always"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "gates routed to a"},
+			{Name: "b", Description: "gates routed to b"},
+		},
+	})
 }
 
 type chanceGate struct {
@@ -12,9 +30,10 @@ type chanceGate struct {
 	a, b     dsp.Frame
 	flip     bool
 	lastIn   dsp.Float64
+	rand     *randSource
 }
 
-func newChanceGate() (*chanceGate, error) {
+func newChanceGate(seed int64) (*chanceGate, error) {
 	m := &chanceGate{
 		in:   NewIn("input", dsp.Float64(0)),
 		bias: NewInBuffer("bias", dsp.Float64(0)),
@@ -22,10 +41,12 @@ func newChanceGate() (*chanceGate, error) {
 		b:    dsp.NewFrame(),
 	}
 
-	return m, m.Expose("ChanceGate", []*In{m.in, m.bias}, []*Out{
+	err := m.Expose("ChanceGate", []*In{m.in, m.bias}, []*Out{
 		{Name: "a", Provider: provideCopyOut(m, &m.a)},
 		{Name: "b", Provider: provideCopyOut(m, &m.b)},
 	})
+	m.rand = newRand(m.ID(), seed)
+	return m, err
 }
 
 func (c *chanceGate) Process(out dsp.Frame) {
@@ -34,7 +55,7 @@ func (c *chanceGate) Process(out dsp.Frame) {
 		bias := c.bias.ProcessFrame()
 		for i := range out {
 			if c.lastIn < 0 && out[i] > 0 {
-				r := dsp.Rand()
+				r := c.rand.Value()
 				if r < 0.5*(bias[i]+1) {
 					c.flip = true
 				} else {
@@ -4,6 +4,14 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Clip", func(Config) (Patcher, error) { return newClip() })
+	Describe("Clip", Metadata{
+		Description: "Hard clipper limiting its input to a level",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to clip"},
+			{Name: "level", Default: 1, Description: "largest magnitude let through"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type clip struct {
@@ -36,6 +36,48 @@ func init() {
 		}
 		return newClockMultiply(config.Multiplier)
 	})
+	Describe("Clock", Metadata{
+		Description: "Clock emitting pulses at a tempo",
+		Inputs: []PortMetadata{
+			{Name: "tempo", Unit: UnitBPM, Default: 120, Description: "rate of the pulses"},
+			{Name: "pulseWidth", Default: 0.9, Range: &Range{0, 1}, Description: "fraction of each beat the pulse is high"},
+			{Name: "shuffle", Default: 0, Range: &Range{-0.5, 0.5}, Description: "swing applied to the pulses"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "pulses"},
+		},
+	})
+	Describe("ClockDivide", Metadata{
+		Description: "Clock divider passing one pulse out of every few",
+		Config: []ConfigMetadata{
+			{Name: "divisor", Type: "int", Default: "1", Description: "initial value of the divisor input"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "clock to divide"},
+			{Name: "divisor", Default: 1, Description: "pulses of the input per pulse of the output"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "divided clock"}},
+	})
+	Describe("ClockMultiply", Metadata{
+		Description: "Clock multiplier learning the rate of its input and pulsing faster",
+		Config: []ConfigMetadata{
+			{Name: "multiplier", Type: "int", Default: "1", Description: "initial value of the multiplier input"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "clock to multiply"},
+			{Name: "multiplier", Default: 1, Description: "pulses of the output per pulse of the input"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "multiplied clock"}},
+	})
+	Describe("RotatingClockDivide", Metadata{
+		Description: "Divides a clock by 1 through 8 at once, with the divisions rotating between outputs",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "clock to divide"},
+			{Name: "rotate", Default: 0, Description: "trigger shifting the divisions to the next output"},
+			{Name: "reset", Default: 0, Description: "trigger returning the divisions to their outputs"},
+		},
+		Outputs: []PortMetadata{{Name: "<n>", Description: "clock divided by n, before rotation"}},
+	})
 }
 
 type clock struct {
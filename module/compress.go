@@ -8,6 +8,15 @@ import (
 
 func init() {
 	Register("Compress", func(Config) (Patcher, error) { return newCompress() })
+	Describe("Compress", Metadata{
+		Description: "Compressor following the level of its input and turning it down above 1",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to compress"},
+			{Name: "attack", Unit: UnitMS, Default: 10, Description: "time to respond to a rising level"},
+			{Name: "release", Unit: UnitMS, Default: 500, Description: "time to respond to a falling level"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type compress struct {
@@ -8,24 +8,31 @@ import (
 
 func init() {
 	Register("Concurrent", func(Config) (Patcher, error) { return newConcurrent() })
+	Describe("Concurrent", Metadata{
+		Description: "Computes its input on a goroutine of its own, a frame ahead, so it runs in parallel with the rest",
+		Inputs:      []PortMetadata{{Name: "input", Description: "signal to compute ahead"}},
+		Outputs:     []PortMetadata{{Name: "output", Description: "the input, delayed by a frame"}},
+	})
 }
 
+// concurrent reads its input on a goroutine of its own, a frame ahead of the audio thread. The goroutine is started by
+// the first frame processed and halted while the module is patched.
 type concurrent struct {
 	IO
 	in      *In
 	ch      chan dsp.Frame
 	stop    chan struct{}
+	done    chan struct{}
 	running atomic.Value
+	closed  bool
 }
 
 func newConcurrent() (*concurrent, error) {
 	m := &concurrent{
-		in:   NewInBuffer("input", dsp.Float64(0)),
-		ch:   make(chan dsp.Frame),
-		stop: make(chan struct{}),
+		in: NewInBuffer("input", dsp.Float64(0)),
+		ch: make(chan dsp.Frame),
 	}
-	m.running.Store(true)
-	go m.readInput()
+	m.running.Store(false)
 	return m, m.Expose(
 		"Concurrent",
 		[]*In{m.in},
@@ -34,10 +41,27 @@ func newConcurrent() (*concurrent, error) {
 		})
 }
 
-func (c *concurrent) readInput() {
+func (c *concurrent) start() {
+	c.stop, c.done = make(chan struct{}), make(chan struct{})
+	c.running.Store(true)
+	go c.readInput(c.stop, c.done)
+}
+
+// halt stops reading the input ahead of time and waits for the goroutine doing so to return
+func (c *concurrent) halt() {
+	if !c.running.Load().(bool) {
+		return
+	}
+	close(c.stop)
+	<-c.done
+	c.running.Store(false)
+}
+
+func (c *concurrent) readInput(stop, done chan struct{}) {
+	defer close(done)
 	for {
 		select {
-		case <-c.stop:
+		case <-stop:
 			return
 		case c.ch <- c.in.ProcessFrame():
 		}
@@ -45,6 +69,15 @@ func (c *concurrent) readInput() {
 }
 
 func (c *concurrent) Process(out dsp.Frame) {
+	// The profiler's state belongs to the audio thread, so the input is read synchronously while profiling
+	if profiler.enabled {
+		c.halt()
+		copy(out, c.in.ProcessFrame())
+		return
+	}
+	if !c.running.Load().(bool) && !c.closed {
+		c.start()
+	}
 	frame := <-c.ch
 	for i := range out {
 		out[i] = frame[i]
@@ -52,16 +85,13 @@ func (c *concurrent) Process(out dsp.Frame) {
 }
 
 func (c *concurrent) Patch(name string, t interface{}) error {
-	if !c.running.Load().(bool) {
-		c.stop = make(chan struct{})
-		go c.readInput()
-		c.running.Store(true)
-	}
+	c.halt()
+	c.closed = false
 	return c.IO.Patch(name, t)
 }
 
 func (c *concurrent) Close() error {
-	close(c.stop)
-	c.running.Store(false)
+	c.halt()
+	c.closed = true
 	return nil
 }
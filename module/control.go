@@ -19,6 +19,22 @@ func init() {
 		}
 		return newCtrl(config.Min, config.Max, config.Smooth)
 	})
+	Describe("Control", Metadata{
+		Description: "Scales a control value into a range",
+		Config: []ConfigMetadata{
+			{Name: "min", Type: "float", Default: "0", Description: "initial lower bound"},
+			{Name: "max", Type: "float", Default: "1", Description: "initial upper bound"},
+			{Name: "smooth", Type: "bool", Default: "false", Description: "smooth changes of the input"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Range: &Range{0, 1}, Description: "control value"},
+			{Name: "mod", Default: 1, Range: &Range{-1, 1}, Description: "multiplies the scaled value"},
+			{Name: "min", Default: 0, Description: "value output for an input of 0"},
+			{Name: "max", Default: 1, Description: "value output for an input of 1"},
+		},
+		Outputs:     []PortMetadata{{Name: "output"}},
+		ControlRate: true,
+	})
 }
 
 type ctrl struct {
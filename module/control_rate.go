@@ -0,0 +1,126 @@
+package module
+
+import (
+	"fmt"
+
+	"buddin.us/eolian/dsp"
+	"github.com/mitchellh/mapstructure"
+)
+
+// DefaultControlRate is the control rate given to modules whose metadata allows it, unless their Config says
+// otherwise. A rate of 1 is audio rate.
+var DefaultControlRate = 1
+
+// A module running at a control rate of n computes one sample for every n. Its inputs are decimated to its rate and
+// its outputs are linearly interpolated back to audio rate. Inputs with a time unit are rescaled to match: a duration of
+// x samples becomes x/n control samples and a frequency of x cycles per sample becomes n*x cycles per control sample.
+
+type controlRater interface {
+	setControlRate(n int) error
+}
+
+// applyControlRate sets the rate of a newly created module from its "controlRate" config key or DefaultControlRate
+func applyControlRate(typ string, p Patcher, c Config) error {
+	var config struct {
+		ControlRate int
+	}
+	if err := mapstructure.Decode(c, &config); err != nil {
+		return err
+	}
+	n := config.ControlRate
+	if n == 0 {
+		if m, ok := metadata[typ]; ok && m.ControlRate {
+			n = DefaultControlRate
+		}
+	}
+	if n <= 1 {
+		return nil
+	}
+	r, ok := p.(controlRater)
+	if !ok {
+		return fmt.Errorf("%s can't run at a control rate", typ)
+	}
+	return r.setControlRate(n)
+}
+
+// ControlRate returns the number of audio samples computed for each sample of the module
+func (io *IO) ControlRate() int {
+	if io.rate == 0 {
+		return 1
+	}
+	return io.rate
+}
+
+// CheckControlRate returns an error if modules can't run at a control rate, which must divide the frame size
+func CheckControlRate(n int) error {
+	if n < 1 || dsp.FrameSize%n != 0 {
+		return fmt.Errorf("control rate %d doesn't divide the frame size %d", n, dsp.FrameSize)
+	}
+	return nil
+}
+
+func (io *IO) setControlRate(n int) error {
+	if err := CheckControlRate(n); err != nil {
+		return err
+	}
+	io.lazyInit()
+	io.rate = n
+
+	m := metadata[io.typ]
+	for _, in := range io.in {
+		in.controlScale = 1
+		switch inputUnit(m, in.Name) {
+		case UnitMS:
+			in.controlScale = 1 / dsp.Float64(n)
+		case UnitHz, UnitBPM, UnitPitch:
+			in.controlScale = dsp.Float64(n)
+		}
+		if in.controlFrame == nil {
+			in.controlFrame, in.audioFrame = dsp.NewFrame(), dsp.NewFrame()
+		}
+	}
+	for _, out := range io.out {
+		if out.controlFrame == nil {
+			out.controlFrame = dsp.NewFrame()
+		}
+	}
+	return nil
+}
+
+func inputUnit(m Metadata, name string) string {
+	generic := describedName(name)
+	for _, p := range m.Inputs {
+		if p.Name == name || p.Name == generic {
+			return p.Unit
+		}
+	}
+	return ""
+}
+
+func (i *In) controlRate() int {
+	if i.owner == nil {
+		return 1
+	}
+	return i.owner.ControlRate()
+}
+
+// decimate keeps the last sample of every control period, scaled for the input's unit
+func (i *In) decimate(audio, control dsp.Frame) dsp.Frame {
+	n := i.controlRate()
+	control = control[:len(audio)/n]
+	for k := range control {
+		control[k] = audio[k*n+n-1] * i.controlScale
+	}
+	return control
+}
+
+// interpolate expands control samples to audio rate, ramping from the previous control sample to each new one
+func (o *Out) interpolate(control, audio dsp.Frame) {
+	n := len(audio) / len(control)
+	for k, v := range control {
+		for m := 0; m < n; m++ {
+			audio[k*n+m] = o.lastControl + (v-o.lastControl)*dsp.Float64(m+1)/dsp.Float64(n)
+		}
+		o.lastControl = v
+	}
+}
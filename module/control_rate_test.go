@@ -0,0 +1,125 @@
+package module
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestControlRateInterpolates(t *testing.T) {
+	init, err := Lookup("Control")
+	assert.Equal(t, err, nil)
+	p, err := init(Config{"controlRate": 4})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.(*ctrl).ControlRate(), 4)
+	assert.Equal(t, p.Patch("input", &ramp{}), nil)
+
+	out, err := p.Output("output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+
+	// The first period ramps up from zero; afterwards the interpolation reproduces the input ramp
+	assert.Equal(t, frame[0], dsp.Float64(0.75))
+	assert.Equal(t, frame[3], dsp.Float64(3))
+	for i := 4; i < len(frame); i++ {
+		assert.Equal(t, frame[i], dsp.Float64(i))
+	}
+}
+
+func TestControlRateScalesUnits(t *testing.T) {
+	init, err := Lookup("Glide")
+	assert.Equal(t, err, nil)
+	p, err := init(Config{"controlRate": 4})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.Patch("rise", dsp.Duration(10)), nil)
+
+	rise := p.(*glide).rise.ProcessFrame()
+	assert.Equal(t, len(rise), dsp.FrameSize/4)
+	assert.Equal(t, rise[0], dsp.Duration(10).Value()/4)
+}
+
+func TestDefaultControlRate(t *testing.T) {
+	defer func(rate int) { DefaultControlRate = rate }(DefaultControlRate)
+	DefaultControlRate = 8
+
+	for _, c := range []struct {
+		name string
+		rate int
+	}{
+		{"Control", 8},
+		{"Filter", 1},
+	} {
+		init, err := Lookup(c.name)
+		assert.Equal(t, err, nil)
+		p, err := init(nil)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, p.(interface{ ControlRate() int }).ControlRate(), c.rate)
+	}
+
+	init, err := Lookup("Control")
+	assert.Equal(t, err, nil)
+	p, err := init(Config{"controlRate": 1})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.(*ctrl).ControlRate(), 1)
+
+	_, err = init(Config{"controlRate": 7})
+	assert.NotEqual(t, err, nil)
+}
+
+// edge is low until a given sample and high from then on
+type edge struct{ at, i int }
+
+func (e *edge) Process(out dsp.Frame) {
+	for i := range out {
+		out[i] = -1
+		if e.i >= e.at {
+			out[i] = 1
+		}
+		e.i++
+	}
+}
+
+func TestControlRateCount(t *testing.T) {
+	init, err := Lookup("Count")
+	assert.Equal(t, err, nil)
+	p, err := init(Config{"controlRate": 4})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.Patch("trigger", &edge{at: 5}), nil)
+
+	out, err := p.Output("output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+
+	// The trigger rises within the second control period, which is decimated to its last sample, so the count steps
+	// there and the output ramps up to it over that period
+	for i, v := range []dsp.Float64{0, 0, 0, 0, 0.25, 0.5, 0.75, 1, 1, 1} {
+		assert.Equal(t, frame[i], v)
+	}
+	assert.Equal(t, frame[len(frame)-1], dsp.Float64(1))
+}
+
+func TestControlRateModules(t *testing.T) {
+	for _, name := range RegisteredTypes() {
+		if m, err := LookupMetadata(name); err != nil || !m.ControlRate {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			init, err := Lookup(name)
+			assert.Equal(t, err, nil)
+			p, err := init(Config{"controlRate": 4})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, p.(interface{ ControlRate() int }).ControlRate(), 4)
+
+			frame := dsp.NewFrame()
+			for name := range p.Outputs() {
+				out, err := p.Output(name)
+				assert.Equal(t, err, nil)
+				out.Process(frame)
+				assert.Equal(t, out.Close(), nil)
+			}
+		})
+	}
+}
@@ -22,6 +22,21 @@ func init() {
 		}
 		return newCount(config.Limit, config.Step)
 	})
+	Describe("Count", Metadata{
+		Description: "Counter stepping on each trigger and wrapping around at a limit",
+		Config: []ConfigMetadata{
+			{Name: "limit", Type: "int", Default: "1024", Description: "initial value of the limit input"},
+			{Name: "step", Type: "int", Default: "1", Description: "initial value of the step input"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "trigger", Default: -1, Description: "trigger advancing the count"},
+			{Name: "reset", Default: -1, Description: "trigger returning the count to zero"},
+			{Name: "limit", Default: 1024, Description: "count at which it wraps around to zero"},
+			{Name: "step", Default: 1, Description: "amount added on each trigger"},
+		},
+		Outputs:     []PortMetadata{{Name: "output", Description: "count"}},
+		ControlRate: true,
+	})
 }
 
 type count struct {
@@ -4,6 +4,15 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Coupler", func(c Config) (Patcher, error) { return newCoupler() })
+	Describe("Coupler", Metadata{
+		Description: "Fades its input out and back in each time it's toggled",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to fade"},
+			{Name: "duration", Unit: UnitMS, Default: 300, Description: "length of the fades"},
+			{Name: "toggle", Default: 0, Description: "trigger starting a fade"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 const (
@@ -4,6 +4,15 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Crossfade", func(Config) (Patcher, error) { return newCrossfade() })
+	Describe("Crossfade", Metadata{
+		Description: "Fades between two signals",
+		Inputs: []PortMetadata{
+			{Name: "a"},
+			{Name: "b"},
+			{Name: "bias", Default: 0, Range: &Range{-1, 1}, Description: "-1 is only a, 1 is only b and 0 is both"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type crossfade struct {
@@ -4,6 +4,18 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Crossfeed", func(Config) (Patcher, error) { return newCrossfeed() })
+	Describe("Crossfeed", Metadata{
+		Description: "Mixes each of two channels into the other",
+		Inputs: []PortMetadata{
+			{Name: "a"},
+			{Name: "b"},
+			{Name: "amount", Default: 0, Range: &Range{0, 1}, Description: "level of each channel in the other"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "a with b mixed in"},
+			{Name: "b", Description: "b with a mixed in"},
+		},
+	})
 }
 
 type crossfeed struct {
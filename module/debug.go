@@ -27,6 +27,15 @@ func init() {
 		}
 		return newDebug(config.Output, config.RateDivisor)
 	})
+	Describe("Debug", Metadata{
+		Description: "Prints samples of its input as it passes through",
+		Config: []ConfigMetadata{
+			{Name: "rateDivisor", Type: "int", Default: "10", Description: "samples printed per second"},
+			{Name: "output", Type: "io.Writer", Default: "stdout", Description: "where samples are printed; only settable from Go"},
+		},
+		Inputs:  []PortMetadata{{Name: "input", Description: "signal to print"}},
+		Outputs: []PortMetadata{{Name: "output", Description: "the input, unchanged"}},
+	})
 }
 
 type debug struct {
@@ -6,6 +6,15 @@ import (
 
 func init() {
 	Register("Decimate", func(Config) (Patcher, error) { return newDecimate() })
+	Describe("Decimate", Metadata{
+		Description: "Bitcrusher reducing the sample rate and resolution of its input",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to decimate"},
+			{Name: "rate", Default: 44100, Description: "sample rate to reduce to"},
+			{Name: "bits", Default: 24, Range: &Range{1, 24}, Description: "resolution to reduce to"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type decimate struct {
@@ -33,6 +33,60 @@ func init() {
 		}
 		return newDelay(dsp.DurationInt(config.Size))
 	})
+
+	var (
+		size     = ConfigMetadata{Name: "size", Type: "int", Default: "10000", Description: "maximum delay in milliseconds"}
+		input    = PortMetadata{Name: "input", Description: "signal to delay"}
+		duration = PortMetadata{Name: "duration", Unit: UnitMS, Default: 1000, Description: "delay time; limited by the size"}
+		output   = PortMetadata{Name: "output", Description: "delayed signal"}
+	)
+	Describe("Delay", Metadata{
+		Description: "Delay line without feedback",
+		Config:      []ConfigMetadata{size},
+		Inputs:      []PortMetadata{input, duration},
+		Outputs:     []PortMetadata{output},
+	})
+	Describe("FBDelay", Metadata{
+		Description: "Delay line feeding its output back into itself",
+		Config:      []ConfigMetadata{size},
+		Inputs: []PortMetadata{
+			input, duration,
+			{Name: "gain", Default: 0.9, Range: &Range{0, 1}, Description: "level of the feedback"},
+		},
+		Outputs: []PortMetadata{output},
+	})
+	Describe("Allpass", Metadata{
+		Description: "Allpass filter built from a delay line; it smears the phase of its input, as in reverbs",
+		Config:      []ConfigMetadata{size},
+		Inputs: []PortMetadata{
+			input, duration,
+			{Name: "gain", Default: 0.9, Range: &Range{0, 1}, Description: "level of the feedback"},
+		},
+		Outputs: []PortMetadata{output},
+	})
+	Describe("FilteredFBDelay", Metadata{
+		Description: "Delay line feeding its output back into itself through a lowpass filter",
+		Config:      []ConfigMetadata{size},
+		Inputs: []PortMetadata{
+			input, duration,
+			{Name: "gain", Default: 0.98, Range: &Range{0, 1}, Description: "level of the feedback"},
+			{Name: "cutoff", Unit: UnitHz, Default: 1000, Range: &Range{20, 20000}, Description: "cutoff frequency of the feedback's filter"},
+		},
+		Outputs: []PortMetadata{output},
+	})
+	Describe("FBLoopDelay", Metadata{
+		Description: "Delay line whose feedback may be sent through other modules on its way back",
+		Config:      []ConfigMetadata{size},
+		Inputs: []PortMetadata{
+			input, duration,
+			{Name: "gain", Default: 0.98, Range: &Range{0, 1}, Description: "level of the feedback"},
+			{Name: "feedbackReturn", Default: 0, Description: "feedback after processing; used while feedbackSend is patched"},
+		},
+		Outputs: []PortMetadata{
+			output,
+			{Name: "feedbackSend", Description: "delayed signal to process and patch back into feedbackReturn"},
+		},
+	})
 }
 
 type delay struct {
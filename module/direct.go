@@ -4,6 +4,11 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Direct", func(Config) (Patcher, error) { return newDirect() })
+	Describe("Direct", Metadata{
+		Description: "Passes its input through unchanged",
+		Inputs:      []PortMetadata{{Name: "input"}},
+		Outputs:     []PortMetadata{{Name: "output"}},
+	})
 }
 
 type direct struct {
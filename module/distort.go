@@ -8,6 +8,16 @@ import (
 
 func init() {
 	Register("Distort", func(Config) (Patcher, error) { return newDistort() })
+	Describe("Distort", Metadata{
+		Description: "Asymmetric tanh-like waveshaping distortion",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to distort"},
+			{Name: "gain", Default: 1, Description: "amount of distortion"},
+			{Name: "offsetA", Default: 0, Description: "extra gain of the positive half"},
+			{Name: "offsetB", Default: 0, Description: "extra gain of the negative half"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type distort struct {
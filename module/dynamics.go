@@ -6,6 +6,19 @@ import (
 
 func init() {
 	Register("Dynamics", func(Config) (Patcher, error) { return newDynamics() })
+	Describe("Dynamics", Metadata{
+		Description: "Compressor and expander applying gain to its input by the level of a control signal",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to apply gain to"},
+			{Name: "control", Default: 0, Description: "signal whose level is followed; often the input itself"},
+			{Name: "threshold", Default: 0.5, Description: "level at which the slope changes"},
+			{Name: "slopeAbove", Default: 0.3, Description: "gain ratio above the threshold; below 1 compresses"},
+			{Name: "slopeBelow", Default: 1, Description: "gain ratio below the threshold; above 1 expands"},
+			{Name: "clamp", Unit: UnitMS, Default: 10, Description: "time to follow a rising level"},
+			{Name: "relax", Unit: UnitMS, Default: 10, Description: "time to follow a falling level"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 var (
@@ -4,6 +4,14 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Edges", func(c Config) (Patcher, error) { return newEdges() })
+	Describe("Edges", Metadata{
+		Description: "Emits triggers when its input crosses zero",
+		Inputs:      []PortMetadata{{Name: "input", Description: "signal to watch"}},
+		Outputs: []PortMetadata{
+			{Name: "endrise", Description: "trigger when the input goes above zero"},
+			{Name: "endcycle", Description: "trigger when the input goes below zero"},
+		},
+	})
 }
 
 type edges struct {
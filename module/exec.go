@@ -0,0 +1,45 @@
+package module
+
+import "sync"
+
+// Executor runs functions that read or mutate the module graph. Implementations decide when the function runs relative
+// to audio processing, but Exec must not return until the function has run.
+type Executor interface {
+	Exec(func() error) error
+}
+
+// LockExecutor is an Executor that guards the module graph with a sync.Locker
+type LockExecutor struct {
+	sync.Locker
+}
+
+// Exec runs the function while holding the lock
+func (e LockExecutor) Exec(fn func() error) error {
+	e.Lock()
+	defer e.Unlock()
+	return fn()
+}
+
+// Scheduler is an Executor that can also run functions at a sample time. Time is counted in samples rendered since
+// audio processing started.
+type Scheduler interface {
+	Executor
+
+	// Now returns the time of the frame being rendered, or the next one if none is
+	Now() int64
+
+	// ExecAt queues a function to run at a time without waiting for it. Patches made by the function take effect at
+	// that exact sample; functions queued for a time that has passed run as soon as possible.
+	ExecAt(at int64, fn func() error)
+}
+
+// patchOffset delays patches made through the Executor by a number of samples into the next frame
+var patchOffset int
+
+// PatchAt runs a function that patches inputs, making the patches take effect a number of samples into the next frame
+// rather than at its start. It must be called through the Executor guarding the module graph.
+func PatchAt(offset int, fn func() error) error {
+	patchOffset = offset
+	defer func() { patchOffset = 0 }()
+	return fn()
+}
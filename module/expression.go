@@ -21,6 +21,17 @@ func init() {
 		}
 		return newExpression(config.Expression)
 	})
+	Describe("MathExp", Metadata{
+		Description: "Evaluates a math expression on each sample, e.g. \"input * (x + 1)\"",
+		Config: []ConfigMetadata{
+			{Name: "expression", Type: "string", Description: "expression to evaluate; required"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Default: 0, Description: "signal available to the expression as input"},
+			{Name: "<name>", Default: 0, Description: "an input for each other variable in the expression"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "value of the expression"}},
+	})
 }
 
 type expression struct {
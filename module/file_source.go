@@ -20,6 +20,13 @@ func init() {
 		}
 		return newFileSource(config.Path)
 	})
+	Describe("FileSource", Metadata{
+		Description: "Loops over numbers read from a text file, one per sample",
+		Config: []ConfigMetadata{
+			{Name: "path", Type: "string", Description: "file of whitespace-separated numbers"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type fileSource struct {
@@ -49,6 +56,9 @@ func (f *fileSource) Process(out dsp.Frame) {
 }
 
 func (f *fileSource) loadData(path string) error {
+	if err := CheckPath(path); err != nil {
+		return err
+	}
 	file, err := os.Open(path)
 	if err != nil {
 		return err
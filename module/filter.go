@@ -20,6 +20,22 @@ func init() {
 
 		return newSVFilter(config.Poles)
 	})
+	Describe("Filter", Metadata{
+		Description: "State-variable filter with simultaneous lowpass, highpass and bandpass outputs",
+		Config: []ConfigMetadata{
+			{Name: "poles", Type: "int", Default: "4", Description: "number of poles; steepness of the slope"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to filter"},
+			{Name: "cutoff", Unit: UnitHz, Default: 1000, Range: &Range{20, 20000}, Description: "cutoff frequency"},
+			{Name: "resonance", Default: 1, Range: &Range{1, 100}, Description: "emphasis of frequencies around the cutoff"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "lowpass", Description: "frequencies below the cutoff"},
+			{Name: "highpass", Description: "frequencies above the cutoff"},
+			{Name: "bandpass", Description: "frequencies around the cutoff"},
+		},
+	})
 }
 
 type svFilter struct {
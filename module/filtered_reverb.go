@@ -22,6 +22,22 @@ func init() {
 		}
 		return newFilteredReverb(config.Feedback, config.Allpass)
 	})
+	Describe("FilteredReverb", Metadata{
+		Description: "Freeverb-style reverb with lowpass filters in its feedback and on its output",
+		Config: []ConfigMetadata{
+			{Name: "feedback", Type: "[]int", Default: "1557, 1617, 1491, 1422, 1277, 1356, 1118, 1116", Description: "sizes of the feedback delays in milliseconds"},
+			{Name: "allpass", Type: "[]int", Default: "225, 556, 441, 341", Description: "sizes of the allpass filters in milliseconds"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to reverberate"},
+			{Name: "feedback", Default: 0.84, Range: &Range{0, 1}, Description: "feedback of the delays; length of the tail"},
+			{Name: "fbCutoff", Unit: UnitHz, Default: 1000, Range: &Range{20, 20000}, Description: "cutoff frequency of the feedback's filters"},
+			{Name: "gain", Default: 0.5, Range: &Range{0, 1}, Description: "gain of the allpass filters; density of the tail"},
+			{Name: "cutoff", Unit: UnitHz, Default: 1000, Range: &Range{20, 20000}, Description: "cutoff frequency of the reverberated signal"},
+			{Name: "bias", Default: 0, Range: &Range{-1, 1}, Description: "mix of dry (-1) and wet (1) signal; 0 is both"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type filteredReverb struct {
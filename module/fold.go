@@ -8,6 +8,16 @@ import (
 
 func init() {
 	Register("Fold", func(Config) (Patcher, error) { return newFold() })
+	Describe("Fold", Metadata{
+		Description: "Wavefolder reflecting its input back whenever it goes past a level",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to fold"},
+			{Name: "stages", Default: 1, Description: "times the signal is folded, at successively lower levels"},
+			{Name: "level", Default: 1, Description: "level the signal is folded at"},
+			{Name: "gain", Default: 1, Description: "level of the output"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type fold struct {
@@ -6,6 +6,15 @@ import (
 
 func init() {
 	Register("Follow", func(Config) (Patcher, error) { return newFollow() })
+	Describe("Follow", Metadata{
+		Description: "Envelope follower tracking the level of its input",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to follow"},
+			{Name: "attack", Unit: UnitMS, Default: 10, Description: "time to follow a rising level"},
+			{Name: "release", Unit: UnitMS, Default: 500, Description: "time to follow a falling level"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "envelope"}},
+	})
 }
 
 type follow struct {
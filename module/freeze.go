@@ -6,6 +6,17 @@ import (
 
 func init() {
 	Register("Freeze", func(Config) (Patcher, error) { return newFreeze() })
+	Describe("Freeze", Metadata{
+		Description: "Loops a short stretch of its input while the gate is high, through a bitcrusher",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to freeze"},
+			{Name: "gate", Default: -1, Description: "gate; the input loops while high and passes through while low"},
+			{Name: "size", Unit: UnitMS, Default: 1, Description: "length of the loop"},
+			{Name: "rate", Default: 44100, Description: "sample rate the loop is reduced to"},
+			{Name: "bits", Default: 24, Range: &Range{1, 24}, Description: "resolution the loop is reduced to"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type freeze struct {
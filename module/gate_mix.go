@@ -19,6 +19,14 @@ func init() {
 		}
 		return newGateMix(config.Size)
 	})
+	Describe("GateMix", Metadata{
+		Description: "Combines gates: high (1) while any of its inputs is above zero and low (-1) otherwise",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "4", Description: "number of inputs"},
+		},
+		Inputs:  []PortMetadata{{Name: "<n>", Default: 0, Description: "gate to combine"}},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type gateMix struct {
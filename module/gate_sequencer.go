@@ -21,6 +21,22 @@ func init() {
 		}
 		return newGateSequence(config.Steps)
 	})
+	Describe("GateSequence", Metadata{
+		Description: "Sequencer of on and off beats, advancing a step on each clock pulse",
+		Config: []ConfigMetadata{
+			{Name: "steps", Type: "int", Default: "16", Description: "number of steps"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "clock advancing the sequence"},
+			{Name: "reset", Default: 0, Description: "trigger returning to the first step"},
+			{Name: "<n>/mode", Default: 0, Description: "whether the step is on (above zero) or off"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "on", Description: "gate for the steps that are on"},
+			{Name: "off", Description: "gate for the steps that are off"},
+		},
+		ControlRate: true,
+	})
 }
 
 type gateSequence struct {
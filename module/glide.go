@@ -23,6 +23,20 @@ func init() {
 		}
 		return newGlide(config.Rise, config.Fall)
 	})
+	Describe("Glide", Metadata{
+		Description: "Slews changes of its input over time",
+		Config: []ConfigMetadata{
+			{Name: "rise", Type: "float", Default: "5", Description: "initial rise time in milliseconds"},
+			{Name: "fall", Type: "float", Default: "5", Description: "initial fall time in milliseconds"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to slew"},
+			{Name: "rise", Unit: UnitMS, Default: 5, Description: "time to rise to a higher value"},
+			{Name: "fall", Unit: UnitMS, Default: 5, Description: "time to fall to a lower value"},
+		},
+		Outputs:     []PortMetadata{{Name: "output"}},
+		ControlRate: true,
+	})
 }
 
 type glide struct {
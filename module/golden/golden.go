@@ -0,0 +1,152 @@
+// Package golden provides regression tests for the sound of modules. A module is rendered with deterministic input
+// signals and compared against a fixture rendered earlier. Run the tests with -golden.update to regenerate the
+// fixtures after an intended change in sound.
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+	"buddin.us/eolian/wav"
+)
+
+var update = flag.Bool("golden.update", false, "regenerate golden audio fixtures")
+
+const (
+	// Dir is where fixtures are stored, relative to the package under test
+	Dir = "testdata/golden"
+
+	defaultSamples   = 8192
+	defaultTolerance = 1e-6
+)
+
+// Case describes a module rendering
+type Case struct {
+	// Name of the fixture; stored as Dir/Name.wav
+	Name string
+
+	Module string
+	Config module.Config
+
+	// Inputs are patched into the module before rendering. Values can be anything the module's Patch accepts.
+	Inputs map[string]interface{}
+
+	// Output that's rendered. Defaults to "output".
+	Output string
+
+	// Samples is the number of samples rendered. Defaults to 8192.
+	Samples int
+
+	// Tolerance is the largest difference allowed between a rendered sample and the fixture. Defaults to 1e-6.
+	Tolerance float64
+}
+
+// Render builds the module and renders its output. Module IDs depend on how many modules were created before, so
+// modules that make random decisions should be given a "seed" in their Config to render the same way each time.
+func Render(c Case) ([]float32, error) {
+	init, err := module.Lookup(c.Module)
+	if err != nil {
+		return nil, err
+	}
+	p, err := init(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+
+	for name, v := range c.Inputs {
+		if err := p.Patch(name, v); err != nil {
+			return nil, err
+		}
+	}
+
+	name := c.Output
+	if name == "" {
+		name = "output"
+	}
+	out, err := p.Output(name)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := c.Samples
+	if samples == 0 {
+		samples = defaultSamples
+	}
+	var (
+		result = make([]float32, 0, samples)
+		frame  = dsp.NewFrame()
+	)
+	for len(result) < samples {
+		out.Process(frame)
+		for _, v := range frame {
+			if len(result) == samples {
+				break
+			}
+			result = append(result, float32(v))
+		}
+	}
+	return result, nil
+}
+
+// Run renders the case and compares it against its fixture, failing the test if any sample is outside of the
+// tolerance. With -golden.update the fixture is rewritten instead.
+func Run(t *testing.T, c Case) {
+	rendered, err := Render(c)
+	if err != nil {
+		t.Fatalf("%s: render: %s", c.Name, err)
+	}
+
+	path := filepath.Join(Dir, c.Name+".wav")
+	if *update {
+		if err := os.MkdirAll(Dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := wav.WriteFile(path, dsp.SampleRate, rendered); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := read(path)
+	if err != nil {
+		t.Fatalf("%s: %s (run with -golden.update to create it)", c.Name, err)
+	}
+
+	tolerance := c.Tolerance
+	if tolerance == 0 {
+		tolerance = defaultTolerance
+	}
+	if err := Compare(expected, rendered, tolerance); err != nil {
+		t.Errorf("%s: %s", c.Name, err)
+	}
+}
+
+// Compare returns an error describing the first sample that differs by more than the tolerance
+func Compare(expected, actual []float32, tolerance float64) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d samples; got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if diff := math.Abs(float64(expected[i] - actual[i])); diff > tolerance || math.IsNaN(diff) {
+			return fmt.Errorf("sample %d: expected %v; got %v (difference %v exceeds %v)",
+				i, expected[i], actual[i], diff, tolerance)
+		}
+	}
+	return nil
+}
+
+func read(path string) ([]float32, error) {
+	w, err := wav.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+	return w.ReadAll()
+}
@@ -0,0 +1,31 @@
+package golden
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+
+	_ "buddin.us/eolian/module"
+)
+
+func TestRender(t *testing.T) {
+	c := Case{Module: "Direct", Samples: 1000}
+	c.Inputs = map[string]interface{}{"input": Impulse()}
+	rendered, err := Render(c)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(rendered), 1000)
+	assert.Equal(t, rendered[0], float32(1))
+	assert.Equal(t, rendered[1], float32(0))
+
+	c.Inputs = map[string]interface{}{"input": dsp.Float64(0.5)}
+	c.Output = "unknown"
+	_, err = Render(c)
+	assert.NotEqual(t, err, nil)
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, Compare([]float32{0, 0.5}, []float32{0, 0.5000001}, 1e-6), nil)
+	assert.NotEqual(t, Compare([]float32{0, 0.5}, []float32{0, 0.6}, 1e-6), nil)
+	assert.NotEqual(t, Compare([]float32{0}, []float32{0, 0}, 1e-6), nil)
+}
@@ -0,0 +1,78 @@
+package golden
+
+import (
+	"math"
+	"math/rand"
+
+	"buddin.us/eolian/dsp"
+)
+
+// Deterministic input signals. They hold state, so create a new one for each Case.
+
+// Sine returns a sine wave at a frequency
+func Sine(hz float64) dsp.Processor {
+	return &sine{step: hz / dsp.SampleRate}
+}
+
+type sine struct {
+	phase, step float64
+}
+
+func (s *sine) Process(out dsp.Frame) {
+	for i := range out {
+		out[i] = dsp.Float64(math.Sin(2 * math.Pi * s.phase))
+		s.phase = math.Mod(s.phase+s.step, 1)
+	}
+}
+
+// Impulse returns a single sample of 1 followed by silence
+func Impulse() dsp.Processor {
+	return &impulse{}
+}
+
+type impulse struct {
+	fired bool
+}
+
+func (s *impulse) Process(out dsp.Frame) {
+	for i := range out {
+		out[i] = 0
+		if !s.fired {
+			out[i], s.fired = 1, true
+		}
+	}
+}
+
+// Gate returns a gate signal that's high for half of each period
+func Gate(hz float64) dsp.Processor {
+	return &gate{step: hz / dsp.SampleRate}
+}
+
+type gate struct {
+	phase, step float64
+}
+
+func (s *gate) Process(out dsp.Frame) {
+	for i := range out {
+		out[i] = 0
+		if s.phase < 0.5 {
+			out[i] = 1
+		}
+		s.phase = math.Mod(s.phase+s.step, 1)
+	}
+}
+
+// Noise returns white noise from its own seeded generator
+func Noise(seed int64) dsp.Processor {
+	return &noise{rand.New(rand.NewSource(seed))}
+}
+
+type noise struct {
+	r *rand.Rand
+}
+
+func (s *noise) Process(out dsp.Frame) {
+	for i := range out {
+		out[i] = dsp.Float64(s.r.Float64()*2 - 1)
+	}
+}
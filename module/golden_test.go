@@ -0,0 +1,53 @@
+package module_test
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/module"
+	"buddin.us/eolian/module/golden"
+)
+
+func TestGolden(t *testing.T) {
+	cases := []golden.Case{
+		{
+			Name:   "oscillator_saw",
+			Module: "Oscillator",
+			Output: "saw",
+			Inputs: map[string]interface{}{"pitch": dsp.Frequency(220)},
+		},
+		{
+			Name:   "oscillator_sine_sync",
+			Module: "Oscillator",
+			Output: "sine",
+			Inputs: map[string]interface{}{"pitch": dsp.Frequency(330), "sync": golden.Gate(100)},
+		},
+		{
+			Name:   "filter_lowpass",
+			Module: "Filter",
+			Output: "lowpass",
+			Inputs: map[string]interface{}{
+				"input":     golden.Noise(1),
+				"cutoff":    dsp.Frequency(800),
+				"resonance": dsp.Float64(4),
+			},
+		},
+		{
+			Name:   "filter_bandpass_2pole",
+			Module: "Filter",
+			Config: module.Config{"poles": 2},
+			Output: "bandpass",
+			Inputs: map[string]interface{}{"input": golden.Sine(1000), "cutoff": dsp.Frequency(1000)},
+		},
+		{
+			Name:    "tank_reverb_impulse",
+			Module:  "TankReverb",
+			Output:  "a",
+			Samples: 32768,
+			Inputs:  map[string]interface{}{"a": golden.Impulse()},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) { golden.Run(t, c) })
+	}
+}
@@ -16,6 +16,16 @@ func init() {
 		}
 		return newInterpolate(config)
 	})
+	Describe("Interpolate", Metadata{
+		Description: "Scales an input in the range [0, 1] to the range [min, max]",
+		Config: []ConfigMetadata{
+			{Name: "min", Type: "float", Default: "0", Description: "output when the input is 0"},
+			{Name: "max", Type: "float", Default: "1", Description: "output when the input is 1"},
+			{Name: "smooth", Type: "bool", Default: "false", Description: "smooth the output with a rolling average"},
+		},
+		Inputs:  []PortMetadata{{Name: "input", Default: 0, Range: &Range{0, 1}}},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type interpolateConfig struct {
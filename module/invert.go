@@ -4,6 +4,11 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Invert", func(Config) (Patcher, error) { return newInvert() })
+	Describe("Invert", Metadata{
+		Description: "Flips the sign of its input",
+		Inputs:      []PortMetadata{{Name: "input"}},
+		Outputs:     []PortMetadata{{Name: "output"}},
+	})
 }
 
 type invert struct {
@@ -14,6 +14,10 @@ import (
 
 var moduleSequence uint64
 
+// DefaultFade is the length, in samples, of the crossfade applied when an input is repatched to a new source. It is
+// used by every input that doesn't specify its own Fade. Zero disables crossfading.
+var DefaultFade int
+
 // Patcher is the patching behavior of a module
 type Patcher interface {
 	Identifier
@@ -56,6 +60,12 @@ type IO struct {
 	outLookup map[string]*Out
 
 	forcedActiveOutputs int
+	profile             profile
+	rate                int
+	config              Config
+
+	// pendingClose is the module's Close, held by CloseAfterFades until no input is fading away from its outputs
+	pendingClose func() error
 }
 
 // ID returns the module's unique identifier
@@ -68,6 +78,20 @@ func (io *IO) Type() string {
 	return io.typ
 }
 
+// Config returns the config the module was created with. It's nil for modules that weren't created through the
+// registry.
+func (io *IO) Config() Config {
+	return io.config
+}
+
+type configRecorder interface {
+	recordConfig(Config)
+}
+
+func (io *IO) recordConfig(c Config) {
+	io.config = c
+}
+
 // Expose registers inputs and outputs of the module so that they can be used in patching
 func (io *IO) Expose(typ string, ins []*In, outs []*Out) error {
 	io.typ = typ
@@ -106,6 +130,9 @@ func (io *IO) AddInput(in *In) error {
 	in.owner = io
 	io.in = append(io.in, in)
 	io.inLookup[in.Name] = in
+	if io.rate > 1 {
+		return io.setControlRate(io.rate)
+	}
 	return nil
 }
 
@@ -120,6 +147,9 @@ func (io *IO) AddOutput(out *Out) error {
 	out.owner = io
 	io.out = append(io.out, out)
 	io.outLookup[out.Name] = out
+	if io.rate > 1 {
+		return io.setControlRate(io.rate)
+	}
 	return nil
 }
 
@@ -131,6 +161,9 @@ func (io *IO) Patch(name string, t interface{}) error {
 	if !ok {
 		return fmt.Errorf(`unknown input "%s"`, name)
 	}
+	if input.fadeLength() > 0 || patchOffset > 0 {
+		return input.crossfadeTo(t, patchOffset)
+	}
 	if err := input.Close(); err != nil {
 		return err
 	}
@@ -246,7 +279,7 @@ func (io *IO) lazyInit() {
 // original default value
 func (io *IO) Reset() error {
 	for _, in := range io.in {
-		if err := in.Close(); err != nil {
+		if err := in.Reset(); err != nil {
 			return err
 		}
 	}
@@ -257,7 +290,7 @@ func (io *IO) Reset() error {
 func (io *IO) ResetOnly(names []string) error {
 	for _, n := range names {
 		if in, ok := io.inLookup[n]; ok {
-			if err := in.Close(); err != nil {
+			if err := in.Reset(); err != nil {
 				return err
 			}
 		} else {
@@ -277,9 +310,21 @@ type In struct {
 	Source       dsp.Processor
 	Name         string
 	ForceSinking bool
-	initial      dsp.Processor
-	owner        *IO
-	audioRate    bool
+
+	// Fade is the length, in samples, of the crossfade applied when the input is repatched. Zero falls back to
+	// DefaultFade and a negative value disables crossfading for this input.
+	Fade int
+
+	initial   dsp.Processor
+	owner     *IO
+	audioRate bool
+
+	fadeFrom         dsp.Processor
+	fadeFrame        dsp.Frame
+	fadePos, fadeLen int
+
+	controlScale             dsp.Float64
+	controlFrame, audioFrame dsp.Frame
 }
 
 // NewIn returns a new unbuffered input
@@ -294,7 +339,19 @@ func NewInBuffer(name string, v dsp.Processor) *In {
 
 // Process reads the output of the source into a Frame
 func (i *In) Process(f dsp.Frame) {
+	if i.controlRate() > 1 {
+		i.process(i.audioFrame)
+		i.decimate(i.audioFrame, f)
+		return
+	}
+	i.process(f)
+}
+
+func (i *In) process(f dsp.Frame) {
 	i.Source.Process(f)
+	if i.fading() {
+		i.crossfade(f, f)
+	}
 }
 
 func (i *In) setSource(r dsp.Processor) {
@@ -333,41 +390,219 @@ func (i *In) String() string {
 
 // ProcessFrame reads an entire frame into the buffered input
 func (i *In) ProcessFrame() dsp.Frame {
+	if i.controlRate() > 1 {
+		return i.decimate(i.processFrame(), i.controlFrame)
+	}
+	return i.processFrame()
+}
+
+func (i *In) processFrame() dsp.Frame {
+	if i.fading() {
+		i.crossfade(i.Source.(*dsp.Buffer).ProcessFrame(), i.fadeFrame)
+		return i.fadeFrame
+	}
 	if !i.audioRate {
-		return i.LastFrame()
+		return i.lastFrame()
 	}
 	return i.Source.(*dsp.Buffer).ProcessFrame()
 }
 
 // LastFrame returns the last frame read with ProcessFrame
 func (i *In) LastFrame() dsp.Frame {
+	if n := i.controlRate(); n > 1 {
+		return i.controlFrame[:len(i.controlFrame)/n]
+	}
+	return i.lastFrame()
+}
+
+func (i *In) lastFrame() dsp.Frame {
+	if i.fadeFrom != nil {
+		return i.fadeFrame
+	}
 	return i.Source.(*dsp.Buffer).Frame
 }
 
+func (i *In) fadeLength() int {
+	if i.Fade != 0 {
+		return i.Fade
+	}
+	return DefaultFade
+}
+
+// crossfadeTo patches the input to a new source while continuing to read the previous source for the length of the
+// fade. The fade starts after delay samples. The previous source is only released once the fade has completed.
+func (i *In) crossfadeTo(t interface{}, delay int) error {
+	if err := i.stopFade(); err != nil {
+		return err
+	}
+	prev := i.current()
+	i.setSource(i.initial)
+
+	processor, err := assertProcessor(t)
+	if err != nil {
+		if rerr := i.releaseSource(prev); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+
+	if sameSource(prev, processor) {
+		i.setSource(processor)
+		if _, ok := processor.(*Out); !ok {
+			return i.releaseSource(prev)
+		}
+		return nil
+	}
+
+	i.setSource(processor)
+	if o, ok := processor.(*Out); ok {
+		o.addDestination(i)
+	}
+	i.startFade(prev, delay)
+	return nil
+}
+
+func (i *In) startFade(from dsp.Processor, delay int) {
+	if i.fadeFrame == nil {
+		i.fadeFrame = dsp.NewFrame()
+	}
+	if b, ok := i.Source.(*dsp.Buffer); ok {
+		copy(i.fadeFrame, b.Frame)
+	}
+	i.fadeFrom = from
+	i.fadePos = -delay
+	i.fadeLen = i.fadeLength()
+	if i.fadeLen < 0 {
+		i.fadeLen = 0
+	}
+}
+
+// fading reports whether a crossfade is in progress, releasing the previous source once the fade has run its course.
+func (i *In) fading() bool {
+	if i.fadeFrom == nil {
+		return false
+	}
+	if i.fadePos < i.fadeLen {
+		return true
+	}
+	// There's no one to report an error to on the audio thread; the source is detached either way.
+	i.stopFade()
+	return false
+}
+
+func (i *In) crossfade(in, out dsp.Frame) {
+	from := i.fadeFrame[:len(in)]
+	i.fadeFrom.Process(from)
+	for j := range in {
+		var mix dsp.Float64 = 1
+		if i.fadePos < 0 {
+			mix = 0
+			i.fadePos++
+		} else if i.fadePos < i.fadeLen {
+			mix = dsp.Float64(i.fadePos) / dsp.Float64(i.fadeLen)
+			i.fadePos++
+		}
+		out[j] = from[j]*(1-mix) + in[j]*mix
+	}
+}
+
+func (i *In) stopFade() error {
+	if i.fadeFrom == nil {
+		return nil
+	}
+	from := i.fadeFrom
+	i.fadeFrom = nil
+	i.fadePos, i.fadeLen = 0, 0
+	err := i.releaseSource(from)
+	if o, ok := from.(*Out); ok && o.owner != nil {
+		if cerr := o.owner.closeIfFaded(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// CloseAfterFades closes a module, or holds off closing it until the inputs still crossfading away from its outputs
+// are done reading them
+func CloseAfterFades(p Patcher) error {
+	for _, o := range p.Outputs() {
+		if o.fadingAway() {
+			o.owner.pendingClose = p.Close
+			return nil
+		}
+	}
+	return p.Close()
+}
+
+// closeIfFaded runs a held Close once the last fade away from the module's outputs is over
+func (io *IO) closeIfFaded() error {
+	if io.pendingClose == nil {
+		return nil
+	}
+	for _, o := range io.out {
+		if o.fadingAway() {
+			return nil
+		}
+	}
+	fn := io.pendingClose
+	io.pendingClose = nil
+	return fn()
+}
+
+func sameSource(a, b dsp.Processor) bool {
+	if av, ok := a.(dsp.Valuer); ok {
+		if bv, ok := b.(dsp.Valuer); ok {
+			return av.Value() == bv.Value()
+		}
+		return false
+	}
+	ao, ok := a.(*Out)
+	bo, ok2 := b.(*Out)
+	return ok && ok2 && ao == bo
+}
+
 type releaser interface {
 	release(*In) error
 }
 
-// Close closes the input
-func (i *In) Close() error {
-	var err error
-	switch v := i.Source.(type) {
-	case *dsp.Buffer:
-		if r, ok := v.Processor.(releaser); ok {
-			err = r.release(i)
-		} else {
-			err = v.Close()
-		}
+// current returns the processor the input is reading from
+func (i *In) current() dsp.Processor {
+	if b, ok := i.Source.(*dsp.Buffer); ok {
+		return b.Processor
+	}
+	return i.Source
+}
+
+func (i *In) releaseSource(p dsp.Processor) error {
+	switch v := p.(type) {
 	case releaser:
-		err = v.release(i)
+		return v.release(i)
 	case io.Closer:
-		err = v.Close()
+		return v.Close()
 	}
+	return nil
+}
 
+// Close closes the input
+func (i *In) Close() error {
+	ferr := i.stopFade()
+	err := i.releaseSource(i.current())
 	i.setSource(i.initial)
+	if err == nil {
+		err = ferr
+	}
 	return err
 }
 
+// Reset re-assigns the input to its initial value. Unlike Close, it crossfades away from the current source if the
+// input has a fade length.
+func (i *In) Reset() error {
+	if i.fadeLength() <= 0 {
+		return i.Close()
+	}
+	return i.crossfadeTo(i.initial, 0)
+}
+
 // IsSinking returns whether the input is sinking to audio output
 func (i *In) IsSinking() bool {
 	if i == nil {
@@ -387,6 +622,11 @@ type Out struct {
 	destinations []*In
 	owner        *IO
 	reads        int
+	probes       []*Probe
+	edges        []*Edges
+
+	controlFrame dsp.Frame
+	lastControl  dsp.Float64
 }
 
 func (o *Out) String() string {
@@ -405,12 +645,12 @@ func (o *Out) Process(out dsp.Frame) {
 	}
 
 	if len(o.destinations) == 1 {
-		o.buffer.Process(out)
+		o.process(out)
 		return
 	}
 
 	if o.reads == 0 {
-		o.buffer.Process(out)
+		o.process(out)
 		copy(o.buffer.Frame, out)
 	} else {
 		copy(out, o.buffer.Frame)
@@ -426,10 +666,43 @@ func (o *Out) Process(out dsp.Frame) {
 	}
 }
 
+func (o *Out) process(out dsp.Frame) {
+	frame := out
+	if o.owner != nil && o.owner.rate > 1 {
+		frame = o.controlFrame[:len(out)/o.owner.rate]
+	}
+	if !profiler.enabled || o.owner == nil {
+		o.buffer.Process(frame)
+	} else {
+		start := profileEnter()
+		o.buffer.Process(frame)
+		o.owner.profile.add(profileExit(start))
+	}
+	if len(frame) != len(out) {
+		o.interpolate(frame, out)
+	}
+	for _, p := range o.probes {
+		p.observe(out)
+	}
+	for _, e := range o.edges {
+		e.observe(out)
+	}
+}
+
 func (o *Out) addDestination(in *In) {
 	o.destinations = append(o.destinations, in)
 }
 
+// fadingAway reports whether an input is crossfading away from the output
+func (o *Out) fadingAway() bool {
+	for _, d := range o.destinations {
+		if d.fadeFrom == o {
+			return true
+		}
+	}
+	return false
+}
+
 // DestinationNames returns the name of the destination input
 func (o *Out) DestinationNames() []string {
 	names := []string{}
@@ -484,12 +757,41 @@ type Port struct {
 	Port string
 }
 
+// Reachable returns the module and every module feeding into it, directly or indirectly
+func Reachable(root *IO) []*IO {
+	var (
+		seen   = map[*IO]bool{root: true}
+		result = []*IO{root}
+	)
+	for i := 0; i < len(result); i++ {
+		for _, in := range result[i].Inputs() {
+			out, ok := sourceOut(in)
+			if !ok || seen[out.owner] {
+				continue
+			}
+			seen[out.owner] = true
+			result = append(result, out.owner)
+		}
+	}
+	return result
+}
+
+func sourceOut(in *In) (*Out, bool) {
+	switch v := in.current().(type) {
+	case *Out:
+		return v, v.owner != nil
+	case *In:
+		return sourceOut(v)
+	}
+	return nil, false
+}
+
 func canonicalPort(v string) string {
 	return strings.Replace(v, ".", "/", -1)
 }
 
-// LuaMethod is a function exposed to the Lua layer. If Lock is true, the synthesizer module graph will be locked when
-// it is called to prevent race conditions.
+// LuaMethod is a function exposed to the Lua layer. If Lock is true, the function is run through the Executor guarding
+// the synthesizer module graph to prevent race conditions.
 type LuaMethod struct {
 	Lock bool
 	Func interface{}
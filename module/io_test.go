@@ -199,3 +199,104 @@ func TestNormalizeInput(t *testing.T) {
 	actual, expected = one.OutputsActive(true), 0
 	assert.Equal(t, actual, expected)
 }
+
+func TestCrossfadePatching(t *testing.T) {
+	one, err := newModule(false)
+	assert.Equal(t, err, nil)
+
+	two, err := newModule(true)
+	assert.Equal(t, err, nil)
+
+	input := two.inLookup["input"]
+	input.Fade = 4
+
+	err = two.Patch("input", 2)
+	assert.Equal(t, err, nil)
+
+	frame := input.ProcessFrame()
+	assert.Equal(t, frame[0], dsp.Float64(0))
+	assert.Equal(t, frame[2], dsp.Float64(1))
+	assert.Equal(t, frame[4], dsp.Float64(2))
+
+	// Repatching the same value doesn't start a new fade
+	err = two.Patch("input", 2)
+	assert.Equal(t, err, nil)
+	input.ProcessFrame()
+	assert.Equal(t, input.fadeFrom == nil, true)
+
+	err = two.Patch("input", Port{one, "output"})
+	assert.Equal(t, err, nil)
+	input.ProcessFrame()
+
+	err = two.Patch("input", 1)
+	assert.Equal(t, err, nil)
+
+	// The previous source stays connected until the fade completes
+	actual, expected := one.OutputsActive(true), 1
+	assert.Equal(t, actual, expected)
+
+	input.ProcessFrame()
+	input.ProcessFrame()
+	actual, expected = one.OutputsActive(true), 0
+	assert.Equal(t, actual, expected)
+
+	err = two.Reset()
+	assert.Equal(t, err, nil)
+	frame = input.ProcessFrame()
+	assert.Equal(t, frame[1], dsp.Float64(0.75))
+}
+
+func TestPatchAt(t *testing.T) {
+	m, err := newModule(true)
+	assert.Equal(t, err, nil)
+	input := m.inLookup["input"]
+
+	err = PatchAt(3, func() error {
+		return m.Patch("input", 2)
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, patchOffset, 0)
+
+	frame := input.ProcessFrame()
+	assert.Equal(t, frame[2], dsp.Float64(0))
+	assert.Equal(t, frame[3], dsp.Float64(2))
+	assert.Equal(t, input.ProcessFrame()[0], dsp.Float64(2))
+
+	// A fade starts at the offset
+	input.Fade = 4
+	err = PatchAt(2, func() error {
+		return m.Patch("input", 4)
+	})
+	assert.Equal(t, err, nil)
+	frame = input.ProcessFrame()
+	assert.Equal(t, frame[1], dsp.Float64(2))
+	assert.Equal(t, frame[4], dsp.Float64(3))
+	assert.Equal(t, frame[6], dsp.Float64(4))
+}
+
+func TestEdges(t *testing.T) {
+	m, err := newModule(false)
+	assert.Equal(t, err, nil)
+	out, err := m.Output("output")
+	assert.Equal(t, err, nil)
+
+	var now int64 = 1000
+	edges := NewEdges(func() int64 { return now }, 4)
+	out.AddEdges(edges)
+
+	frame := dsp.NewFrame()
+	frame[3], frame[4], frame[10] = 1, 1, 0.5
+	edges.observe(frame)
+	assert.Equal(t, <-edges.Times(), int64(1003))
+	assert.Equal(t, <-edges.Times(), int64(1010))
+
+	// Edges are found across frames
+	now += int64(len(frame))
+	next := dsp.NewFrame()
+	next[0] = 1
+	edges.observe(next)
+	assert.Equal(t, <-edges.Times(), now)
+
+	out.RemoveEdges(edges)
+	assert.Equal(t, len(out.edges), 0)
+}
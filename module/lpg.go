@@ -13,6 +13,17 @@ func init() {
 		}
 		return newLPG()
 	})
+	Describe("LPGate", Metadata{
+		Description: "Low pass gate: a lowpass filter and VCA opened together by a control signal",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to gate"},
+			{Name: "control", Default: 1, Range: &Range{0, 1}, Description: "how far the gate is open"},
+			{Name: "mode", Default: 1, Range: &Range{0, 2}, Description: "0 filters, 1 filters and amplifies, 2 amplifies"},
+			{Name: "cutoff", Unit: UnitHz, Default: 20000, Range: &Range{20, 20000}, Description: "cutoff frequency when fully open"},
+			{Name: "resonance", Default: 1, Range: &Range{1, 100}, Description: "emphasis of frequencies around the cutoff"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type lpg struct {
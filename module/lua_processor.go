@@ -0,0 +1,410 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	lua "github.com/yuin/gopher-lua"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/lua/instrument"
+)
+
+func init() {
+	Register("LuaProcessor", func(c Config) (Patcher, error) {
+		var config struct {
+			Script  string
+			File    string
+			Inputs  []string
+			Outputs []string
+			Budget  float64
+		}
+		if err := mapstructure.Decode(c, &config); err != nil {
+			return nil, err
+		}
+		if config.File != "" {
+			if err := CheckPath(config.File); err != nil {
+				return nil, err
+			}
+			raw, err := ioutil.ReadFile(config.File)
+			if err != nil {
+				return nil, err
+			}
+			config.Script = string(raw)
+		}
+		if config.Script == "" {
+			return nil, fmt.Errorf(`"script" or "file" is required when initializing LuaProcessor`)
+		}
+		if len(config.Inputs) == 0 {
+			config.Inputs = []string{"input"}
+		}
+		if len(config.Outputs) == 0 {
+			config.Outputs = []string{"output"}
+		}
+		if config.Budget == 0 {
+			config.Budget = 0.25
+		}
+		return newLuaProcessor(config.Script, config.Inputs, config.Outputs, config.Budget)
+	})
+	Describe("LuaProcessor", Metadata{
+		Description: "Runs a Lua function on each frame. The script defines process(inputs, outputs, size), where each " +
+			"input and output is a table of size samples; state kept in the script persists between frames. It goes " +
+			"silent if the script errors or runs past its budget for several frames in a row, until a script is loaded again with :load(script)",
+		Config: []ConfigMetadata{
+			{Name: "script", Type: "string", Description: "Lua source defining process"},
+			{Name: "file", Type: "string", Description: "file to read the script from instead"},
+			{Name: "inputs", Type: "[]string", Default: "input", Description: "names of the inputs"},
+			{Name: "outputs", Type: "[]string", Default: "output", Description: "names of the outputs"},
+			{Name: "budget", Type: "float", Default: "0.25", Description: "fraction of a frame's duration the script may take"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Default: 0, Description: "signal read by the script; renamed or added to with the inputs config"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "signal written by the script; renamed or added to with the outputs config"},
+		},
+	})
+}
+
+var errLuaClosed = errors.New("module was closed")
+
+// luaOverruns is how many frames in a row a script may run past its budget before the processor gives up on it
+const luaOverruns = 4
+
+// luaProcessor runs a Lua function on each frame. The script runs in a Lua state of its own, separate from the REPL,
+// on a goroutine of its own, so the audio thread can give up on it when it runs past its budget. The frames it misses
+// while it catches up are silent.
+type luaProcessor struct {
+	multiOutIO
+	ins      []*In
+	outs     []dsp.Frame
+	outputs  []string
+	budget   time.Duration
+	timer    *time.Timer
+	worker   *luaWorker
+	busy     bool
+	overruns int
+	err      error
+}
+
+func newLuaProcessor(script string, inputs, outputs []string, budget float64) (*luaProcessor, error) {
+	if budget <= 0 {
+		return nil, fmt.Errorf("budget must be positive")
+	}
+	m := &luaProcessor{
+		outputs: outputs,
+		budget:  time.Duration(budget * float64(dsp.FrameSize) / dsp.SampleRate * float64(time.Second)),
+		timer:   time.NewTimer(time.Hour),
+	}
+	m.timer.Stop()
+
+	worker, err := newLuaWorker(script, inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	m.worker = worker
+
+	for _, name := range inputs {
+		m.ins = append(m.ins, NewInBuffer(name, dsp.Float64(0)))
+	}
+	outs := []*Out{}
+	for i, name := range outputs {
+		m.outs = append(m.outs, dsp.NewFrame())
+		outs = append(outs, &Out{Name: name, Provider: provideCopyOut(m, &m.outs[i])})
+	}
+	if err := m.Expose("LuaProcessor", m.ins, outs); err != nil {
+		worker.stop()
+		return nil, err
+	}
+	return m, nil
+}
+
+// LuaMethods exposes methods on the module at the Lua layer
+func (p *luaProcessor) LuaMethods() map[string]LuaMethod {
+	return map[string]LuaMethod{
+		"load": LuaMethod{
+			Lock: true,
+			Func: func(script string) error {
+				return p.Load(script)
+			},
+		},
+		"status": LuaMethod{
+			Lock: true,
+			Func: func() (string, error) {
+				if p.err != nil {
+					return p.err.Error(), nil
+				}
+				return "running", nil
+			},
+		},
+	}
+}
+
+// Load replaces the script, keeping the inputs and outputs. It brings a processor that has failed back to life.
+func (p *luaProcessor) Load(script string) error {
+	inputs := make([]string, len(p.ins))
+	for i, in := range p.ins {
+		inputs[i] = in.Name
+	}
+	worker, err := newLuaWorker(script, inputs, p.outputs)
+	if err != nil {
+		return err
+	}
+	p.worker.stop()
+	p.worker, p.err = worker, nil
+	p.busy, p.overruns = false, 0
+	return nil
+}
+
+func (p *luaProcessor) Process(out dsp.Frame) {
+	p.incrRead(func() {
+		w := p.worker
+		if p.busy {
+			// The worker is still catching up with a frame that ran past its budget
+			select {
+			case err := <-w.done:
+				p.busy = false
+				if err != nil {
+					p.fail(err)
+				}
+			default:
+			}
+		}
+		if p.err != nil || p.busy {
+			for _, in := range p.ins {
+				in.ProcessFrame()
+			}
+			if p.err == nil {
+				p.overrun()
+			}
+			p.silence()
+			return
+		}
+		for i, in := range p.ins {
+			copy(w.in[i], in.ProcessFrame())
+		}
+
+		w.frames <- struct{}{}
+		p.timer.Reset(p.budget)
+		select {
+		case err := <-w.done:
+			if !p.timer.Stop() {
+				<-p.timer.C
+			}
+			if err != nil {
+				p.fail(err)
+				return
+			}
+			p.overruns = 0
+			for i := range p.outs {
+				copy(p.outs[i], w.out[i])
+			}
+		case <-p.timer.C:
+			p.busy = true
+			p.overrun()
+			p.silence()
+		}
+	})
+}
+
+// overrun counts a frame the script didn't finish within its budget, failing once too many of them come in a row
+func (p *luaProcessor) overrun() {
+	if p.overruns++; p.overruns >= luaOverruns {
+		p.fail(fmt.Errorf("script ran past its budget of %v for %d frames in a row", p.budget, p.overruns))
+	}
+}
+
+// fail silences the processor and stops its worker, interrupting a script that's still running
+func (p *luaProcessor) fail(err error) {
+	p.err = err
+	fmt.Println("LuaProcessor:", err)
+	p.worker.stop()
+	p.silence()
+}
+
+func (p *luaProcessor) silence() {
+	for _, frame := range p.outs {
+		for i := range frame {
+			frame[i] = 0
+		}
+	}
+}
+
+// Close stops the script, interrupting any frame it's still working on. The processor is silent from then on, since
+// inputs fading away from it may still read it for a while.
+func (p *luaProcessor) Close() error {
+	p.err = errLuaClosed
+	p.worker.stop()
+	p.silence()
+	return nil
+}
+
+// luaWorker owns the Lua state running a script. Only its goroutine touches the state. The script is instrumented so
+// that stopping the worker interrupts it at its next loop iteration or function call.
+type luaWorker struct {
+	state           *lua.LState
+	process         *lua.LFunction
+	inputs, outputs *lua.LTable
+	inTables        []*lua.LTable
+	outTables       []*lua.LTable
+	in, out         []dsp.Frame
+
+	frames  chan struct{}
+	done    chan error
+	stopped int32
+}
+
+func newLuaWorker(script string, inputs, outputs []string) (*luaWorker, error) {
+	state := newLuaState()
+	w := &luaWorker{
+		state:   state,
+		inputs:  state.NewTable(),
+		outputs: state.NewTable(),
+		frames:  make(chan struct{}),
+		// Buffered so a worker that ran past its budget can finish without anyone waiting on it
+		done: make(chan error, 1),
+	}
+	step := state.NewFunction(func(state *lua.LState) int {
+		if atomic.LoadInt32(&w.stopped) != 0 {
+			state.RaiseError("script was stopped")
+		}
+		return 0
+	})
+
+	state.SetGlobal("sampleRate", lua.LNumber(dsp.SampleRate))
+	if err := runScript(state, script, step); err != nil {
+		state.Close()
+		return nil, err
+	}
+	process, ok := state.GetGlobal("process").(*lua.LFunction)
+	if !ok {
+		state.Close()
+		return nil, fmt.Errorf(`script must define a "process" function`)
+	}
+	w.process = process
+
+	for _, name := range inputs {
+		t := state.NewTable()
+		w.inputs.RawSetString(name, t)
+		w.inTables = append(w.inTables, t)
+		w.in = append(w.in, dsp.NewFrame())
+	}
+	for _, name := range outputs {
+		t := state.NewTable()
+		for i := 1; i <= dsp.FrameSize; i++ {
+			t.RawSetInt(i, lua.LNumber(0))
+		}
+		w.outputs.RawSetString(name, t)
+		w.outTables = append(w.outTables, t)
+		w.out = append(w.out, dsp.NewFrame())
+	}
+	go w.run()
+	return w, nil
+}
+
+// newLuaState returns the Lua state a script runs in. When modules are sandboxed, it only gets the libraries that
+// can't reach the rest of the system.
+func newLuaState() *lua.LState {
+	if !Sandboxed() {
+		return lua.NewState()
+	}
+	state := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.LoadLibName, lua.OpenPackage},
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+		{lua.OsLibName, lua.OpenOs},
+	} {
+		state.Push(state.NewFunction(lib.open))
+		state.Push(lua.LString(lib.name))
+		state.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "getfenv", "load", "loadfile", "loadstring", "module", "package", "require", "setfenv"} {
+		state.SetGlobal(name, lua.LNil)
+	}
+	os := state.NewTable()
+	os.RawSetString("clock", state.GetField(state.GetGlobal("os"), "clock"))
+	os.RawSetString("time", state.GetField(state.GetGlobal("os"), "time"))
+	state.SetGlobal("os", os)
+	return state
+}
+
+// runScript instruments a script to call step on each of its steps and runs its top level. When modules are sandboxed,
+// the top level is also held to the sandbox's limits, since it runs on the goroutine creating the module.
+func runScript(state *lua.LState, script string, step *lua.LFunction) error {
+	proto, err := instrument.Compile(script, "<LuaProcessor>")
+	if err != nil {
+		return err
+	}
+	state.SetGlobal(instrument.StepName, step)
+	var counter *instrument.Counter
+	if Sandboxed() {
+		counter = &instrument.Counter{Limits: sandboxLimits}
+		counter.Reset()
+		state.SetGlobal(instrument.StepName, state.NewFunction(func(state *lua.LState) int {
+			if err := counter.Step(); err != nil {
+				state.RaiseError("script %s", err)
+			}
+			return 0
+		}))
+		defer state.SetGlobal(instrument.StepName, step)
+	}
+
+	state.Push(&lua.LFunction{Env: state.G.Global, Proto: proto, Upvalues: []*lua.Upvalue{}})
+	if err := state.PCall(0, 0, nil); err != nil {
+		return err
+	}
+	if counter != nil {
+		if err := counter.CheckMemory(); err != nil {
+			return fmt.Errorf("script %s", err)
+		}
+	}
+	return nil
+}
+
+func (w *luaWorker) run() {
+	defer w.state.Close()
+	for range w.frames {
+		w.done <- w.call()
+	}
+}
+
+func (w *luaWorker) call() error {
+	for i, t := range w.inTables {
+		for j, v := range w.in[i] {
+			t.RawSetInt(j+1, lua.LNumber(v))
+		}
+	}
+	err := w.state.CallByParam(lua.P{Fn: w.process, Protect: true}, w.inputs, w.outputs, lua.LNumber(dsp.FrameSize))
+	if err != nil {
+		return err
+	}
+	for i, t := range w.outTables {
+		out := w.out[i]
+		for j := range out {
+			if v, ok := t.RawGetInt(j + 1).(lua.LNumber); ok {
+				out[j] = dsp.Float64(v)
+			} else {
+				out[j] = 0
+			}
+		}
+	}
+	return nil
+}
+
+// stop ends the worker's goroutine, interrupting the script if it's in the middle of a frame. Only a script stuck
+// within a single call to a library function keeps its goroutine until the call returns.
+func (w *luaWorker) stop() {
+	if atomic.CompareAndSwapInt32(&w.stopped, 0, 1) {
+		close(w.frames)
+	}
+}
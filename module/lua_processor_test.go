@@ -0,0 +1,141 @@
+package module
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestLuaProcessor(t *testing.T) {
+	p, err := newLuaProcessor(`
+		local last = 0
+		function process(inputs, outputs, size)
+			for i = 1, size do
+				last = last + inputs.input[i] * inputs.scale[i]
+				outputs.sum[i] = last
+				outputs.input[i] = inputs.input[i]
+			end
+		end
+	`, []string{"input", "scale"}, []string{"sum", "input"}, 10)
+	assert.Equal(t, err, nil)
+	defer p.Close()
+	// Nothing is sinking the outputs, so have both share each processed frame
+	p.forcedActiveOutputs = 2
+
+	assert.Equal(t, p.Patch("input", dsp.Float64(1)), nil)
+	assert.Equal(t, p.Patch("scale", dsp.Float64(0.5)), nil)
+	sum, err := p.Output("sum")
+	assert.Equal(t, err, nil)
+	input, err := p.Output("input")
+	assert.Equal(t, err, nil)
+
+	sums, inputs := dsp.NewFrame(), dsp.NewFrame()
+	for i := 0; i < 2; i++ {
+		sum.Process(sums)
+		input.Process(inputs)
+	}
+	// State persists between frames
+	assert.Equal(t, sums[0], dsp.Float64(dsp.FrameSize+1)*0.5)
+	assert.Equal(t, sums[dsp.FrameSize-1], dsp.Float64(dsp.FrameSize*2)*0.5)
+	assert.Equal(t, inputs[0], dsp.Float64(1))
+}
+
+func TestLuaProcessorFailures(t *testing.T) {
+	_, err := newLuaProcessor(`function process(`, []string{"input"}, []string{"output"}, 0.25)
+	assert.NotEqual(t, err, nil)
+	_, err = newLuaProcessor(`x = 1`, []string{"input"}, []string{"output"}, 0.25)
+	assert.NotEqual(t, err, nil)
+
+	var tests = []struct {
+		script, status string
+	}{
+		{`function process() error("broken") end`, "broken"},
+		{`function process()
+			local start = os.clock()
+			while os.clock() - start < 0.05 do end
+		end`, "budget"},
+		{`function process() while true do end end`, "budget"},
+	}
+	for _, test := range tests {
+		p, err := newLuaProcessor(test.script, []string{"input"}, []string{"output"}, 0.25)
+		assert.Equal(t, err, nil)
+
+		out, err := p.Output("output")
+		assert.Equal(t, err, nil)
+		frame := dsp.NewFrame()
+		for i := range frame {
+			frame[i] = 1
+		}
+		// Scripts that run past their budget get a few frames to catch up
+		for i := 0; i < luaOverruns; i++ {
+			out.Process(frame)
+			assert.Equal(t, frame[0], dsp.Float64(0))
+		}
+
+		status, err := p.LuaMethods()["status"].Func.(func() (string, error))()
+		assert.Equal(t, err, nil)
+		assert.Equal(t, strings.Contains(status, test.status), true)
+
+		// A script that ran past its budget is interrupted, even if it was stuck
+		if test.status == "budget" {
+			select {
+			case <-p.worker.done:
+			case <-time.After(time.Second):
+				t.Fatal("script wasn't interrupted")
+			}
+		}
+
+		// Loading a script again brings it back
+		assert.Equal(t, p.Load(`function process(inputs, outputs) outputs.output[1] = 2 end`), nil)
+		out.Process(frame)
+		assert.Equal(t, frame[0], dsp.Float64(2))
+		assert.Equal(t, p.Close(), nil)
+	}
+}
+
+func TestLuaProcessorCatchesUp(t *testing.T) {
+	p, err := newLuaProcessor(`
+		local frames = 0
+		function process(inputs, outputs)
+			frames = frames + 1
+			if frames == 1 then
+				local start = os.clock()
+				while os.clock() - start < 0.1 do end
+			end
+			outputs.output[1] = frames
+		end
+	`, []string{"input"}, []string{"output"}, 0.25)
+	assert.Equal(t, err, nil)
+	defer p.Close()
+
+	out, err := p.Output("output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+	assert.Equal(t, frame[0], dsp.Float64(0))
+
+	// A single slow frame is skipped rather than failing the script
+	time.Sleep(200 * time.Millisecond)
+	out.Process(frame)
+	assert.Equal(t, frame[0], dsp.Float64(2))
+	assert.Equal(t, p.err, nil)
+}
+
+func TestLuaProcessorClosed(t *testing.T) {
+	p, err := newLuaProcessor(`function process(inputs, outputs) outputs.output[1] = 1 end`,
+		[]string{"input"}, []string{"output"}, 0.25)
+	assert.Equal(t, err, nil)
+	out, err := p.Output("output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+	assert.Equal(t, frame[0], dsp.Float64(1))
+
+	// Inputs fading away from a closed processor may still read it
+	assert.Equal(t, p.Close(), nil)
+	out.Process(frame)
+	assert.Equal(t, frame[0], dsp.Float64(0))
+}
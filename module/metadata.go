@@ -0,0 +1,126 @@
+package module
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"text/tabwriter"
+)
+
+var (
+	metadata  = map[string]Metadata{}
+	portIndex = regexp.MustCompile(`(^|/)\d+(/|$)`)
+	portLayer = regexp.MustCompile(`^[a-z]/`)
+)
+
+// Metadata describes a module type: what it does, how it's configured and what its ports expect
+type Metadata struct {
+	Description string
+	Config      []ConfigMetadata
+	Inputs      []PortMetadata
+	Outputs     []PortMetadata
+
+	// ControlRate marks modules that only depend on time through inputs with a unit, so they behave the same at a
+	// control rate. They run at DefaultControlRate unless configured otherwise.
+	ControlRate bool
+}
+
+// ConfigMetadata describes a key accepted in a module's Config
+type ConfigMetadata struct {
+	Name, Type, Default, Description string
+}
+
+// PortMetadata describes an input or output. Ports created per-index (e.g. "0/input", "1/input") are described once
+// using "<n>" in place of the index, ports created per-layer (e.g. "a/pitch", "b/pitch") using "<layer>" in place of
+// the layer, and ports named by the module's config are described as "<name>".
+type PortMetadata struct {
+	Name, Description string
+	Unit              string
+	Default           float64
+	Range             *Range
+}
+
+// Range is the range of values an input is designed to handle
+type Range struct {
+	Min, Max float64
+}
+
+// describedName returns the name a port is described under, with any index replaced by "<n>" and any layer by
+// "<layer>"
+func describedName(name string) string {
+	return portLayer.ReplaceAllString(portIndex.ReplaceAllString(name, "$1<n>$2"), "<layer>/")
+}
+
+// Describe attaches metadata to a registered module type. It's used by the REPL's help and `eolian -describe`.
+func Describe(name string, m Metadata) {
+	if _, ok := registry[name]; !ok {
+		panic(fmt.Sprintf("%s is not registered as a module", name))
+	}
+	metadata[name] = m
+}
+
+// LookupMetadata retrieves the metadata of a module type
+func LookupMetadata(name string) (Metadata, error) {
+	if _, ok := registry[name]; !ok {
+		return Metadata{}, fmt.Errorf("module type not registered: %s", name)
+	}
+	m, ok := metadata[name]
+	if !ok {
+		return Metadata{}, fmt.Errorf("no description available for %s", name)
+	}
+	return m, nil
+}
+
+// Help returns a human-readable description of a module type
+func Help(name string) (string, error) {
+	m, err := LookupMetadata(name)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s: %s\n", name, m.Description)
+
+	w := tabwriter.NewWriter(&b, 5, 0, 2, ' ', 0)
+	if len(m.Config) > 0 {
+		fmt.Fprintln(w, "\nConfig:")
+		for _, c := range m.Config {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Name, c.Type, c.Default, c.Description)
+		}
+	}
+	if len(m.Inputs) > 0 {
+		fmt.Fprintln(w, "\nInputs:")
+		for _, p := range m.Inputs {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", p.Name, formatValue(p.Default, p.Unit), p.Range, p.Description)
+		}
+	}
+	if len(m.Outputs) > 0 {
+		fmt.Fprintln(w, "\nOutputs:")
+		for _, p := range m.Outputs {
+			fmt.Fprintf(w, "  %s\t%s\n", p.Name, p.Description)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	if m.ControlRate {
+		fmt.Fprintln(&b, "\nSuitable for control rate (see -control-rate)")
+	}
+	return b.String(), nil
+}
+
+func (r *Range) String() string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("[%s, %s]", formatValue(r.Min, ""), formatValue(r.Max, ""))
+}
+
+func formatValue(v float64, unit string) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if unit != "" {
+		s += unit
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+// TestMetadata ensures the described ports and defaults haven't drifted from the modules themselves
+func TestMetadata(t *testing.T) {
+	for _, m := range allModules {
+		meta, err := LookupMetadata(m.Name)
+		if err != nil {
+			continue
+		}
+		t.Run(m.Name, func(t *testing.T) {
+			init, err := Lookup(m.Name)
+			assert.Equal(t, err, nil)
+			p, err := init(m.Config)
+			assert.Equal(t, err, nil)
+
+			inputs := map[string]PortMetadata{}
+			for _, in := range meta.Inputs {
+				inputs[in.Name] = in
+			}
+			for name, in := range p.Inputs() {
+				described, ok := inputs[describedName(name)]
+				if !ok {
+					described, ok = inputs["<name>"]
+				}
+				assert.Equal(t, ok, true)
+
+				initial, ok := in.initial.(dsp.Valuer)
+				assert.Equal(t, ok, true)
+				state := InputState{Value: &described.Default}
+				if _, ok := initial.(dsp.Float64); !ok {
+					state.Unit = described.Unit
+				}
+				expected, err := state.Constant()
+				assert.Equal(t, err, nil)
+				assert.Equal(t, initial.Value(), expected.(dsp.Valuer).Value())
+			}
+
+			outputs := map[string]bool{}
+			for _, out := range meta.Outputs {
+				outputs[out.Name] = true
+			}
+			for name := range p.Outputs() {
+				assert.Equal(t, outputs[describedName(name)], true)
+			}
+		})
+	}
+}
+
+// TestDescribed ensures every registered module type can be looked up in the REPL's help and `eolian -describe`
+func TestDescribed(t *testing.T) {
+	for _, name := range RegisteredTypes() {
+		if _, err := LookupMetadata(name); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	help, err := Help("Filter")
+	assert.Equal(t, err, nil)
+
+	lines := strings.Split(help, "\n")
+	assert.Equal(t, lines[0], "Filter: State-variable filter with simultaneous lowpass, highpass and bandpass outputs")
+
+	var cutoff string
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "cutoff") {
+			cutoff = strings.Join(strings.Fields(l), " ")
+		}
+	}
+	assert.Equal(t, cutoff, "cutoff 1000Hz [20, 20000] cutoff frequency")
+
+	_, err = Help("Unknown")
+	assert.NotEqual(t, err, nil)
+}
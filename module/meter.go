@@ -0,0 +1,57 @@
+package module
+
+import "buddin.us/eolian/dsp"
+
+func init() {
+	Register("Meter", func(Config) (Patcher, error) { return newMeter() })
+	Describe("Meter", Metadata{
+		Description: "Measures the level of a signal passing through it; read the levels with meter:read()",
+		Inputs:      []PortMetadata{{Name: "input", Description: "signal to measure"}},
+		Outputs:     []PortMetadata{{Name: "output", Description: "the input, unchanged"}},
+	})
+}
+
+type meter struct {
+	IO
+	in     *In
+	levels dsp.Levels
+}
+
+func newMeter() (*meter, error) {
+	m := &meter{
+		in: NewIn("input", dsp.Float64(0)),
+	}
+	return m, m.Expose("Meter", []*In{m.in}, []*Out{{Name: "output", Provider: dsp.Provide(m)}})
+}
+
+// LuaMethods exposes methods on the module at the Lua layer
+func (m *meter) LuaMethods() map[string]LuaMethod {
+	return map[string]LuaMethod{
+		"read": LuaMethod{
+			Lock: true,
+			Func: func() (map[string]interface{}, error) {
+				return ReadLevels(&m.levels), nil
+			},
+		},
+	}
+}
+
+func (m *meter) Process(out dsp.Frame) {
+	m.in.Process(out)
+	for i := range out {
+		m.levels.Tick(out[i])
+	}
+}
+
+// ReadLevels reads the levels measured since the last read in a form suitable for LuaMethods. Peak and RMS are
+// reported both as amplitudes and in dBFS.
+func ReadLevels(l *dsp.Levels) map[string]interface{} {
+	peak, rms, clips := l.Read()
+	return map[string]interface{}{
+		"peak":   peak,
+		"rms":    rms,
+		"peakDB": dsp.Decibels(peak),
+		"rmsDB":  dsp.Decibels(rms),
+		"clips":  clips,
+	}
+}
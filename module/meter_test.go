@@ -0,0 +1,31 @@
+package module
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestMeter(t *testing.T) {
+	m, err := newMeter()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, m.Patch("input", dsp.Float64(-0.5)), nil)
+
+	out, err := m.Output("output")
+	assert.Equal(t, err, nil)
+	frame := dsp.NewFrame()
+	out.Process(frame)
+	assert.Equal(t, frame[0], dsp.Float64(-0.5))
+
+	read := m.LuaMethods()["read"].Func.(func() (map[string]interface{}, error))
+	levels, err := read()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, levels["peak"], dsp.Float64(0.5))
+	assert.Equal(t, levels["rms"], dsp.Float64(0.5))
+	assert.Equal(t, levels["clips"], 0)
+
+	levels, err = read()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, levels["peak"], dsp.Float64(0))
+}
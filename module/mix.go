@@ -21,6 +21,20 @@ func init() {
 		}
 		return newMix(config.Size)
 	})
+	Describe("Mix", Metadata{
+		Description: "Mixer summing a number of channels",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "4", Description: "number of channels"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "<n>/input", Description: "signal of the channel"},
+			{Name: "<n>/level", Default: 1, Range: &Range{0, 1}, Description: "level of the channel"},
+			{Name: "master", Default: 1, Range: &Range{0, 1}, Description: "level of the sum"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "sum of the channels"},
+		},
+	})
 }
 
 type mix struct {
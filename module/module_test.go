@@ -23,6 +23,7 @@ var allModules = []struct {
 	{"AND", nil, []string{"a", "b"}, defaultOutput},
 	{"Allpass", nil, []string{"input", "duration", "gain"}, defaultOutput},
 	{"BPM", nil, []string{"input"}, defaultOutput},
+	{"Capture", Config{"channels": 2}, []string{"0.input", "1.input", "trigger"}, []string{"0.output", "1.output"}},
 	{"Ceil", nil, []string{"input"}, defaultOutput},
 	{"ChanceGate", nil, []string{"input", "bias"}, []string{"a", "b"}},
 	{"Clip", nil, []string{"input", "level"}, defaultOutput},
@@ -63,10 +64,13 @@ var allModules = []struct {
 	{"Invert", nil, []string{"input"}, defaultOutput},
 	{"Filter", nil, []string{"input", "cutoff", "resonance"}, []string{"lowpass", "bandpass", "highpass"}},
 	{"LPGate", nil, []string{"input", "cutoff", "resonance", "control", "mode"}, defaultOutput},
+	{"LuaProcessor", Config{"script": "function process() end"}, []string{"input"}, defaultOutput},
 	{"MathExp", Config{"expression": "x + y * 2"}, []string{"x", "y"}, defaultOutput},
 	{"Max", nil, []string{"a", "b"}, defaultOutput},
+	{"Meter", nil, []string{"input"}, defaultOutput},
 	{"Min", nil, []string{"a", "b"}, defaultOutput},
 	{"Mix", nil, []string{"0.input", "0.level", "1.input", "1.level", "2.input", "2.level", "3.input", "3.level", "master"}, defaultOutput},
+	{"Morph", Config{"snapshots": []string{"testdata/morph_a.json", "testdata/morph_b.json"}}, []string{"position"}, []string{"0", "1"}},
 	{"Mux", nil, []string{"0.input", "1.input", "selection"}, []string{"output"}},
 	{"Mod", nil, []string{"a", "b"}, defaultOutput},
 	{"MS", nil, []string{"input"}, defaultOutput},
@@ -81,6 +85,7 @@ var allModules = []struct {
 		[]string{"sine", "saw", "pulse", "triangle", "sub"}},
 	{"Pan", nil, []string{"input", "bias"}, []string{"a", "b"}},
 	{"PanMix", nil, []string{"0.input", "0.level", "0.pan", "1.input", "1.level", "1.pan", "2.input", "2.level", "2.pan", "3.input", "3.level", "3.pan", "master"}, []string{"a", "b"}},
+	{"Pattern", nil, []string{"clock", "reset", "length"}, []string{"pitch", "gate", "velocity"}},
 	{"FBPingPongDelay", nil, []string{"a", "b", "duration", "gain"}, []string{"a", "b"}},
 	{"Quantize", Config{"size": 2}, []string{"input", "0.pitch", "1.pitch", "transpose"}, defaultOutput},
 	{"Random", nil, []string{"clock", "max", "min", "probability", "smoothness"}, []string{"stepped", "smooth"}},
@@ -0,0 +1,174 @@
+package module
+
+import (
+	"sort"
+	"strconv"
+
+	"buddin.us/eolian/dsp"
+	"github.com/mitchellh/mapstructure"
+)
+
+func init() {
+	Register("Morph", func(c Config) (Patcher, error) {
+		var config struct {
+			Snapshots []string
+		}
+		if err := mapstructure.Decode(c, &config); err != nil {
+			return nil, err
+		}
+		snapshots := make([]Snapshot, len(config.Snapshots))
+		for i, path := range config.Snapshots {
+			if err := CheckPath(path); err != nil {
+				return nil, err
+			}
+			s, err := ReadSnapshot(path)
+			if err != nil {
+				return nil, err
+			}
+			snapshots[i] = s
+		}
+		return newMorph(MorphTargets(snapshots))
+	})
+	Describe("Morph", Metadata{
+		Description: "Blends every constant input that changes across a series of rack snapshots",
+		Config: []ConfigMetadata{
+			{Name: "snapshots", Type: "[]string", Description: "snapshot files written by Rack.snapshot"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "position", Default: 0, Range: &Range{0, 1}, Description: "0 is the first snapshot and 1 is the last"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "<n>", Description: "blended value of the nth target input"},
+		},
+	})
+}
+
+// MorphTarget is a module input that can be blended between a series of snapshots
+type MorphTarget struct {
+	Module, Input string
+	Values        []dsp.Float64
+	Log           bool
+}
+
+// MorphTargets finds every input that holds a constant value (of the same unit) in all of the snapshots, leaving out
+// those whose value is the same in all of them. Frequencies are interpolated in log space so sweeps between them are
+// even in pitch.
+func MorphTargets(snapshots []Snapshot) []MorphTarget {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	targets := []MorphTarget{}
+	for path, ms := range snapshots[0].Modules {
+	inputs:
+		for name, first := range ms.Inputs {
+			if first.IsConnection() {
+				continue
+			}
+			t := MorphTarget{
+				Module: path,
+				Input:  name,
+				Log:    first.Unit == UnitHz || first.Unit == UnitPitch,
+			}
+			for _, s := range snapshots {
+				is, ok := s.Modules[path].Inputs[name]
+				if !ok || is.IsConnection() || is.Unit != first.Unit || s.Modules[path].Type != ms.Type {
+					continue inputs
+				}
+				c, err := is.Constant()
+				if err != nil {
+					continue inputs
+				}
+				v := c.(dsp.Valuer).Value()
+				if v <= 0 {
+					t.Log = false
+				}
+				t.Values = append(t.Values, v)
+			}
+			if !varies(t.Values) {
+				continue
+			}
+			if t.Log {
+				for i, v := range t.Values {
+					t.Values[i] = dsp.Log(v)
+				}
+			}
+			targets = append(targets, t)
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Module == targets[j].Module {
+			return targets[i].Input < targets[j].Input
+		}
+		return targets[i].Module < targets[j].Module
+	})
+	return targets
+}
+
+func varies(values []dsp.Float64) bool {
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return true
+		}
+	}
+	return false
+}
+
+type morph struct {
+	multiOutIO
+	position *In
+	targets  []MorphTarget
+	frames   []dsp.Frame
+}
+
+func newMorph(targets []MorphTarget) (*morph, error) {
+	m := &morph{
+		position: NewInBuffer("position", dsp.Float64(0)),
+		targets:  targets,
+		frames:   make([]dsp.Frame, len(targets)),
+	}
+
+	outputs := []*Out{}
+	for i := range targets {
+		m.frames[i] = dsp.NewFrame()
+		outputs = append(outputs, &Out{
+			Name:     strconv.Itoa(i),
+			Provider: provideCopyOut(m, &m.frames[i]),
+		})
+	}
+
+	return m, m.Expose("Morph", []*In{m.position}, outputs)
+}
+
+// Targets returns the inputs blended by the module. The output for each target is named after its index.
+func (m *morph) Targets() []MorphTarget {
+	return m.targets
+}
+
+func (m *morph) Process(_ dsp.Frame) {
+	m.incrRead(func() {
+		position := m.position.ProcessFrame()
+		for i := range position {
+			for j, t := range m.targets {
+				m.frames[j][i] = t.blend(position[i])
+			}
+		}
+	})
+}
+
+// blend interpolates between the values of neighboring snapshots; position 0 is the first snapshot and 1 is the last
+func (t MorphTarget) blend(position dsp.Float64) dsp.Float64 {
+	last := len(t.Values) - 1
+	idx, frac := dsp.Modf(dsp.Clamp(position, 0, 1) * dsp.Float64(last))
+	i := int(idx)
+
+	v := t.Values[i]
+	if i < last {
+		v = dsp.Lerp(frac, v, t.Values[i+1])
+	}
+	if t.Log {
+		return dsp.Exp(v)
+	}
+	return v
+}
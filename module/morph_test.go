@@ -0,0 +1,37 @@
+package module
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestMorph(t *testing.T) {
+	init, err := Lookup("Morph")
+	assert.Equal(t, err, nil)
+
+	p, err := init(Config{"snapshots": []string{"testdata/morph_a.json", "testdata/morph_b.json"}})
+	assert.Equal(t, err, nil)
+
+	// pitchModAmount is left out because it's the same in both snapshots
+	targets := p.(*morph).Targets()
+	assert.Equal(t, len(targets), 2)
+	assert.Equal(t, targets[0].Input, "amp")
+	assert.Equal(t, targets[1].Input, "pitch")
+
+	assert.Equal(t, p.Patch("position", dsp.Float64(0.5)), nil)
+
+	amp, err := p.Output("0")
+	assert.Equal(t, err, nil)
+	pitch, err := p.Output("1")
+	assert.Equal(t, err, nil)
+
+	ampFrame, pitchFrame := dsp.NewFrame(), dsp.NewFrame()
+	amp.Process(ampFrame)
+	pitch.Process(pitchFrame)
+
+	// Halfway between A3 and A5 in log space is A4
+	assert.Equal(t, float64(ampFrame[0]), 0.75)
+	assert.Equal(t, round(pitchFrame[0]*1e9), round(dsp.Frequency(440).Value()*1e9))
+}
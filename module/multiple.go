@@ -21,6 +21,14 @@ func init() {
 		}
 		return newMultiple(config.Size)
 	})
+	Describe("Multiple", Metadata{
+		Description: "Copies its input to a number of outputs",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "4", Description: "number of outputs"},
+		},
+		Inputs:  []PortMetadata{{Name: "input"}},
+		Outputs: []PortMetadata{{Name: "<n>", Description: "copy of the input"}},
+	})
 }
 
 type multiple struct {
@@ -29,6 +29,21 @@ func init() {
 		}
 		return newDemux(config.Size)
 	})
+
+	size := ConfigMetadata{Name: "size", Type: "int", Default: "2", Description: "number of channels"}
+	selection := PortMetadata{Name: "selection", Default: 0, Description: "index of the selected channel"}
+	Describe("Mux", Metadata{
+		Description: "Passes one of its inputs through",
+		Config:      []ConfigMetadata{size},
+		Inputs:      []PortMetadata{{Name: "<n>/input", Default: 0}, selection},
+		Outputs:     []PortMetadata{{Name: "output", Description: "the selected input"}},
+	})
+	Describe("Demux", Metadata{
+		Description: "Sends its input to one of its outputs",
+		Config:      []ConfigMetadata{size},
+		Inputs:      []PortMetadata{{Name: "input"}, selection},
+		Outputs:     []PortMetadata{{Name: "<n>", Description: "the input when selected and 0 otherwise"}},
+	})
 }
 
 type mux struct {
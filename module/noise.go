@@ -3,15 +3,35 @@ package module
 import "buddin.us/eolian/dsp"
 
 func init() {
-	Register("Noise", func(Config) (Patcher, error) { return newNoise() })
+	Register("Noise", func(c Config) (Patcher, error) {
+		seed, err := seedConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		return newNoise(seed)
+	})
+	Describe("Noise", Metadata{
+		Description: "White noise generator",
+		Config:      []ConfigMetadata{seedMetadata},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "added to the noise"},
+			{Name: "min", Default: -1, Description: "lowest value of the noise"},
+			{Name: "max", Default: 1, Description: "highest value of the noise"},
+			{Name: "gain", Default: 1, Range: &Range{0, 1}, Description: "level of the noise"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output"},
+		},
+	})
 }
 
 type noise struct {
 	IO
 	in, min, max, gain *In
+	rand               *randSource
 }
 
-func newNoise() (*noise, error) {
+func newNoise(seed int64) (*noise, error) {
 	m := &noise{
 		in:   NewIn("input", dsp.Float64(0)),
 		min:  NewInBuffer("min", dsp.Float64(-1)),
@@ -23,6 +43,7 @@ func newNoise() (*noise, error) {
 		[]*In{m.in, m.min, m.max, m.gain},
 		[]*Out{{Name: "output", Provider: dsp.Provide(m)}},
 	)
+	m.rand = newRand(m.ID(), seed)
 	return m, err
 }
 
@@ -33,6 +54,6 @@ func (n *noise) Process(out dsp.Frame) {
 	gain := n.gain.ProcessFrame()
 	for i := range out {
 		diff := max[i] - min[i]
-		out[i] += (dsp.Rand()*diff + min[i]) * gain[i]
+		out[i] += (n.rand.Value()*diff + min[i]) * gain[i]
 	}
 }
@@ -25,6 +25,19 @@ func init() {
 		}
 		return newNoteQuantize(config.Key, config.Scale)
 	})
+	Describe("NoteQuantize", Metadata{
+		Description: "Maps its input from 0 to 1 onto the notes of a scale. The key and scale may be changed with " +
+			":setKey(key) and :setIntervals(scale)",
+		Config: []ConfigMetadata{
+			{Name: "key", Type: "string", Default: "C", Description: "tonic of the scale"},
+			{Name: "scale", Type: "string", Default: "major", Description: "scale, e.g. minor, dorian or majorPentatonic"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Range: &Range{0, 1}, Description: "position within the scale's notes"},
+			{Name: "octave", Default: 3, Description: "octave of the tonic"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "pitch"}},
+	})
 }
 
 type noteQuantize struct {
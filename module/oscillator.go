@@ -30,6 +30,32 @@ func init() {
 	}
 	Register("Osc", f)
 	Register("Oscillator", f)
+	for _, name := range []string{"Osc", "Oscillator"} {
+		Describe(name, Metadata{
+			Description: "Band-limited oscillator with pulse, saw, sine, triangle and sub outputs",
+			Config: []ConfigMetadata{
+				{Name: "algorithm", Type: "string", Default: algBLEP, Description: `waveform generation; "blep" or "simple"`},
+				{Name: "multiplier", Type: "float", Default: "1", Description: "scales the frequency of every output"},
+			},
+			Inputs: []PortMetadata{
+				{Name: "pitch", Unit: UnitHz, Default: 0, Description: "frequency"},
+				{Name: "pitchMod", Default: 0, Range: &Range{-1, 1}, Description: "frequency modulation"},
+				{Name: "pitchModAmount", Default: 1, Range: &Range{0, 1}, Description: "depth of the frequency modulation"},
+				{Name: "amp", Default: 1, Range: &Range{0, 1}, Description: "amplitude"},
+				{Name: "detune", Unit: UnitHz, Default: 0, Description: "offset added to the frequency"},
+				{Name: "offset", Default: 0, Description: "DC offset added to the outputs"},
+				{Name: "pulseWidth", Default: 1, Range: &Range{0, 1}, Description: "width of the pulse output"},
+				{Name: "sync", Default: 0, Description: "resets the phase while high"},
+			},
+			Outputs: []PortMetadata{
+				{Name: "pulse"},
+				{Name: "saw"},
+				{Name: "sine"},
+				{Name: "triangle"},
+				{Name: "sub", Description: "pulse one octave below"},
+			},
+		})
+	}
 }
 
 const (
@@ -4,6 +4,17 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Pan", func(Config) (Patcher, error) { return newPan() })
+	Describe("Pan", Metadata{
+		Description: "Pans its input between two channels",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to pan"},
+			{Name: "bias", Default: 0, Range: &Range{-1, 1}, Description: "-1 is only a, 1 is only b and 0 is both"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "left channel"},
+			{Name: "b", Description: "right channel"},
+		},
+	})
 }
 
 type pan struct {
@@ -21,6 +21,22 @@ func init() {
 		}
 		return newPanMix(config.Size)
 	})
+	Describe("PanMix", Metadata{
+		Description: "Stereo mixer panning each of a number of channels",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "4", Description: "number of channels"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "<n>/input", Description: "signal of the channel"},
+			{Name: "<n>/level", Default: 1, Range: &Range{0, 1}, Description: "level of the channel"},
+			{Name: "<n>/pan", Default: 0, Range: &Range{-1, 1}, Description: "-1 is only a, 1 is only b and 0 is both"},
+			{Name: "master", Default: 1, Range: &Range{0, 1}, Description: "level of the sums"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "left sum"},
+			{Name: "b", Description: "right sum"},
+		},
+	})
 }
 
 type panMix struct {
@@ -0,0 +1,225 @@
+package module
+
+import (
+	"fmt"
+	"math"
+
+	"buddin.us/eolian/dsp"
+	"buddin.us/eolian/pattern"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+func init() {
+	Register("Pattern", func(c Config) (Patcher, error) {
+		var config struct {
+			Pattern string
+			Pulses  int
+		}
+		if err := mapstructure.Decode(c, &config); err != nil {
+			return nil, err
+		}
+		if config.Pulses == 0 {
+			config.Pulses = 4
+		}
+		return newPatternPlayer(config.Pattern, config.Pulses)
+	})
+	Describe("Pattern", Metadata{
+		Description: "Plays a pattern written in mini-notation against a clock; swap it with " +
+			"player:play(pat('c3 e3 [g3 b3] ~')) and the new pattern starts with the next cycle",
+		Config: []ConfigMetadata{
+			{Name: "pattern", Type: "string", Description: "pattern played from the start"},
+			{Name: "pulses", Type: "int", Default: "4", Description: "clock pulses in each cycle of the pattern"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "pulses the pattern is played against"},
+			{Name: "reset", Default: 0, Description: "restarts the pattern from its first cycle on the next pulse"},
+			{Name: "length", Default: 0.9, Range: &Range{0, 1}, Description: "fraction of each event the gate is high"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "pitch", Description: "value of the latest event"},
+			{Name: "gate", Description: "high during events"},
+			{Name: "velocity", Description: "velocity of the latest event"},
+		},
+	})
+}
+
+// patternPlayer steps through the events of a pattern. The position within the cycle between clock pulses is
+// estimated from the interval between the last two pulses, so events that fall between pulses play on time.
+type patternPlayer struct {
+	multiOutIO
+	clock, reset, length *In
+	pulses               int
+
+	// Patterns are handed over from Lua without blocking the audio thread; a new one starts with the next cycle
+	pattern pattern.Pattern
+	next    chan pattern.Pattern
+
+	events                         []pattern.Event
+	cycle, pulse, index            int
+	started, restart               bool
+	elapsed, period                int
+	gateOn                         bool
+	gateEnd                        float64
+	pitch, velocity                dsp.Float64
+	lastClock, lastReset           dsp.Float64
+	lastGate                       dsp.Float64
+	pitchOut, gateOut, velocityOut dsp.Frame
+}
+
+func newPatternPlayer(src string, pulses int) (*patternPlayer, error) {
+	if pulses < 1 {
+		return nil, fmt.Errorf("pattern needs at least one pulse per cycle")
+	}
+	m := &patternPlayer{
+		clock:       NewInBuffer("clock", dsp.Float64(0)),
+		reset:       NewInBuffer("reset", dsp.Float64(0)),
+		length:      NewInBuffer("length", dsp.Float64(0.9)),
+		pulses:      pulses,
+		next:        make(chan pattern.Pattern, 1),
+		lastClock:   -1,
+		lastReset:   -1,
+		lastGate:    -1,
+		pitchOut:    dsp.NewFrame(),
+		gateOut:     dsp.NewFrame(),
+		velocityOut: dsp.NewFrame(),
+	}
+	if src != "" {
+		p, err := pattern.Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		m.pattern = p
+	}
+	return m, m.Expose(
+		"Pattern",
+		[]*In{m.clock, m.reset, m.length},
+		[]*Out{
+			{Name: "pitch", Provider: provideCopyOut(m, &m.pitchOut)},
+			{Name: "gate", Provider: provideCopyOut(m, &m.gateOut)},
+			{Name: "velocity", Provider: provideCopyOut(m, &m.velocityOut)},
+		},
+	)
+}
+
+// LuaMethods exposes methods on the module at the Lua layer
+func (p *patternPlayer) LuaMethods() map[string]LuaMethod {
+	return map[string]LuaMethod{
+		"play": LuaMethod{
+			Func: func(v interface{}) error {
+				return p.Play(v)
+			},
+		},
+	}
+}
+
+// Play queues a pattern, or the mini-notation of one, to start with the next cycle. It replaces any pattern queued
+// before that hasn't started yet.
+func (p *patternPlayer) Play(v interface{}) error {
+	var next pattern.Pattern
+	switch v := v.(type) {
+	case pattern.Pattern:
+		next = v
+	case string:
+		parsed, err := pattern.Parse(v)
+		if err != nil {
+			return err
+		}
+		next = parsed
+	default:
+		return fmt.Errorf("expected a pattern; got %T", v)
+	}
+	select {
+	case <-p.next:
+	default:
+	}
+	p.next <- next
+	return nil
+}
+
+func (p *patternPlayer) Process(out dsp.Frame) {
+	p.incrRead(func() {
+		clock := p.clock.ProcessFrame()
+		reset := p.reset.ProcessFrame()
+		length := p.length.ProcessFrame()
+
+		for i := range out {
+			if p.lastReset <= 0 && reset[i] > 0 {
+				p.restart = true
+			}
+			if p.lastClock <= 0 && clock[i] > 0 {
+				p.tick()
+			}
+
+			gate := dsp.Float64(-1)
+			if p.started {
+				phase := p.phase()
+				if p.gateOn && phase >= p.gateEnd {
+					p.gateOn = false
+				}
+				var triggered bool
+				for p.index < len(p.events) && p.events[p.index].Start <= phase {
+					e := p.events[p.index]
+					p.pitch, p.velocity = e.Value.Value(), dsp.Float64(e.Velocity)
+					p.gateEnd = e.Start + (e.End-e.Start)*float64(length[i])
+					p.gateOn, triggered = true, true
+					p.index++
+				}
+				// Back-to-back events drop the gate for a sample so each one retriggers
+				if p.gateOn && !(triggered && p.lastGate > 0) {
+					gate = 1
+				}
+			}
+
+			p.pitchOut[i], p.gateOut[i], p.velocityOut[i] = p.pitch, gate, p.velocity
+			p.lastGate = gate
+			p.lastClock, p.lastReset = clock[i], reset[i]
+			p.elapsed++
+		}
+	})
+}
+
+// tick advances the pattern by one clock pulse
+func (p *patternPlayer) tick() {
+	if p.started && !p.restart {
+		p.period = p.elapsed
+		if p.pulse++; p.pulse == p.pulses {
+			p.pulse = 0
+			p.cycle++
+			p.load()
+		}
+	} else {
+		if p.started {
+			p.period = p.elapsed
+		}
+		p.started, p.restart = true, false
+		p.pulse, p.cycle = 0, 0
+		p.load()
+	}
+	p.elapsed = 0
+}
+
+// load starts a cycle, switching to a queued pattern if there is one
+func (p *patternPlayer) load() {
+	select {
+	case next := <-p.next:
+		p.pattern = next
+	default:
+	}
+	p.events, p.index = nil, 0
+	if p.pattern != nil {
+		p.events = p.pattern.Cycle(p.cycle)
+	}
+	if p.gateOn {
+		p.gateEnd--
+	}
+}
+
+// phase is the position within the cycle. It doesn't run past the next pulse if the clock slows down.
+func (p *patternPlayer) phase() float64 {
+	var frac float64
+	if p.period > 0 {
+		frac = math.Min(float64(p.elapsed)/float64(p.period), math.Nextafter(1, 0))
+	}
+	return (float64(p.pulse) + frac) / float64(p.pulses)
+}
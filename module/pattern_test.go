@@ -0,0 +1,76 @@
+package module
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+// pulses is high for the first half of every period
+type pulses struct{ period, n int }
+
+func (p *pulses) Process(out dsp.Frame) {
+	for i := range out {
+		if p.n%p.period < p.period/2 {
+			out[i] = 1
+		} else {
+			out[i] = -1
+		}
+		p.n++
+	}
+}
+
+func pitchValue(t *testing.T, name string) dsp.Float64 {
+	p, err := dsp.ParsePitch(name)
+	assert.Equal(t, err, nil)
+	return p.Value()
+}
+
+func TestPatternPlayer(t *testing.T) {
+	m, err := newPatternPlayer("c3 [e3 g3:0.5]", 2)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, m.Patch("clock", &pulses{period: 100}), nil)
+	// Nothing is sinking the outputs, so have all three share each processed frame
+	m.forcedActiveOutputs = 3
+
+	pitch, err := m.Output("pitch")
+	assert.Equal(t, err, nil)
+	gate, err := m.Output("gate")
+	assert.Equal(t, err, nil)
+	velocity, err := m.Output("velocity")
+	assert.Equal(t, err, nil)
+
+	var (
+		pitches   = dsp.NewFrame()
+		gates     = dsp.NewFrame()
+		velocites = dsp.NewFrame()
+	)
+	process := func() {
+		pitch.Process(pitches)
+		gate.Process(gates)
+		velocity.Process(velocites)
+	}
+	process()
+
+	// Once the clock's period is known, events between pulses play on time. Cycles last two pulses.
+	assert.Equal(t, pitches[200], pitchValue(t, "C3"))
+	assert.Equal(t, gates[285], dsp.Float64(1))
+	assert.Equal(t, gates[295], dsp.Float64(-1))
+	assert.Equal(t, pitches[300], pitchValue(t, "E3"))
+	assert.Equal(t, gates[300], dsp.Float64(1))
+	assert.Equal(t, gates[347], dsp.Float64(-1))
+	assert.Equal(t, pitches[350], pitchValue(t, "G3"))
+	assert.Equal(t, gates[350], dsp.Float64(1))
+	assert.Equal(t, velocites[350], dsp.Float64(0.5))
+
+	// A new pattern starts with the next cycle, at sample 600
+	assert.Equal(t, m.Play("c4"), nil)
+	process()
+	assert.Equal(t, pitches[80], pitchValue(t, "G3"))
+	assert.Equal(t, pitches[88], pitchValue(t, "C4"))
+	assert.Equal(t, velocites[88], dsp.Float64(1))
+
+	assert.NotEqual(t, m.Play("c4 ["), nil)
+	assert.NotEqual(t, m.Play(1), nil)
+}
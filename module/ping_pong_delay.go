@@ -16,6 +16,22 @@ func init() {
 		}
 		return newPingPongDelay(dsp.DurationInt(config.Size))
 	})
+	Describe("FBPingPongDelay", Metadata{
+		Description: "Stereo delay whose feedback bounces between the channels",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "10000", Description: "maximum delay in milliseconds"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "a", Description: "left channel"},
+			{Name: "b", Description: "right channel"},
+			{Name: "duration", Unit: UnitMS, Default: 1000, Description: "delay time; limited by the size"},
+			{Name: "gain", Default: 0.5, Range: &Range{0, 1}, Description: "level of the feedback"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "left channel"},
+			{Name: "b", Description: "right channel"},
+		},
+	})
 }
 
 type pingPongDelay struct {
@@ -0,0 +1,55 @@
+package module
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadPlugins opens Go plugins (built with -buildmode=plugin) that register additional modules from their init
+// functions. Paths may be plugin files or directories of them; only files ending in .so are opened from directories.
+// Plugins must be built with the same Go version and the same version of this package as the host.
+func LoadPlugins(paths []string) error {
+	for _, path := range paths {
+		files, err := pluginFiles(path)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if err := loadPlugin(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func pluginFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return filepath.Glob(filepath.Join(path, "*.so"))
+}
+
+func loadPlugin(path string) (err error) {
+	// Register panics on duplicate names; report that as an error for this plugin instead
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("plugin %s: %v", path, r)
+		}
+	}()
+
+	before := len(registry)
+	if _, err := plugin.Open(path); err != nil {
+		return fmt.Errorf("plugin %s: %s", path, err)
+	}
+	if len(registry) == before {
+		return fmt.Errorf("plugin %s: no modules registered", path)
+	}
+	return nil
+}
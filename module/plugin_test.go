@@ -0,0 +1,34 @@
+package module
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestPluginFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-plugins")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.so", "b.so", "README"} {
+		assert.Equal(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644), nil)
+	}
+
+	files, err := pluginFiles(dir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, files, []string{filepath.Join(dir, "a.so"), filepath.Join(dir, "b.so")})
+
+	files, err = pluginFiles(filepath.Join(dir, "README"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(files), 1)
+
+	_, err = pluginFiles(filepath.Join(dir, "missing.so"))
+	assert.NotEqual(t, err, nil)
+
+	// Not a shared object
+	assert.NotEqual(t, LoadPlugins([]string{dir}), nil)
+}
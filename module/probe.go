@@ -0,0 +1,114 @@
+package module
+
+import "buddin.us/eolian/dsp"
+
+// Probe receives copies of the frames computed by an output. Frames are handed over without blocking or allocating on
+// the audio thread; if the reader falls behind, frames are dropped.
+type Probe struct {
+	frames, free chan dsp.Frame
+}
+
+// NewProbe returns a Probe that buffers up to size frames
+func NewProbe(size int) *Probe {
+	p := &Probe{
+		frames: make(chan dsp.Frame, size),
+		free:   make(chan dsp.Frame, size),
+	}
+	for i := 0; i < size; i++ {
+		p.free <- dsp.NewFrame()
+	}
+	return p
+}
+
+// Frames returns the channel of observed frames. Each frame must be handed back with Release once it has been read.
+func (p *Probe) Frames() <-chan dsp.Frame {
+	return p.frames
+}
+
+// Release returns a frame to the Probe for reuse
+func (p *Probe) Release(f dsp.Frame) {
+	select {
+	case p.free <- f:
+	default:
+	}
+}
+
+func (p *Probe) observe(f dsp.Frame) {
+	select {
+	case b := <-p.free:
+		copy(b, f)
+		select {
+		case p.frames <- b:
+		default:
+			p.free <- b
+		}
+	default:
+	}
+}
+
+// Edges receives the times of the rising edges of an output, where a rising edge is the signal crossing from zero or
+// below to above zero. Times are read from a clock when each frame is computed and are dropped if the reader falls
+// behind.
+type Edges struct {
+	now   func() int64
+	last  dsp.Float64
+	times chan int64
+}
+
+// NewEdges returns Edges that buffer up to size times. The clock must return the time of the frame being computed.
+func NewEdges(now func() int64, size int) *Edges {
+	return &Edges{now: now, times: make(chan int64, size)}
+}
+
+// Times returns the channel of edge times
+func (e *Edges) Times() <-chan int64 {
+	return e.times
+}
+
+func (e *Edges) observe(f dsp.Frame) {
+	var start int64 = -1
+	for i, v := range f {
+		if e.last <= 0 && v > 0 {
+			if start < 0 {
+				start = e.now()
+			}
+			select {
+			case e.times <- start + int64(i):
+			default:
+			}
+		}
+		e.last = v
+	}
+}
+
+// AddProbe attaches a Probe to the output. It must be called through the Executor guarding the module graph.
+func (o *Out) AddProbe(p *Probe) {
+	o.probes = append(o.probes, p)
+}
+
+// RemoveProbe detaches a Probe from the output. It must be called through the Executor guarding the module graph.
+func (o *Out) RemoveProbe(p *Probe) {
+	probes := []*Probe{}
+	for _, existing := range o.probes {
+		if existing != p {
+			probes = append(probes, existing)
+		}
+	}
+	o.probes = probes
+}
+
+// AddEdges attaches Edges to the output. It must be called through the Executor guarding the module graph.
+func (o *Out) AddEdges(e *Edges) {
+	o.edges = append(o.edges, e)
+}
+
+// RemoveEdges detaches Edges from the output. It must be called through the Executor guarding the module graph.
+func (o *Out) RemoveEdges(e *Edges) {
+	edges := []*Edges{}
+	for _, existing := range o.edges {
+		if existing != e {
+			edges = append(edges, existing)
+		}
+	}
+	o.edges = edges
+}
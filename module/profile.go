@@ -0,0 +1,114 @@
+package module
+
+import "time"
+
+// Per-module CPU accounting. Modules are timed whenever one of their outputs computes a new frame. The time spent in
+// upstream modules while doing so is subtracted, so each module is only charged for its own work.
+//
+// All of the profiler state is owned by the audio thread. Anything else must go through the Executor guarding the
+// module graph to read or change it. Modules that process their inputs on goroutines of their own (Concurrent) do so on
+// the audio thread while profiling.
+var profiler struct {
+	enabled bool
+	epoch   int
+	frame   int64
+
+	// Time spent in upstream modules, per level of nested Out.Process calls. It's allocated up front, so the audio
+	// thread only allocates for graphs nested deeper than that.
+	children []time.Duration
+}
+
+func init() {
+	profiler.children = make([]time.Duration, 0, 256)
+}
+
+// EnableProfiling turns per-module CPU accounting on or off. Turning it on discards any previously collected timings.
+func EnableProfiling(enabled bool) {
+	if enabled && !profiler.enabled {
+		profiler.epoch++
+	}
+	profiler.enabled = enabled
+}
+
+// ProfilingEnabled returns whether per-module CPU accounting is turned on
+func ProfilingEnabled() bool {
+	return profiler.enabled
+}
+
+// ProfileFrame marks the start of a new frame of audio processing
+func ProfileFrame() {
+	if profiler.enabled {
+		profiler.frame++
+	}
+}
+
+func profileEnter() time.Time {
+	profiler.children = append(profiler.children, 0)
+	return time.Now()
+}
+
+func profileExit(start time.Time) time.Duration {
+	elapsed := time.Since(start)
+	last := len(profiler.children) - 1
+	self := elapsed - profiler.children[last]
+	profiler.children = profiler.children[:last]
+	if last > 0 {
+		profiler.children[last-1] += elapsed
+	}
+	return self
+}
+
+// ProfileStats is the CPU time a module has spent processing frames
+type ProfileStats struct {
+	ID, Type  string
+	Frames    int64
+	Mean, Max time.Duration
+}
+
+type profile struct {
+	epoch        int
+	frame        int64
+	current, max time.Duration
+	total        time.Duration
+	frames       int64
+}
+
+func (p *profile) add(d time.Duration) {
+	if p.epoch != profiler.epoch {
+		*p = profile{epoch: profiler.epoch, frame: profiler.frame}
+	}
+	if p.frame != profiler.frame {
+		p.commit()
+		p.frame = profiler.frame
+	}
+	p.current += d
+}
+
+func (p *profile) commit() {
+	if p.current == 0 {
+		return
+	}
+	p.total += p.current
+	p.frames++
+	if p.current > p.max {
+		p.max = p.current
+	}
+	p.current = 0
+}
+
+// Profile returns the CPU time spent by the module since profiling was enabled
+func (io *IO) Profile() ProfileStats {
+	stats := ProfileStats{ID: io.ID(), Type: io.Type()}
+	p := io.profile
+	if p.epoch != profiler.epoch {
+		return stats
+	}
+	if p.frame != profiler.frame {
+		p.commit()
+	}
+	stats.Frames, stats.Max = p.frames, p.max
+	if p.frames > 0 {
+		stats.Mean = p.total / time.Duration(p.frames)
+	}
+	return stats
+}
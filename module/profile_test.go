@@ -0,0 +1,94 @@
+package module
+
+import (
+	"testing"
+	"time"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+type slowOutput struct {
+	d time.Duration
+}
+
+func (o slowOutput) Process(dsp.Frame) { time.Sleep(o.d) }
+
+func TestProfile(t *testing.T) {
+	EnableProfiling(true)
+	defer EnableProfiling(false)
+
+	upstream := &IO{}
+	err := upstream.Expose("Slow", []*In{}, []*Out{{Name: "output", Provider: dsp.Provide(slowOutput{2 * time.Millisecond})}})
+	assert.Equal(t, err, nil)
+
+	downstream := &IO{}
+	in := NewIn("input", dsp.Float64(0))
+	err = downstream.Expose("Fast", []*In{in}, []*Out{{Name: "output", Provider: dsp.Provide(in)}})
+	assert.Equal(t, err, nil)
+
+	upOut, err := upstream.Output("output")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, downstream.Patch("input", upOut), nil)
+
+	out, err := downstream.Output("output")
+	assert.Equal(t, err, nil)
+	sink := &In{Name: "sink", Source: out, ForceSinking: true}
+	out.addDestination(sink)
+
+	frame := dsp.NewFrame()
+	for i := 0; i < 3; i++ {
+		ProfileFrame()
+		out.Process(frame)
+	}
+	ProfileFrame()
+
+	up, down := upstream.Profile(), downstream.Profile()
+	assert.Equal(t, up.Frames, int64(3))
+	assert.Equal(t, down.Frames, int64(3))
+	assert.Equal(t, up.Mean >= 2*time.Millisecond, true)
+	assert.Equal(t, down.Mean < time.Millisecond, true)
+
+	// Re-enabling discards previous timings
+	EnableProfiling(false)
+	EnableProfiling(true)
+	assert.Equal(t, upstream.Profile().Frames, int64(0))
+}
+
+func TestProfileConcurrent(t *testing.T) {
+	EnableProfiling(true)
+	defer EnableProfiling(false)
+
+	upstream := &IO{}
+	err := upstream.Expose("Slow", []*In{}, []*Out{{Name: "output", Provider: dsp.Provide(slowOutput{time.Millisecond})}})
+	assert.Equal(t, err, nil)
+
+	c, err := newConcurrent()
+	assert.Equal(t, err, nil)
+	defer c.Close()
+
+	upOut, err := upstream.Output("output")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, c.Patch("input", upOut), nil)
+
+	out, err := c.Output("output")
+	assert.Equal(t, err, nil)
+	sink := &In{Name: "sink", Source: out, ForceSinking: true}
+	out.addDestination(sink)
+
+	// While profiling, the input is read on the calling goroutine, so upstream modules are timed there
+	frame := dsp.NewFrame()
+	for i := 0; i < 3; i++ {
+		ProfileFrame()
+		out.Process(frame)
+	}
+	ProfileFrame()
+	assert.Equal(t, c.running.Load().(bool), false)
+	assert.Equal(t, upstream.Profile().Frames, int64(3))
+	assert.Equal(t, c.Profile().Mean < time.Millisecond, true)
+
+	// It goes back to reading ahead once profiling is turned off
+	EnableProfiling(false)
+	out.Process(frame)
+	assert.Equal(t, c.running.Load().(bool), true)
+}
@@ -20,6 +20,18 @@ func init() {
 		}
 		return newQuantize(config.Size)
 	})
+	Describe("Quantize", Metadata{
+		Description: "Maps its input from 0 to 1 onto a set of pitches",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "10", Description: "number of pitches"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Range: &Range{0, 1}, Description: "position within the pitches"},
+			{Name: "transpose", Default: 1, Description: "multiplier applied to the chosen pitch"},
+			{Name: "<n>/pitch", Default: 0, Description: "pitch chosen for the nth part of the input's range"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "pitch"}},
+	})
 }
 
 type quantize struct {
@@ -0,0 +1,65 @@
+package module
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync/atomic"
+
+	"buddin.us/eolian/dsp"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Modules that make random decisions each draw from their own generator. A generator's sequence depends only on the
+// seed and the module's ID, so a patch behaves the same way between runs regardless of the order modules are pulled in
+// or which goroutine pulls them.
+
+var globalSeed int64 = 1
+
+// SetSeed sets the seed that module random number generators are derived from. It affects modules created afterwards.
+func SetSeed(seed int64) {
+	atomic.StoreInt64(&globalSeed, seed)
+}
+
+// Seed returns the seed that module random number generators are derived from
+func Seed() int64 {
+	return atomic.LoadInt64(&globalSeed)
+}
+
+// seedMetadata describes the "seed" config key decoded by seedConfig
+var seedMetadata = ConfigMetadata{
+	Name: "seed", Type: "int", Default: "0", Description: "random seed; derived from the global seed when 0",
+}
+
+// seedConfig decodes the optional "seed" config key
+func seedConfig(c Config) (int64, error) {
+	var config struct {
+		Seed int64
+	}
+	err := mapstructure.Decode(c, &config)
+	return config.Seed, err
+}
+
+// newRand returns a generator for a module. A non-zero seed is used as-is, so modules given the same seed produce the
+// same sequence; otherwise the seed is derived from the global seed and the module's ID.
+func newRand(id string, seed int64) *randSource {
+	if seed == 0 {
+		h := fnv.New64a()
+		h.Write([]byte(id))
+		seed = Seed() ^ int64(h.Sum64())
+	}
+	return &randSource{rand.New(rand.NewSource(seed))}
+}
+
+type randSource struct {
+	*rand.Rand
+}
+
+// Value returns a random value in [0, 1)
+func (r *randSource) Value() dsp.Float64 {
+	return dsp.Float64(r.Float64())
+}
+
+// Range returns a random value in [min, max)
+func (r *randSource) Range(min, max dsp.Float64) dsp.Float64 {
+	return r.Value()*(max-min) + min
+}
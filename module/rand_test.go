@@ -0,0 +1,37 @@
+package module
+
+import (
+	"testing"
+
+	"buddin.us/eolian/dsp"
+	"gopkg.in/go-playground/assert.v1"
+)
+
+func TestRandDerivedFromSeedAndID(t *testing.T) {
+	defer SetSeed(Seed())
+
+	SetSeed(42)
+	first := newRand("Noise:1", 0).Value()
+	assert.Equal(t, newRand("Noise:1", 0).Value(), first)
+	assert.NotEqual(t, newRand("Noise:2", 0).Value(), first)
+
+	SetSeed(43)
+	assert.NotEqual(t, newRand("Noise:1", 0).Value(), first)
+}
+
+func TestRandConfigSeed(t *testing.T) {
+	render := func() dsp.Frame {
+		p, err := Lookup("Noise")
+		assert.Equal(t, err, nil)
+		m, err := p(Config{"seed": 7})
+		assert.Equal(t, err, nil)
+		out, err := m.Output("output")
+		assert.Equal(t, err, nil)
+		frame := dsp.NewFrame()
+		out.Process(frame)
+		return frame
+	}
+	a, b := render(), render()
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a[0], a[1])
+}
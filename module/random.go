@@ -3,7 +3,29 @@ package module
 import "buddin.us/eolian/dsp"
 
 func init() {
-	Register("Random", func(Config) (Patcher, error) { return newRandom() })
+	Register("Random", func(c Config) (Patcher, error) {
+		seed, err := seedConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		return newRandom(seed)
+	})
+	Describe("Random", Metadata{
+		Description: "Picks a random value between min and max on each clock pulse",
+		Config:      []ConfigMetadata{seedMetadata},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: -1, Description: "clock; a new value may be picked on each pulse"},
+			{Name: "probability", Default: 1, Range: &Range{0, 1}, Description: "chance of picking a new value on a pulse"},
+			{Name: "smoothness", Default: 1, Description: "values averaged in the smooth output, one per sample or control period"},
+			{Name: "min", Default: 0, Description: "lowest value"},
+			{Name: "max", Default: 1, Description: "highest value"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "stepped", Description: "the value picked last"},
+			{Name: "smooth", Description: "the stepped output, averaged"},
+		},
+		ControlRate: true,
+	})
 }
 
 type random struct {
@@ -13,9 +35,10 @@ type random struct {
 	average                                  dsp.RollingAverage
 
 	captured, lastClock dsp.Float64
+	rand                *randSource
 }
 
-func newRandom() (*random, error) {
+func newRandom(seed int64) (*random, error) {
 	m := &random{
 		clock:       NewInBuffer("clock", dsp.Float64(-1)),
 		smoothness:  NewInBuffer("smoothness", dsp.Float64(1)),
@@ -34,6 +57,7 @@ func newRandom() (*random, error) {
 			{Name: "smooth", Provider: provideCopyOut(m, &m.smooth)},
 		},
 	)
+	m.rand = newRand(m.ID(), seed)
 	return m, err
 }
 
@@ -46,8 +70,8 @@ func (r *random) Process(out dsp.Frame) {
 		max := r.max.ProcessFrame()
 
 		for i := range out {
-			if dsp.Rand() <= probability[i] && r.lastClock < 0 && clock[i] > 0 {
-				r.captured = dsp.RandRange(min[i], max[i])
+			if r.rand.Value() <= probability[i] && r.lastClock < 0 && clock[i] > 0 {
+				r.captured = r.rand.Range(min[i], max[i])
 			}
 			r.lastClock = clock[i]
 
@@ -1,13 +1,31 @@
 package module
 
-import (
-	"math/rand"
-
-	"buddin.us/eolian/dsp"
-)
+import "buddin.us/eolian/dsp"
 
 func init() {
-	Register("RandomSeries", func(Config) (Patcher, error) { return newRandomSeries() })
+	Register("RandomSeries", func(c Config) (Patcher, error) {
+		seed, err := seedConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		return newRandomSeries(seed)
+	})
+	Describe("RandomSeries", Metadata{
+		Description: "Loops over a series of random values and gates, picking a new series on each trigger",
+		Config:      []ConfigMetadata{seedMetadata},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "clock advancing through the series"},
+			{Name: "size", Default: 8, Range: &Range{1, randomSeriesMax}, Description: "length of the series"},
+			{Name: "trigger", Default: 0, Description: "trigger picking a new series"},
+			{Name: "min", Default: 0, Description: "lowest value"},
+			{Name: "max", Default: 1, Description: "highest value"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "value", Description: "value of the current step"},
+			{Name: "gate", Description: "the clock on the steps that have a gate"},
+		},
+		ControlRate: true,
+	})
 }
 
 const randomSeriesMax = 128
@@ -18,11 +36,12 @@ type randomSeries struct {
 	idx                            int
 	memory, gateMemory             []dsp.Float64
 	lastTrigger, lastClock         dsp.Float64
+	rand                           *randSource
 
 	valueOut, gateOut dsp.Frame
 }
 
-func newRandomSeries() (*randomSeries, error) {
+func newRandomSeries(seed int64) (*randomSeries, error) {
 	m := &randomSeries{
 		clock:       NewInBuffer("clock", dsp.Float64(0)),
 		size:        NewInBuffer("size", dsp.Float64(8)),
@@ -37,7 +56,7 @@ func newRandomSeries() (*randomSeries, error) {
 		lastClock:   -1,
 	}
 
-	return m, m.Expose(
+	err := m.Expose(
 		"RandomSeries",
 		[]*In{m.size, m.trigger, m.clock, m.min, m.max},
 		[]*Out{
@@ -45,6 +64,8 @@ func newRandomSeries() (*randomSeries, error) {
 			{Name: "gate", Provider: provideCopyOut(m, &m.gateOut)},
 		},
 	)
+	m.rand = newRand(m.ID(), seed)
+	return m, err
 }
 
 func (s *randomSeries) Process(out dsp.Frame) {
@@ -67,8 +88,8 @@ func (s *randomSeries) Process(out dsp.Frame) {
 			}
 			if s.lastTrigger < 0 && trigger[i] > 0 {
 				for j := 0; j < int(size); j++ {
-					s.memory[j] = dsp.Rand()*(max[j]-min[j]) + min[j]
-					if rand.Float32() > 0.25 {
+					s.memory[j] = s.rand.Range(min[j], max[j])
+					if s.rand.Value() > 0.25 {
 						s.gateMemory[j] = 1
 					} else {
 						s.gateMemory[j] = -1
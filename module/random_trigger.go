@@ -3,16 +3,33 @@ package module
 import "buddin.us/eolian/dsp"
 
 func init() {
-	Register("RandomTrigger", func(Config) (Patcher, error) { return newRandomTrigger() })
+	Register("RandomTrigger", func(c Config) (Patcher, error) {
+		seed, err := seedConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		return newRandomTrigger(seed)
+	})
+	Describe("RandomTrigger", Metadata{
+		Description: "Passes on each pulse of a clock by chance",
+		Config:      []ConfigMetadata{seedMetadata},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: -1, Description: "clock to pass on"},
+			{Name: "probability", Default: 1, Range: &Range{0, 1}, Description: "chance of passing on a pulse"},
+		},
+		Outputs:     []PortMetadata{{Name: "output", Description: "the pulses passed on"}},
+		ControlRate: true,
+	})
 }
 
 type randomTrigger struct {
 	IO
 	clock, probability *In
 	lastClock          dsp.Float64
+	rand               *randSource
 }
 
-func newRandomTrigger() (*randomTrigger, error) {
+func newRandomTrigger(seed int64) (*randomTrigger, error) {
 	m := &randomTrigger{
 		clock:       NewInBuffer("clock", dsp.Float64(-1)),
 		probability: NewInBuffer("probability", dsp.Float64(1)),
@@ -23,6 +40,7 @@ func newRandomTrigger() (*randomTrigger, error) {
 		[]*In{m.clock, m.probability},
 		[]*Out{{Name: "output", Provider: dsp.Provide(m)}},
 	)
+	m.rand = newRand(m.ID(), seed)
 	return m, err
 }
 
@@ -30,7 +48,7 @@ func (r *randomTrigger) Process(out dsp.Frame) {
 	clock := r.clock.ProcessFrame()
 	probability := r.probability.ProcessFrame()
 	for i := range out {
-		if dsp.Rand() <= probability[i] && r.lastClock < 0 && clock[i] > 0 {
+		if r.rand.Value() <= probability[i] && r.lastClock < 0 && clock[i] > 0 {
 			out[i] = 1
 		} else {
 			out[i] = -1
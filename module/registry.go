@@ -28,7 +28,20 @@ func Register(name string, fn func(Config) (Patcher, error)) {
 	if _, ok := registry[name]; ok {
 		panic(fmt.Sprintf("%s already registered as a module", name))
 	}
-	registry[name] = fn
+	registry[name] = func(c Config) (Patcher, error) {
+		p, err := fn(c)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyControlRate(name, p, c); err != nil {
+			p.Close()
+			return nil, err
+		}
+		if r, ok := p.(configRecorder); ok {
+			r.recordConfig(c)
+		}
+		return p, nil
+	}
 }
 
 // RegisteredTypes returns a list of all registered module types
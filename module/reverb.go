@@ -22,6 +22,19 @@ func init() {
 		}
 		return newReverb(config.Feedback, config.Allpass)
 	})
+	Describe("Reverb", Metadata{
+		Description: "Schroeder reverb: parallel feedback delays followed by allpass filters. Its output is fully wet",
+		Config: []ConfigMetadata{
+			{Name: "feedback", Type: "[]int", Default: "4003, 3001, 2004, 1002, 3027", Description: "sizes of the feedback delays in milliseconds"},
+			{Name: "allpass", Type: "[]int", Default: "573, 331, 178", Description: "sizes of the allpass filters in milliseconds"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to reverberate"},
+			{Name: "feedback", Default: 0.84, Range: &Range{0, 1}, Description: "feedback of the delays; length of the tail"},
+			{Name: "gain", Default: 0.5, Range: &Range{0, 1}, Description: "gain of the allpass filters; density of the tail"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "reverberated signal"}},
+	})
 }
 
 type reverb struct {
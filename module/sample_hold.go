@@ -4,6 +4,14 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("SampleHold", func(Config) (Patcher, error) { return newSampleHold() })
+	Describe("SampleHold", Metadata{
+		Description: "Samples its input on each trigger and holds the value until the next",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to sample"},
+			{Name: "trigger", Default: 0, Description: "trigger taking a sample"},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "the sample held"}},
+	})
 }
 
 type sampleHold struct {
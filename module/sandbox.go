@@ -0,0 +1,74 @@
+package module
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"buddin.us/eolian/lua/instrument"
+)
+
+var (
+	// sandboxDir is the only directory modules may read or write files in. It's empty unless modules are sandboxed.
+	sandboxDir string
+	// sandboxLimits bounds the top level of LuaProcessor scripts, which runs when the module is created
+	sandboxLimits instrument.Limits
+)
+
+// SetSandbox confines the files modules read and write to a directory and keeps the scripts of LuaProcessor modules
+// away from the rest of the system, limiting the work their top level may do. An empty directory lifts the
+// restrictions.
+func SetSandbox(dir string, limits instrument.Limits) error {
+	if dir == "" {
+		sandboxDir, sandboxLimits = "", instrument.Limits{}
+		return nil
+	}
+	resolved, err := resolvePath(dir)
+	if err != nil {
+		return err
+	}
+	sandboxDir, sandboxLimits = resolved, limits
+	return nil
+}
+
+// Sandboxed returns whether modules are confined to a directory
+func Sandboxed() bool {
+	return sandboxDir != ""
+}
+
+// CheckPath returns an error if modules are sandboxed and the path is outside of the sandbox's directory. Symbolic
+// links are followed, so they can't lead out of it.
+func CheckPath(path string) error {
+	if sandboxDir == "" {
+		return nil
+	}
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(sandboxDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside of the sandbox %s", path, sandboxDir)
+	}
+	return nil
+}
+
+// resolvePath returns the absolute path with symbolic links followed. Files that don't exist yet are resolved through
+// their directory.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(abs)), nil
+}
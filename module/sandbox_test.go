@@ -0,0 +1,44 @@
+package module
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+
+	"buddin.us/eolian/lua/instrument"
+)
+
+func TestCheckPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-sandbox")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	assert.Equal(t, CheckPath("/etc/passwd"), nil)
+
+	assert.Equal(t, SetSandbox(dir, instrument.Limits{}), nil)
+	defer SetSandbox("", instrument.Limits{})
+	assert.Equal(t, Sandboxed(), true)
+
+	assert.Equal(t, os.Mkdir(filepath.Join(dir, "sub"), 0755), nil)
+	assert.Equal(t, os.Symlink("/etc", filepath.Join(dir, "link")), nil)
+
+	var tests = []struct {
+		path string
+		ok   bool
+	}{
+		{filepath.Join(dir, "rack.lua"), true},
+		{filepath.Join(dir, "sub", "sample.wav"), true},
+		{filepath.Join(dir, "sub", "..", "snapshot.json"), true},
+		{dir, true},
+		{filepath.Join(dir, "..", "rack.lua"), false},
+		{filepath.Join(dir, "link", "passwd"), false},
+		{"/etc/passwd", false},
+	}
+	for _, test := range tests {
+		err := CheckPath(test.path)
+		assert.Equal(t, err == nil, test.ok)
+	}
+}
@@ -8,6 +8,22 @@ import (
 
 func init() {
 	Register("Shape", func(Config) (Patcher, error) { return newShape() })
+	Describe("Shape", Metadata{
+		Description: "Function generator rising and falling once per gate or trigger, or cycling as an LFO",
+		Inputs: []PortMetadata{
+			{Name: "gate", Default: 0, Description: "gate; the shape rises and holds at the top while high"},
+			{Name: "trigger", Default: 0, Description: "trigger; the shape rises and falls"},
+			{Name: "rise", Unit: UnitMS, Default: 1, Description: "time to rise to 1"},
+			{Name: "fall", Unit: UnitMS, Default: 1, Description: "time to fall to 0"},
+			{Name: "cycle", Default: 0, Description: "rise again at the end of each fall while above zero"},
+			{Name: "ratio", Default: 0.01, Range: &Range{0.0001, 100}, Description: "curvature of the segments; low values are exponential"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "shape"},
+			{Name: "endcycle", Description: "trigger when the shape finishes falling"},
+		},
+		ControlRate: true,
+	})
 }
 
 type shape struct {
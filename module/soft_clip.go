@@ -13,6 +13,14 @@ func init() {
 		}
 		return newSoftClip()
 	})
+	Describe("SoftClip", Metadata{
+		Description: "Soft clipper rounding off its input above 0.5",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to clip"},
+			{Name: "gain", Default: 1, Description: "level of the clipped part"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type softClip struct {
@@ -2,7 +2,6 @@ package module
 
 import (
 	"fmt"
-	"math/rand"
 
 	"buddin.us/eolian/dsp"
 
@@ -13,6 +12,7 @@ func init() {
 	Register("StageSequence", func(c Config) (Patcher, error) {
 		var config struct {
 			Stages int
+			Seed   int64
 		}
 		if err := mapstructure.Decode(c, &config); err != nil {
 			return nil, err
@@ -20,7 +20,34 @@ func init() {
 		if config.Stages == 0 {
 			config.Stages = 8
 		}
-		return newStageSequence(config.Stages)
+		return newStageSequence(config.Stages, config.Seed)
+	})
+	Describe("StageSequence", Metadata{
+		Description: "Pitch sequencer whose stages each last a number of clock pulses, with a gate mode per stage",
+		Config: []ConfigMetadata{
+			{Name: "stages", Type: "int", Default: "8", Description: "number of stages"},
+			seedMetadata,
+		},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "clock advancing the sequence"},
+			{Name: "reset", Default: 0, Description: "trigger returning to the first stage"},
+			{Name: "mode", Default: 0, Range: &Range{0, 2}, Description: "order of the stages: 0 sequential, 1 ping-pong, 2 random"},
+			{Name: "transpose", Default: 1, Description: "multiplier applied to the pitches"},
+			{Name: "glide", Unit: UnitMS, Default: 0, Description: "glide time of the stages that glide"},
+			{Name: "<n>/pitch", Default: 0, Description: "pitch of the stage"},
+			{Name: "<n>/pulses", Default: 1, Description: "clock pulses the stage lasts"},
+			{Name: "<n>/mode", Default: 1, Range: &Range{0, 3}, Description: "gate of the stage: 0 rest, 1 first pulse, 2 every pulse, 3 held"},
+			{Name: "<n>/glide", Default: 0, Description: "glide into the stage's pitch when above zero"},
+			{Name: "<n>/velocity", Default: 1, Description: "velocity of the stage"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "gate", Description: "gate of the current stage"},
+			{Name: "pitch", Description: "pitch of the current stage"},
+			{Name: "velocity", Description: "velocity of the current stage, smoothed"},
+			{Name: "endstage", Description: "trigger when the sequence moves to another stage"},
+			{Name: "sync", Description: "not driven yet; always 0"},
+		},
+		ControlRate: true,
 	})
 }
 
@@ -43,6 +70,7 @@ type stageSequence struct {
 	pulse, stage, lastStage              int
 	pong                                 bool
 	slew                                 *slew
+	rand                                 *randSource
 
 	lastClock, lastReset, rollingVelocity dsp.Float64
 
@@ -57,7 +85,7 @@ type stage struct {
 	pitch, pulses, gateMode, glide, velocity *In
 }
 
-func newStageSequence(stages int) (*stageSequence, error) {
+func newStageSequence(stages int, seed int64) (*stageSequence, error) {
 	m := &stageSequence{
 		clock:       NewInBuffer("clock", dsp.Float64(0)),
 		transpose:   NewInBuffer("transpose", dsp.Float64(1)),
@@ -95,7 +123,7 @@ func newStageSequence(stages int) (*stageSequence, error) {
 			m.stages[i].velocity)
 	}
 
-	return m, m.Expose(
+	err := m.Expose(
 		"StageSequence",
 		inputs,
 		[]*Out{
@@ -106,6 +134,8 @@ func newStageSequence(stages int) (*stageSequence, error) {
 			{Name: "sync", Provider: provideCopyOut(m, &m.syncOut)},
 		},
 	)
+	m.rand = newRand(m.ID(), seed)
+	return m, err
 }
 
 func (s *stageSequence) Process(out dsp.Frame) {
@@ -152,7 +182,7 @@ func (s *stageSequence) Process(out dsp.Frame) {
 							s.pong = false
 						}
 					case patternModeRandom:
-						s.stage = rand.Intn(len(s.stages))
+						s.stage = s.rand.Intn(len(s.stages))
 						s.pong = false
 					}
 				}
@@ -0,0 +1,136 @@
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"buddin.us/eolian/dsp"
+)
+
+// Units of constant input values
+const (
+	UnitHz    = "Hz"
+	UnitMS    = "MS"
+	UnitBPM   = "BPM"
+	UnitPitch = "Pitch"
+)
+
+// InputState describes what an input is reading from: either a constant value (along with its unit) or the output of
+// another module. It's used to save and restore the state of a rack.
+type InputState struct {
+	Value  *float64 `json:"value,omitempty"`
+	Pitch  string   `json:"pitch,omitempty"`
+	Unit   string   `json:"unit,omitempty"`
+	Module string   `json:"module,omitempty"`
+	Output string   `json:"output,omitempty"`
+}
+
+// IsConnection returns whether the input is patched to another module's output
+func (s InputState) IsConnection() bool {
+	return s.Module != ""
+}
+
+// Constant returns the constant value described by the state in a form that can be patched
+func (s InputState) Constant() (interface{}, error) {
+	if s.Unit == UnitPitch {
+		return dsp.ParsePitch(s.Pitch)
+	}
+	if s.Value == nil {
+		return nil, fmt.Errorf("missing value")
+	}
+	v := *s.Value
+	switch s.Unit {
+	case "":
+		return dsp.Float64(v), nil
+	case UnitHz:
+		return dsp.Frequency(v), nil
+	case UnitMS:
+		return dsp.Duration(v), nil
+	case UnitBPM:
+		return dsp.BPM(v), nil
+	default:
+		return nil, fmt.Errorf(`unknown unit "%s"`, s.Unit)
+	}
+}
+
+// State returns the current state of the input. It returns false if the source can't be described; for instance an
+// internal Processor that isn't a constant value or another module's output.
+func (i *In) State() (InputState, bool) {
+	return processorState(i.current())
+}
+
+// IsDefault returns whether the input still reads from the source it was created with
+func (i *In) IsDefault() bool {
+	current, ok := i.State()
+	if !ok {
+		return false
+	}
+	initial, ok := processorState(i.initial)
+	if !ok {
+		return false
+	}
+	return current.equal(initial)
+}
+
+// Upstream returns the module whose output the input reads from, along with the name of that output. It returns false
+// if the input isn't patched to another module.
+func (i *In) Upstream() (*IO, string, bool) {
+	if out, ok := i.current().(*Out); ok {
+		return out.owner, out.Name, true
+	}
+	return nil, "", false
+}
+
+func processorState(p dsp.Processor) (InputState, bool) {
+	var s InputState
+	switch v := p.(type) {
+	case *Out:
+		s.Module, s.Output = v.owner.ID(), v.Name
+	case dsp.Hz:
+		s.Value, s.Unit = &v.Raw, UnitHz
+	case dsp.MS:
+		s.Value, s.Unit = &v.Raw, UnitMS
+	case dsp.BeatsPerMin:
+		s.Value, s.Unit = &v.Raw, UnitBPM
+	case dsp.Pitch:
+		s.Pitch, s.Unit = v.Raw, UnitPitch
+	case dsp.Valuer:
+		value := float64(v.Value())
+		s.Value = &value
+	default:
+		return s, false
+	}
+	return s, true
+}
+
+func (s InputState) equal(o InputState) bool {
+	if (s.Value == nil) != (o.Value == nil) || (s.Value != nil && *s.Value != *o.Value) {
+		return false
+	}
+	return s.Pitch == o.Pitch && s.Unit == o.Unit && s.Module == o.Module && s.Output == o.Output
+}
+
+// Snapshot is the saved state of every module in a rack. Modules are keyed by their path within the rack.
+type Snapshot struct {
+	Modules map[string]ModuleSnapshot `json:"modules"`
+}
+
+// ModuleSnapshot is the saved state of a single module's inputs
+type ModuleSnapshot struct {
+	Type   string                `json:"type"`
+	Inputs map[string]InputState `json:"inputs"`
+}
+
+// ReadSnapshot reads a Snapshot from a JSON file
+func ReadSnapshot(path string) (Snapshot, error) {
+	var s Snapshot
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return s, fmt.Errorf("decode %s: %s", path, err)
+	}
+	return s, nil
+}
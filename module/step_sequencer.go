@@ -2,7 +2,6 @@ package module
 
 import (
 	"fmt"
-	"math/rand"
 
 	"buddin.us/eolian/dsp"
 
@@ -15,6 +14,7 @@ func init() {
 	Register("StepSequence", func(c Config) (Patcher, error) {
 		var config struct {
 			Steps, Layers int
+			Seed          int64
 		}
 		if err := mapstructure.Decode(c, &config); err != nil {
 			return nil, err
@@ -27,7 +27,28 @@ func init() {
 		if config.Steps == 0 {
 			config.Steps = 8
 		}
-		return newStepSequence(config.Steps, config.Layers)
+		return newStepSequence(config.Steps, config.Layers, config.Seed)
+	})
+	Describe("StepSequence", Metadata{
+		Description: "Step sequencer with layers of pitches, named a, b, c and so on, sharing its steps",
+		Config: []ConfigMetadata{
+			{Name: "steps", Type: "int", Default: "8", Description: "number of steps"},
+			{Name: "layers", Type: "int", Default: "3", Description: "number of layers; at most 24"},
+			seedMetadata,
+		},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "clock advancing the sequence"},
+			{Name: "reset", Default: 0, Description: "trigger returning to the first step on the next pulse"},
+			{Name: "mode", Default: 0, Range: &Range{0, 2}, Description: "order of the steps: 0 sequential, 1 ping-pong, 2 random"},
+			{Name: "<layer>/<n>/pitch", Default: 0, Description: "pitch of the step in the layer"},
+			{Name: "<n>/enabled", Default: 1, Description: "the sequence starts over after the step while it's at or below zero"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "gate", Description: "the clock, gated by every step"},
+			{Name: "<layer>/pitch", Description: "pitch of the current step in the layer"},
+			{Name: "<n>/gate", Description: "the clock while the step is current"},
+		},
+		ControlRate: true,
 	})
 }
 
@@ -39,6 +60,7 @@ type stepSequence struct {
 	pitches                               [][]*In
 	step, lastStep, layerCount, stepCount int
 	pong                                  bool
+	rand                                  *randSource
 
 	lastClock, lastReset dsp.Float64
 
@@ -46,7 +68,7 @@ type stepSequence struct {
 	allGateOut           dsp.Frame
 }
 
-func newStepSequence(steps, layers int) (*stepSequence, error) {
+func newStepSequence(steps, layers int, seed int64) (*stepSequence, error) {
 	m := &stepSequence{
 		clock:      NewInBuffer("clock", dsp.Float64(0)),
 		reset:      NewInBuffer("reset", dsp.Float64(0)),
@@ -96,7 +118,9 @@ func newStepSequence(steps, layers int) (*stepSequence, error) {
 		})
 	}
 
-	return m, m.Expose("StepSequence", inputs, outputs)
+	err := m.Expose("StepSequence", inputs, outputs)
+	m.rand = newRand(m.ID(), seed)
+	return m, err
 }
 
 func (s *stepSequence) Process(out dsp.Frame) {
@@ -175,7 +199,7 @@ func (s *stepSequence) advance(mode, reset, enabled dsp.Float64) {
 		if s.lastReset < 0 && reset > 0 || enabled <= 0 {
 			s.step = 0
 		} else {
-			s.step = rand.Intn(s.stepCount)
+			s.step = s.rand.Intn(s.stepCount)
 		}
 	}
 }
@@ -6,6 +6,32 @@ import (
 
 func init() {
 	Register("Survey", func(c Config) (Patcher, error) { return newSurvey() })
+	Describe("Survey", Metadata{
+		Description: "Bundle of utilities (crossfade, min/max logic, slew and folding) whose unpatched inputs " +
+			"fall back to shapes derived from the survey input, so a single knob sweeps all of them",
+		Inputs: []PortMetadata{
+			{Name: "survey", Default: 0, Range: &Range{-1, 1}, Description: "voltage the unpatched inputs are derived from"},
+			{Name: "a", Default: 0, Description: "first signal to crossfade"},
+			{Name: "b", Default: 0, Description: "second signal to crossfade"},
+			{Name: "fade", Default: 0, Range: &Range{-1, 1}, Description: "crossfade between a and b; survey while unpatched"},
+			{Name: "offset", Default: 0, Description: "added to the a and b outputs"},
+			{Name: "or1", Default: 0, Description: "first signal to take the maximum of"},
+			{Name: "or2", Default: 0, Description: "second signal to take the maximum of"},
+			{Name: "and1", Default: 0, Description: "first signal to take the minimum of"},
+			{Name: "and2", Default: 0, Description: "second signal to take the minimum of"},
+			{Name: "slope", Default: 0, Description: "scales the follower's response time"},
+			{Name: "crease", Default: 0, Range: &Range{-1, 1}, Description: "signal to fold around zero"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "crossfade of a into b, plus the offset"},
+			{Name: "b", Description: "crossfade of b into a, plus the offset"},
+			{Name: "or", Description: "maximum of or1 and or2"},
+			{Name: "and", Description: "minimum of and1 and and2"},
+			{Name: "slope", Description: "magnitude of the slope input"},
+			{Name: "crease", Description: "the crease input folded around zero"},
+			{Name: "follow", Description: "a plus b, slewed"},
+		},
+	})
 }
 
 var followDuration = dsp.Duration(300).Value()
@@ -21,6 +21,18 @@ func init() {
 		}
 		return newSeqSwitch(config.Size)
 	})
+	Describe("Switch", Metadata{
+		Description: "Sequential switch passing its inputs through in turn, moving to the next on each clock pulse",
+		Config: []ConfigMetadata{
+			{Name: "size", Type: "int", Default: "4", Description: "number of inputs"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "clock moving to the next input"},
+			{Name: "reset", Default: 0, Description: "trigger returning to the first input"},
+			{Name: "<n>/input", Default: 0},
+		},
+		Outputs: []PortMetadata{{Name: "output", Description: "the current input"}},
+	})
 }
 
 type seqSwitch struct {
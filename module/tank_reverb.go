@@ -22,6 +22,25 @@ func init() {
 		}
 		return newTankReverb(config.PolesA, config.PolesB)
 	})
+	Describe("TankReverb", Metadata{
+		Description: "Stereo plate reverb after Dattorro's tank design",
+		Config: []ConfigMetadata{
+			{Name: "polesA", Type: "int", Default: "4", Description: "poles of the lowpass filter in the a side of the tank"},
+			{Name: "polesB", Type: "int", Default: "4", Description: "poles of the lowpass filter in the b side of the tank"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "a", Description: "left channel"},
+			{Name: "b", Description: "right channel"},
+			{Name: "defuse", Default: 0.5, Range: &Range{0.4, 0.6}, Description: "diffusion of the input"},
+			{Name: "cutoff", Unit: UnitHz, Default: 800, Range: &Range{20, 20000}, Description: "cutoff frequency of the tank's filters"},
+			{Name: "decay", Default: 0.5, Range: &Range{0, 0.9}, Description: "length of the tail"},
+			{Name: "bias", Default: 0, Range: &Range{-1, 1}, Description: "mix of dry (-1) and wet (1) signal; 0 is both"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "a", Description: "left channel"},
+			{Name: "b", Description: "right channel"},
+		},
+	})
 }
 
 type tankReverb struct {
@@ -4,6 +4,17 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Tap", func(Config) (Patcher, error) { return newTap() })
+	Describe("Tap", Metadata{
+		Description: "Insert point: sends its input out of the tap output and takes it back from the tap input",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to pass through"},
+			{Name: "tap", Default: 0, Description: "return of the tap; the input is passed on while unpatched"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "the input, unchanged"},
+			{Name: "tap", Description: "the tap input, or the input while it's unpatched"},
+		},
+	})
 }
 
 type tap struct {
@@ -20,6 +20,31 @@ func init() {
 		}
 		return newTape(config.Max, config.File)
 	})
+	Describe("Tape", Metadata{
+		Description: "Tape machine with recording, overdubbing, variable speed and splicing",
+		Config: []ConfigMetadata{
+			{Name: "max", Type: "int", Default: "10", Description: "length of the tape in seconds"},
+			{Name: "file", Type: "string", Description: "WAV file loaded onto the tape at startup"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to record"},
+			{Name: "speed", Default: 1, Description: "playback speed; negative values play in reverse"},
+			{Name: "play", Default: 1, Range: &Range{0, 1}, Description: "gate; play while high"},
+			{Name: "record", Default: 0, Range: &Range{0, 1}, Description: "gate; record while high"},
+			{Name: "reset", Default: 0, Range: &Range{0, 1}, Description: "trigger; rewind to the start of the splice"},
+			{Name: "bias", Default: 0, Range: &Range{-1, 1}, Description: "balance between the input (-1) and the tape (1)"},
+			{Name: "splice", Default: 0, Range: &Range{0, 1}, Description: "trigger; add a splice marker at the playhead"},
+			{Name: "organize", Default: 0, Range: &Range{0, 1}, Description: "selects the splice to play; 0 is the first and 1 is the last"},
+			{Name: "unsplice", Default: 0, Range: &Range{0, 1}, Description: "trigger; remove the current splice marker"},
+			{Name: "zoom", Default: 0, Range: &Range{0, 1}, Description: "loops grains of the splice; higher values make smaller grains"},
+			{Name: "slide", Default: 0, Range: &Range{0, 1}, Description: "moves the grain within the splice"},
+			{Name: "layers", Default: 1, Range: &Range{0, 8}, Description: "number of overlapping grains while zoomed"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "output", Description: "tape playback"},
+			{Name: "endsplice", Description: "trigger at the end of each splice"},
+		},
+	})
 }
 
 const tapeOversample = 20
@@ -40,6 +65,9 @@ type tape struct {
 func newTape(max int, file string) (*tape, error) {
 	var w *wav.Wav
 	if file != "" {
+		if err := CheckPath(file); err != nil {
+			return nil, err
+		}
 		var err error
 		w, err = wav.Open(file)
 		if err != nil {
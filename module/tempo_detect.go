@@ -4,6 +4,11 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("TempoDetect", func(Config) (Patcher, error) { return newTempoDetect() })
+	Describe("TempoDetect", Metadata{
+		Description: "Measures the time between taps and outputs it as a frequency, e.g. for a Clock's tempo",
+		Inputs:      []PortMetadata{{Name: "tap", Default: 0, Description: "pulses to measure"}},
+		Outputs:     []PortMetadata{{Name: "output", Description: "rate of the taps"}},
+	})
 }
 
 type tempoDetect struct {
@@ -4,6 +4,11 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("Toggle", func(Config) (Patcher, error) { return newToggle() })
+	Describe("Toggle", Metadata{
+		Description: "Flips between high (1) and low (-1) on each trigger",
+		Inputs:      []PortMetadata{{Name: "trigger", Default: 0}},
+		Outputs:     []PortMetadata{{Name: "output"}},
+	})
 }
 
 type toggle struct {
@@ -4,6 +4,14 @@ import "buddin.us/eolian/dsp"
 
 func init() {
 	Register("TrackHold", func(Config) (Patcher, error) { return newTrackHold() })
+	Describe("TrackHold", Metadata{
+		Description: "Follows its input, holding the last value while hang is high",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to track"},
+			{Name: "hang", Default: 0, Description: "gate; the value is held while high"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type trackHold struct {
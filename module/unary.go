@@ -10,6 +10,21 @@ func init() {
 	Register("Hz", func(Config) (Patcher, error) { return newUnary("Hz", hz, 0) })
 	Register("MS", func(Config) (Patcher, error) { return newUnary("MS", ms, 0) })
 	Register("BPM", func(Config) (Patcher, error) { return newUnary("BPM", bpm, 0) })
+
+	describeUnary("Round", "Rounds its input to the nearest integer")
+	describeUnary("Floor", "Rounds its input down to an integer")
+	describeUnary("Ceil", "Rounds its input up to an integer")
+	describeUnary("Hz", "Converts a frequency in hertz to the internal representation of frequencies")
+	describeUnary("MS", "Converts a duration in milliseconds to the internal representation of durations")
+	describeUnary("BPM", "Converts a tempo in beats per minute to the internal representation of frequencies")
+}
+
+func describeUnary(name, description string) {
+	Describe(name, Metadata{
+		Description: description,
+		Inputs:      []PortMetadata{{Name: "input"}},
+		Outputs:     []PortMetadata{{Name: "output"}},
+	})
 }
 
 type unary struct {
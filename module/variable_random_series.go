@@ -3,7 +3,29 @@ package module
 import "buddin.us/eolian/dsp"
 
 func init() {
-	Register("VariableRandomSeries", func(Config) (Patcher, error) { return newVariableRandomSeries() })
+	Register("VariableRandomSeries", func(c Config) (Patcher, error) {
+		seed, err := seedConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		return newVariableRandomSeries(seed)
+	})
+	Describe("VariableRandomSeries", Metadata{
+		Description: "Loops over a series of random values and gates, replacing steps by chance as it goes",
+		Config:      []ConfigMetadata{seedMetadata},
+		Inputs: []PortMetadata{
+			{Name: "clock", Default: 0, Description: "clock advancing through the series"},
+			{Name: "size", Default: 8, Range: &Range{1, randomSeriesMax}, Description: "length of the series"},
+			{Name: "random", Default: 0, Range: &Range{0, 1}, Description: "chance of replacing each step as it's passed; 0 locks the series"},
+			{Name: "min", Default: 0, Description: "lowest value"},
+			{Name: "max", Default: 1, Description: "highest value"},
+		},
+		Outputs: []PortMetadata{
+			{Name: "value", Description: "value of the current step"},
+			{Name: "gate", Description: "gate of the current step"},
+		},
+		ControlRate: true,
+	})
 }
 
 type variableRandomSeries struct {
@@ -14,9 +36,10 @@ type variableRandomSeries struct {
 
 	value, gates dsp.Frame
 	lastClock    dsp.Float64
+	rand         *randSource
 }
 
-func newVariableRandomSeries() (*variableRandomSeries, error) {
+func newVariableRandomSeries(seed int64) (*variableRandomSeries, error) {
 	m := &variableRandomSeries{
 		clock:      NewInBuffer("clock", dsp.Float64(0)),
 		size:       NewInBuffer("size", dsp.Float64(8)),
@@ -29,7 +52,7 @@ func newVariableRandomSeries() (*variableRandomSeries, error) {
 		gates:      dsp.NewFrame(),
 	}
 
-	return m, m.Expose(
+	err := m.Expose(
 		"VariableRandomSeries",
 		[]*In{m.size, m.random, m.clock, m.min, m.max},
 		[]*Out{
@@ -37,6 +60,8 @@ func newVariableRandomSeries() (*variableRandomSeries, error) {
 			{Name: "gate", Provider: provideCopyOut(m, &m.gates)},
 		},
 	)
+	m.rand = newRand(m.ID(), seed)
+	return m, err
 }
 
 func (s *variableRandomSeries) Process(out dsp.Frame) {
@@ -53,9 +78,9 @@ func (s *variableRandomSeries) Process(out dsp.Frame) {
 			size := dsp.Clamp(size[i], 1, randomSeriesMax)
 
 			if s.lastClock < 0 && clock[i] > 0 {
-				if r := random[i]; r != 0 && (r == 1 || dsp.Rand() > 1-r) {
-					s.memory[s.idx] = dsp.Rand()*(max[i]-min[i]) + min[i]
-					if dsp.Rand() > 0.25 {
+				if r := random[i]; r != 0 && (r == 1 || s.rand.Value() > 1-r) {
+					s.memory[s.idx] = s.rand.Range(min[i], max[i])
+					if s.rand.Value() > 0.25 {
 						s.gateMemory[s.idx] = 1
 					} else {
 						s.gateMemory[s.idx] = -1
@@ -19,6 +19,18 @@ func init() {
 		}
 		return newWavetable(config.Table)
 	})
+	Describe("Wavetable", Metadata{
+		Description: "Oscillator reading its waveform from a table",
+		Config: []ConfigMetadata{
+			{Name: "table", Type: "string", Default: "sine", Description: "waveform: gap, saw, sine, square or triangle"},
+		},
+		Inputs: []PortMetadata{
+			{Name: "pitch", Default: 0, Description: "frequency"},
+			{Name: "amp", Default: 1, Description: "level of the waveform"},
+			{Name: "offset", Default: 0, Description: "added to the waveform"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type wavetable struct {
@@ -8,6 +8,14 @@ import (
 
 func init() {
 	Register("Wrap", func(Config) (Patcher, error) { return newWrap() })
+	Describe("Wrap", Metadata{
+		Description: "Wraps its input around to the opposite side when it goes past a level",
+		Inputs: []PortMetadata{
+			{Name: "input", Description: "signal to wrap"},
+			{Name: "level", Default: 1, Description: "level the signal wraps at"},
+		},
+		Outputs: []PortMetadata{{Name: "output"}},
+	})
 }
 
 type wrap struct {
@@ -0,0 +1,162 @@
+package pattern
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"buddin.us/eolian/dsp"
+)
+
+// Parse reads a pattern written in mini-notation
+func Parse(s string) (Pattern, error) {
+	p := &parser{src: []rune(s)}
+	seq, err := p.sequence(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(seq) == 0 {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	return parsed{root: seq}, nil
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("pattern: %s at column %d", fmt.Sprintf(format, args...), p.pos+1)
+}
+
+func (p *parser) peek() rune {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// sequence reads steps until the closing rune, or the end of the pattern when close is zero
+func (p *parser) sequence(close rune) (sequence, error) {
+	var seq sequence
+	for {
+		p.skipSpace()
+		switch r := p.peek(); {
+		case r == close:
+			if close != 0 {
+				p.pos++
+			}
+			return seq, nil
+		case r == 0:
+			return nil, p.errorf("missing %q", close)
+		case r == ']' || r == '>':
+			return nil, p.errorf("unexpected %q", r)
+		}
+
+		n, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		copies := 1
+	modifiers:
+		for {
+			switch p.peek() {
+			case '*':
+				p.pos++
+				times, err := p.count()
+				if err != nil {
+					return nil, err
+				}
+				n = repeat{node: n, times: times}
+			case '!':
+				p.pos++
+				if copies, err = p.count(); err != nil {
+					return nil, err
+				}
+			default:
+				break modifiers
+			}
+		}
+		for i := 0; i < copies; i++ {
+			seq = append(seq, n)
+		}
+	}
+}
+
+func (p *parser) term() (node, error) {
+	switch p.peek() {
+	case '[':
+		p.pos++
+		seq, err := p.sequence(']')
+		if err != nil {
+			return nil, err
+		}
+		if len(seq) == 0 {
+			return nil, p.errorf("empty group")
+		}
+		return group{seq}, nil
+	case '<':
+		p.pos++
+		seq, err := p.sequence('>')
+		if err != nil {
+			return nil, err
+		}
+		if len(seq) == 0 {
+			return nil, p.errorf("empty alternation")
+		}
+		return alternation(seq), nil
+	case '~':
+		p.pos++
+		return rest{}, nil
+	}
+	return p.atom()
+}
+
+func (p *parser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) && !unicode.IsSpace(p.src[p.pos]) && !strings.ContainsRune("[]<>~*!:", p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *parser) atom() (node, error) {
+	start := p.pos
+	raw := p.word()
+	if raw == "" {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	// Pitch names may be written in lowercase
+	value, err := dsp.ParseValueString(strings.ToUpper(raw[:1]) + raw[1:])
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid value %q", raw)
+	}
+
+	a := atom{raw: raw, value: value, velocity: 1}
+	if p.peek() == ':' {
+		p.pos++
+		v, err := strconv.ParseFloat(p.word(), 64)
+		if err != nil || v < 0 || v > 1 {
+			return nil, p.errorf("velocity must be between 0 and 1")
+		}
+		a.velocity = v
+	}
+	return a, nil
+}
+
+func (p *parser) count() (int, error) {
+	n, err := strconv.Atoi(p.word())
+	if err != nil || n < 1 {
+		return 0, p.errorf("expected a positive count")
+	}
+	return n, nil
+}
@@ -0,0 +1,148 @@
+// Package pattern provides a mini-notation for sequences. A pattern describes what happens in a cycle: its steps share
+// the cycle equally and can be subdivided, repeated or alternated between cycles.
+//
+//	c3 e3 g3 ~       four steps, the last a rest
+//	c3 [e3 g3]       two steps, the second split in two
+//	c3*2 e3          c3 twice in the first half of the cycle
+//	c3!3 e3          c3 for three steps out of four
+//	<c3 e3> g3       c3 in even cycles and e3 in odd ones
+//	c3:0.5 e3        c3 at half velocity
+//
+// Values are pitches in scientific notation, in either case, or plain numbers.
+package pattern
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"buddin.us/eolian/dsp"
+)
+
+// Event is a value played for part of a cycle. Start and End are fractions of the cycle.
+type Event struct {
+	Start, End float64
+	Value      dsp.Valuer
+	Velocity   float64
+}
+
+// Pattern yields the events of each cycle
+type Pattern interface {
+	// Cycle returns the events of a cycle, ordered by their start
+	Cycle(n int) []Event
+	String() string
+}
+
+// node is a part of a parsed pattern that fills a span of a cycle
+type node interface {
+	query(cycle int, start, end float64, events []Event) []Event
+	String() string
+}
+
+type parsed struct {
+	root node
+}
+
+func (p parsed) Cycle(n int) []Event {
+	return p.root.query(n, 0, 1, nil)
+}
+
+func (p parsed) String() string {
+	return p.root.String()
+}
+
+// sequence divides its span equally between its steps
+type sequence []node
+
+func (s sequence) query(cycle int, start, end float64, events []Event) []Event {
+	step := (end - start) / float64(len(s))
+	for i, n := range s {
+		events = n.query(cycle, start+float64(i)*step, start+float64(i+1)*step, events)
+	}
+	return events
+}
+
+func (s sequence) String() string {
+	return joinNodes(s)
+}
+
+// alternation plays one of its steps per cycle, in turn
+type alternation []node
+
+func (a alternation) query(cycle int, start, end float64, events []Event) []Event {
+	i := mod(cycle, len(a))
+	return a[i].query(floorDiv(cycle, len(a)), start, end, events)
+}
+
+func (a alternation) String() string {
+	return "<" + joinNodes(a) + ">"
+}
+
+// repeat plays its node several times within its span, as if that many cycles went by
+type repeat struct {
+	node  node
+	times int
+}
+
+func (r repeat) query(cycle int, start, end float64, events []Event) []Event {
+	step := (end - start) / float64(r.times)
+	for i := 0; i < r.times; i++ {
+		events = r.node.query(cycle*r.times+i, start+float64(i)*step, start+float64(i+1)*step, events)
+	}
+	return events
+}
+
+func (r repeat) String() string {
+	return fmt.Sprintf("%s*%d", r.node, r.times)
+}
+
+// group is a bracketed sequence
+type group struct {
+	sequence
+}
+
+func (g group) String() string {
+	return "[" + g.sequence.String() + "]"
+}
+
+type atom struct {
+	raw      string
+	value    dsp.Valuer
+	velocity float64
+}
+
+func (a atom) query(_ int, start, end float64, events []Event) []Event {
+	return append(events, Event{Start: start, End: end, Value: a.value, Velocity: a.velocity})
+}
+
+func (a atom) String() string {
+	if a.velocity != 1 {
+		return fmt.Sprintf("%s:%g", a.raw, a.velocity)
+	}
+	return a.raw
+}
+
+type rest struct{}
+
+func (rest) query(_ int, _, _ float64, events []Event) []Event { return events }
+func (rest) String() string                                    { return "~" }
+
+func joinNodes(nodes []node) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
+
+func floorDiv(a, b int) int {
+	return (a - mod(a, b)) / b
+}
+
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start < events[j].Start })
+}
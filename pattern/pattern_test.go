@@ -0,0 +1,79 @@
+package pattern
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/go-playground/assert.v1"
+)
+
+// describe renders the events of a cycle as "value@start-end"
+func describe(events []Event) string {
+	parts := []string{}
+	for _, e := range events {
+		part := fmt.Sprintf("%s@%g-%g", strings.ToLower(fmt.Sprint(e.Value)), e.Start, e.End)
+		if e.Velocity != 1 {
+			part += fmt.Sprintf(":%g", e.Velocity)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		src    string
+		cycles []string
+	}{
+		{"c3 e3 ~ g3", []string{"c3@0-0.25 e3@0.25-0.5 g3@0.75-1"}},
+		{"c3 [e3 g3]", []string{"c3@0-0.5 e3@0.5-0.75 g3@0.75-1"}},
+		{"c3*2 e3", []string{"c3@0-0.25 c3@0.25-0.5 e3@0.5-1"}},
+		{"c3!3 e3", []string{"c3@0-0.25 c3@0.25-0.5 c3@0.5-0.75 e3@0.75-1"}},
+		{"<c3 e3> g3", []string{"c3@0-0.5 g3@0.5-1", "e3@0-0.5 g3@0.5-1"}},
+		{"<c3 <e3 g3>>", []string{"c3@0-1", "e3@0-1", "c3@0-1", "g3@0-1"}},
+		{"<c3 e3>*2", []string{"c3@0-0.5 e3@0.5-1"}},
+		{"c3:0.5 1", []string{"c3@0-0.5:0.5 1.00@0.5-1"}},
+	}
+	for _, test := range tests {
+		p, err := Parse(test.src)
+		assert.Equal(t, err, nil)
+		for i, expected := range test.cycles {
+			assert.Equal(t, describe(p.Cycle(i)), expected)
+		}
+		// Replicated steps are written out
+		assert.Equal(t, p.String(), strings.Replace(test.src, "c3!3", "c3 c3 c3", 1))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{"", "[c3", "c3]", "<>", "c3*0", "c3:2", "h9", "c3 *"} {
+		_, err := Parse(src)
+		assert.NotEqual(t, err, nil)
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	p, err := Parse("c3 e3 g3 b3")
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, describe(Reverse(p).Cycle(0)), "b3@0-0.25 g3@0.25-0.5 e3@0.5-0.75 c3@0.75-1")
+	assert.Equal(t, describe(Rotate(p, 0.25).Cycle(0)), "e3@0-0.25 g3@0.25-0.5 b3@0.5-0.75 c3@0.75-1")
+	assert.Equal(t, describe(Rotate(p, -0.25).Cycle(0)), "b3@0-0.25 c3@0.25-0.5 e3@0.5-0.75 g3@0.75-1")
+
+	everyOther := Every(p, 2, Reverse(p))
+	assert.Equal(t, describe(everyOther.Cycle(0)), describe(Reverse(p).Cycle(0)))
+	assert.Equal(t, describe(everyOther.Cycle(1)), describe(p.Cycle(1)))
+	assert.Equal(t, everyOther.String(), "every(c3 e3 g3 b3, 2, rev(c3 e3 g3 b3))")
+
+	// Degrading drops the same events each time a cycle is played
+	dense, err := Parse("c3*64")
+	assert.Equal(t, err, nil)
+	degraded := Degrade(dense, 0.5, 1)
+	kept := len(degraded.Cycle(0))
+	assert.Equal(t, kept > 16 && kept < 48, true)
+	assert.Equal(t, describe(degraded.Cycle(0)), describe(degraded.Cycle(0)))
+	assert.NotEqual(t, describe(degraded.Cycle(0)), describe(degraded.Cycle(1)))
+	assert.Equal(t, len(Degrade(dense, 0, 1).Cycle(0)), 64)
+	assert.Equal(t, len(Degrade(dense, 1, 1).Cycle(0)), 0)
+}
@@ -0,0 +1,128 @@
+package pattern
+
+import (
+	"fmt"
+	"math"
+)
+
+type reversed struct {
+	Pattern
+}
+
+// Reverse plays each cycle of a pattern backwards
+func Reverse(p Pattern) Pattern {
+	return reversed{p}
+}
+
+func (r reversed) Cycle(n int) []Event {
+	events := r.Pattern.Cycle(n)
+	for i, e := range events {
+		events[i].Start, events[i].End = 1-e.End, 1-e.Start
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events
+}
+
+func (r reversed) String() string {
+	return fmt.Sprintf("rev(%s)", r.Pattern)
+}
+
+type rotated struct {
+	Pattern
+	amount float64
+}
+
+// Rotate shifts a pattern earlier by a fraction of a cycle, wrapping the events that fall off the start of one cycle
+// into the end of the previous one. Negative amounts shift it later.
+func Rotate(p Pattern, amount float64) Pattern {
+	return rotated{p, amount}
+}
+
+func (r rotated) Cycle(n int) []Event {
+	var (
+		offset = float64(n) + r.amount
+		first  = math.Floor(offset)
+		shift  = offset - first
+		events []Event
+	)
+	for _, e := range r.Pattern.Cycle(int(first)) {
+		if e.Start >= shift {
+			e.Start, e.End = e.Start-shift, e.End-shift
+			events = append(events, e)
+		}
+	}
+	for _, e := range r.Pattern.Cycle(int(first) + 1) {
+		if e.Start < shift {
+			e.Start, e.End = e.Start-shift+1, e.End-shift+1
+			events = append(events, e)
+		}
+	}
+	sortEvents(events)
+	return events
+}
+
+func (r rotated) String() string {
+	return fmt.Sprintf("rotate(%s, %g)", r.Pattern, r.amount)
+}
+
+type degraded struct {
+	Pattern
+	probability float64
+	seed        int64
+}
+
+// Degrade drops events at random with a probability. The same events are dropped for a seed each time a cycle is
+// played.
+func Degrade(p Pattern, probability float64, seed int64) Pattern {
+	return degraded{p, probability, seed}
+}
+
+func (d degraded) Cycle(n int) []Event {
+	events := d.Pattern.Cycle(n)
+	kept := events[:0]
+	for i, e := range events {
+		if chance(d.seed, n, i) >= d.probability {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func (d degraded) String() string {
+	return fmt.Sprintf("degrade(%s, %g)", d.Pattern, d.probability)
+}
+
+// chance hashes a seed, cycle and event index to a number in [0, 1)
+func chance(seed int64, cycle, index int) float64 {
+	x := uint64(seed) ^ uint64(cycle)*0x9e3779b97f4a7c15 ^ uint64(index)*0xc2b2ae3d27d4eb4f
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / (1 << 53)
+}
+
+type every struct {
+	Pattern
+	n           int
+	transformed Pattern
+}
+
+// Every plays a transformed pattern instead of the original every nth cycle, starting with the first
+func Every(p Pattern, n int, transformed Pattern) Pattern {
+	return every{p, n, transformed}
+}
+
+func (e every) Cycle(n int) []Event {
+	if e.n > 0 && mod(n, e.n) == 0 {
+		return e.transformed.Cycle(n)
+	}
+	return e.Pattern.Cycle(n)
+}
+
+func (e every) String() string {
+	return fmt.Sprintf("every(%s, %d, %s)", e.Pattern, e.n, e.transformed)
+}
@@ -1,4 +1,4 @@
-// Package wav provides WAV file decoding
+// Package wav provides WAV file decoding and encoding
 package wav
 
 import (
@@ -175,3 +175,51 @@ type LimitReadCloser struct {
 func (lrc *LimitReadCloser) Close() error {
 	return lrc.c.Close()
 }
+
+// Write encodes mono samples as a 32-bit IEEE float WAV file
+func Write(w io.Writer, sampleRate int, samples []float32) error {
+	return WriteInterleaved(w, sampleRate, 1, samples)
+}
+
+// WriteInterleaved encodes interleaved samples of a number of channels as a 32-bit IEEE float WAV file
+func WriteInterleaved(w io.Writer, sampleRate, channels int, samples []float32) error {
+	const bytesPerSample = 4
+	var (
+		dataSize = uint32(len(samples) * bytesPerSample)
+		header   = Header{
+			AudioFormat:    formatIEEEFloat,
+			NumChannels:    uint16(channels),
+			SampleRate:     uint32(sampleRate),
+			BytesPerSecond: uint32(sampleRate * channels * bytesPerSample),
+			BytesPerBlock:  uint16(channels * bytesPerSample),
+			BitsPerSample:  bytesPerSample * 8,
+		}
+	)
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(4+8+16+8)+dataSize)
+	buf.WriteString("WAVE")
+	buf.WriteString(preambleFormat)
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, header)
+	buf.WriteString(preambleData)
+	binary.Write(buf, binary.LittleEndian, dataSize)
+	binary.Write(buf, binary.LittleEndian, samples)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteFile encodes mono samples as a 32-bit IEEE float WAV file at a path
+func WriteFile(path string, sampleRate int, samples []float32) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, sampleRate, samples); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
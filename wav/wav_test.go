@@ -1,6 +1,9 @@
 package wav
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	assert "gopkg.in/go-playground/assert.v1"
@@ -17,3 +20,23 @@ func TestFileLoad(t *testing.T) {
 	assert.Equal(t, err, nil)
 	assert.Equal(t, len(samples), 26312)
 }
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eolian-wav")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out.wav")
+	samples := []float32{0, 0.5, -0.5, 1, 0.25, -0.25, 0.75, -1}
+	assert.Equal(t, WriteFile(path, 44100, samples), nil)
+
+	w, err := Open(path)
+	assert.Equal(t, err, nil)
+	defer w.Close()
+	assert.Equal(t, w.SampleRate, uint32(44100))
+	assert.Equal(t, w.Samples, len(samples))
+
+	read, err := w.ReadAll()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, read, samples)
+}